@@ -0,0 +1,235 @@
+// Package apierror 统一的错误模型
+// 每个错误带有稳定的错误码（供客户端判断）、出错的字段名，以及按 Accept-Language 选择的中英文提示。
+package apierror
+
+import (
+	"SDES/dto/response"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Code 机器可读的错误码，发布后不再修改含义
+type Code string
+
+const (
+	InvalidRequest          Code = "INVALID_REQUEST"
+	MissingField            Code = "MISSING_FIELD"
+	InvalidType             Code = "INVALID_TYPE"
+	InvalidValue            Code = "INVALID_VALUE"
+	UnsupportedAlgorithm    Code = "UNSUPPORTED_ALGORITHM"
+	InvalidBinary           Code = "INVALID_BINARY"
+	InvalidKeyLength        Code = "INVALID_KEY_LENGTH"
+	InvalidBlockLength      Code = "INVALID_BLOCK_LENGTH"
+	InvalidASCII            Code = "INVALID_ASCII"
+	InvalidHex              Code = "INVALID_HEX"
+	InvalidDataLength       Code = "INVALID_DATA_LENGTH"
+	EmptyInput              Code = "EMPTY_INPUT"
+	Base64DecodeFailed      Code = "BASE64_DECODE_FAILED"
+	InvalidCiphertextLength Code = "INVALID_CIPHERTEXT_LENGTH"
+	InvalidPadding          Code = "INVALID_PADDING"
+	InvalidRounds           Code = "INVALID_ROUNDS"
+	AuthUnsupported         Code = "AUTH_UNSUPPORTED"
+	TagMismatch             Code = "TAG_MISMATCH"
+	KeyGenerationFailed     Code = "KEY_GENERATION_FAILED"
+	NoKeyFound              Code = "NO_KEY_FOUND"
+	BruteForceBusy          Code = "BRUTE_FORCE_BUSY"
+	ShuttingDown            Code = "SHUTTING_DOWN"
+	RequestCancelled        Code = "REQUEST_CANCELLED"
+	RateLimited             Code = "RATE_LIMITED"
+	BodyTooLarge            Code = "BODY_TOO_LARGE"
+	InvalidBody             Code = "INVALID_BODY"
+	PlaintextTooLong        Code = "PLAINTEXT_TOO_LONG"
+	AuthRequired            Code = "AUTH_REQUIRED"
+	InvalidCredentials      Code = "INVALID_CREDENTIALS"
+	TokenExpired            Code = "TOKEN_EXPIRED"
+	InsufficientScope       Code = "INSUFFICIENT_SCOPE"
+	OracleSessionNotFound   Code = "ORACLE_SESSION_NOT_FOUND"
+	OracleSessionsFull      Code = "ORACLE_SESSIONS_FULL"
+	OracleBudgetExhausted   Code = "ORACLE_BUDGET_EXHAUSTED"
+	OracleAttemptsExhausted Code = "ORACLE_ATTEMPTS_EXHAUSTED"
+	OracleAlreadySolved     Code = "ORACLE_ALREADY_SOLVED"
+	RandomUnavailable       Code = "RANDOM_UNAVAILABLE"
+	NoCollisionFound        Code = "NO_COLLISION_FOUND"
+	InvalidClassicalKey     Code = "INVALID_CLASSICAL_KEY"
+	OddLetterCount          Code = "ODD_LETTER_COUNT"
+	CiphertextTooShort      Code = "CIPHERTEXT_TOO_SHORT"
+	CrackFailed             Code = "CRACK_FAILED"
+	InvalidExerciseID       Code = "INVALID_EXERCISE_ID"
+	HistoryDisabled         Code = "HISTORY_DISABLED"
+	HistoryStorageFailed    Code = "HISTORY_STORAGE_FAILED"
+	UnsupportedFormat       Code = "UNSUPPORTED_FORMAT"
+)
+
+// Lang 提示语言
+type Lang string
+
+const (
+	ZhCN Lang = "zh-CN"
+	En   Lang = "en"
+)
+
+// DefaultLang 未指定或无法识别 Accept-Language 时使用的语言
+const DefaultLang = ZhCN
+
+// messages 各错误码的提示模板，参数依次为 Error.Args
+var messages = map[Code]map[Lang]string{
+	InvalidRequest:          {ZhCN: "无效的请求格式", En: "malformed request body"},
+	MissingField:            {ZhCN: "必须提供 %s", En: "%s is required"},
+	InvalidType:             {ZhCN: "%s 的类型应为 %s", En: "%s must be of type %s"},
+	InvalidValue:            {ZhCN: "%s 的取值不符合要求（%s %s）", En: "%s is invalid (%s %s)"},
+	UnsupportedAlgorithm:    {ZhCN: "不支持的算法: %s", En: "unsupported algorithm: %s"},
+	InvalidBinary:           {ZhCN: "%s 只能包含 0 和 1", En: "%s must contain only 0 and 1"},
+	InvalidKeyLength:        {ZhCN: "%s 必须是 %d 位二进制字符串", En: "%s must be a %d-bit binary string"},
+	InvalidBlockLength:      {ZhCN: "%s 必须是 %d 位二进制字符串", En: "%s must be a %d-bit binary string"},
+	InvalidASCII:            {ZhCN: "%s 中的字符 %q 超出 ASCII 范围", En: "character %[2]q in %[1]s is outside the ASCII range"},
+	InvalidHex:              {ZhCN: "%s 不是有效的十六进制字符串", En: "%s is not a valid hex string"},
+	InvalidDataLength:       {ZhCN: "%s 的长度必须是 %d 位的整数倍", En: "%s length must be a multiple of %d bits"},
+	EmptyInput:              {ZhCN: "%s 不能为空", En: "%s must not be empty"},
+	Base64DecodeFailed:      {ZhCN: "%s 不是有效的 Base64", En: "%s is not valid Base64"},
+	InvalidCiphertextLength: {ZhCN: "%s 解码后的长度必须是 %d 字节的整数倍", En: "decoded %s length must be a multiple of %d bytes"},
+	InvalidPadding:          {ZhCN: "%s 解密后的 PKCS#7 补齐无效，请检查密钥、IV 与 padding", En: "%s has invalid PKCS#7 padding after decryption, check the key, IV and padding"},
+	InvalidRounds:           {ZhCN: "轮数必须在 1~%d 之间", En: "rounds must be between 1 and %d"},
+	AuthUnsupported:         {ZhCN: "认证加密仅支持 S-DES", En: "authenticated encryption is only supported for S-DES"},
+	TagMismatch:             {ZhCN: "消息认证失败：标签不匹配", En: "authentication failed: tag mismatch"},
+	KeyGenerationFailed:     {ZhCN: "随机密钥生成失败", En: "failed to generate a random key"},
+	NoKeyFound:              {ZhCN: "暴力破解失败：未找到匹配的密钥", En: "brute force failed: no matching key found"},
+	BruteForceBusy:          {ZhCN: "暴力破解任务过多，请稍后再试", En: "too many brute-force jobs, please try again later"},
+	ShuttingDown:            {ZhCN: "服务器正在关闭，暴力破解已取消", En: "server is shutting down, brute force cancelled"},
+	RequestCancelled:        {ZhCN: "请求已取消", En: "request cancelled"},
+	RateLimited:             {ZhCN: "请求过于频繁，请 %d 秒后重试", En: "too many requests, retry after %d seconds"},
+	BodyTooLarge:            {ZhCN: "请求体不能超过 %d 字节", En: "request body must not exceed %d bytes"},
+	InvalidBody:             {ZhCN: "读取请求体失败", En: "failed to read request body"},
+	PlaintextTooLong:        {ZhCN: "%s 不能超过 %d 字节", En: "%s must not exceed %d bytes"},
+	AuthRequired:            {ZhCN: "该接口需要 %s 权限，请提供 API 密钥或令牌", En: "this endpoint requires the %s scope, please provide an API key or token"},
+	InvalidCredentials:      {ZhCN: "API 密钥或令牌无效", En: "invalid API key or token"},
+	TokenExpired:            {ZhCN: "令牌已过期", En: "token has expired"},
+	InsufficientScope:       {ZhCN: "该接口需要 %s 权限", En: "this endpoint requires the %s scope"},
+	OracleSessionNotFound:   {ZhCN: "会话不存在或已过期", En: "session not found or expired"},
+	OracleSessionsFull:      {ZhCN: "活跃会话过多，请稍后再试", En: "too many active sessions, please try again later"},
+	OracleBudgetExhausted:   {ZhCN: "该会话的查询次数已用完，请提交密钥或创建新会话", En: "the query budget of this session is exhausted, submit a key or start a new session"},
+	OracleAttemptsExhausted: {ZhCN: "该会话的提交次数已用完", En: "no submission attempts left for this session"},
+	OracleAlreadySolved:     {ZhCN: "该会话已成功破解", En: "this session has already been solved"},
+	RandomUnavailable:       {ZhCN: "读取系统随机数失败", En: "failed to read from the system random source"},
+	NoCollisionFound:        {ZhCN: "在 %d 次尝试内未找到碰撞", En: "no collision found within %d trials"},
+	InvalidClassicalKey:     {ZhCN: "密钥不符合 %s 的格式要求", En: "key does not match the format required by %s"},
+	OddLetterCount:          {ZhCN: "%s 要求 %s 中的字母数为偶数", En: "%s requires an even number of letters in %s"},
+	CiphertextTooShort:      {ZhCN: "%s 中的字母太少，无法进行唯密文分析", En: "%s has too few letters for ciphertext-only analysis"},
+	CrackFailed:             {ZhCN: "唯密文分析未找到可行的密钥", En: "ciphertext-only analysis found no feasible key"},
+	InvalidExerciseID:       {ZhCN: "%s 不是本服务签发的有效题目 ID", En: "%s is not a valid exercise ID issued by this server"},
+	HistoryDisabled:         {ZhCN: "历史记录未启用", En: "operation history is disabled"},
+	HistoryStorageFailed:    {ZhCN: "历史记录读写失败", En: "failed to access the history store"},
+	UnsupportedFormat:       {ZhCN: "不支持的导出格式 %s，只能是 csv 或 json", En: "unsupported export format %s, expected csv or json"},
+}
+
+// Error 一个带错误码的 API 错误
+type Error struct {
+	Status int
+	Code   Code
+	Field  string // 出错的请求字段，与请求 JSON 中的字段名一致
+	Args   []any  // 提示模板的参数
+	// RetryAfter 建议的重试等待秒数，仅用于限流等可重试的错误
+	RetryAfter int
+}
+
+// New 创建错误
+func New(status int, code Code, field string, args ...any) *Error {
+	return &Error{Status: status, Code: code, Field: field, Args: args}
+}
+
+// Message 按语言生成提示
+func (e *Error) Message(lang Lang) string {
+	templates, ok := messages[e.Code]
+	if !ok {
+		return string(e.Code)
+	}
+	tmpl, ok := templates[lang]
+	if !ok {
+		tmpl = templates[DefaultLang]
+	}
+	if len(e.Args) == 0 {
+		return tmpl
+	}
+	return fmt.Sprintf(tmpl, e.Args...)
+}
+
+// Error 实现 error 接口，使用默认语言
+func (e *Error) Error() string {
+	return e.Message(DefaultLang)
+}
+
+// Negotiate 按 Accept-Language（含 q 权重）选择支持的语言，en* 对应英文，zh* 对应简体中文
+func Negotiate(acceptLanguage string) Lang {
+	best, bestQ := DefaultLang, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		var lang Lang
+		switch tag = strings.ToLower(tag); {
+		case tag == "en" || strings.HasPrefix(tag, "en-"):
+			lang = En
+		case tag == "zh" || strings.HasPrefix(tag, "zh-"):
+			lang = ZhCN
+		default:
+			continue
+		}
+		if q > bestQ {
+			best, bestQ = lang, q
+		}
+	}
+	return best
+}
+
+// LangOf 返回请求使用的语言
+func LangOf(c *gin.Context) Lang {
+	return Negotiate(c.GetHeader("Accept-Language"))
+}
+
+// Detail 生成响应中的错误详情，并设置 Content-Language 响应头
+func Detail(c *gin.Context, e *Error) *response.ErrorDetail {
+	lang := LangOf(c)
+	c.Header("Content-Language", string(lang))
+	return &response.ErrorDetail{
+		Code:       string(e.Code),
+		Field:      e.Field,
+		Message:    e.Message(lang),
+		RetryAfter: e.RetryAfter,
+	}
+}
+
+// Abort 写入统一的错误响应并中止后续处理
+func Abort(c *gin.Context, e *Error) {
+	detail := Detail(c, e)
+	if e.RetryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(e.RetryAfter))
+	}
+	c.AbortWithStatusJSON(e.Status, response.ErrorResponse{
+		Success: false,
+		Message: detail.Message,
+		Error:   detail,
+	})
+}
+
+// Binary 校验二进制字符串字段：为空时返回 MISSING_FIELD，含 0/1 以外字符时返回 INVALID_BINARY，长度不对时返回 lengthCode
+func Binary(field, value string, bits int, lengthCode Code) *Error {
+	if value == "" {
+		return New(http.StatusBadRequest, MissingField, field, field)
+	}
+	if strings.Trim(value, "01") != "" {
+		return New(http.StatusBadRequest, InvalidBinary, field, field)
+	}
+	if len(value) != bits {
+		return New(http.StatusBadRequest, lengthCode, field, field, bits)
+	}
+	return nil
+}
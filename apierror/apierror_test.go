@@ -0,0 +1,62 @@
+package apierror
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := map[string]Lang{
+		"":                          ZhCN,
+		"en":                        En,
+		"en-US,en;q=0.9":            En,
+		"zh-CN,zh;q=0.9,en;q=0.8":   ZhCN,
+		"fr-FR, en;q=0.5, zh;q=0.7": ZhCN,
+		"fr, EN-GB;q=0.3":           En,
+		"en;q=bad, zh-TW":           ZhCN,
+		"de":                        ZhCN,
+	}
+	for header, want := range cases {
+		if got := Negotiate(header); got != want {
+			t.Errorf("Negotiate(%q) = %s，期望 %s", header, got, want)
+		}
+	}
+}
+
+func TestCatalogComplete(t *testing.T) {
+	for code, templates := range messages {
+		for _, lang := range []Lang{ZhCN, En} {
+			if templates[lang] == "" {
+				t.Errorf("错误码 %s 缺少 %s 提示", code, lang)
+			}
+		}
+		if strings.Count(templates[ZhCN], "%") != strings.Count(templates[En], "%") {
+			t.Errorf("错误码 %s 的中英文提示参数个数不一致", code)
+		}
+	}
+}
+
+func TestBinary(t *testing.T) {
+	cases := []struct {
+		value string
+		code  Code
+	}{
+		{"", MissingField},
+		{"10102", InvalidBinary},
+		{"101", InvalidKeyLength},
+		{"1010000010", ""},
+	}
+	for _, tc := range cases {
+		e := Binary("key", tc.value, 10, InvalidKeyLength)
+		var got Code
+		if e != nil {
+			got = e.Code
+		}
+		if got != tc.code {
+			t.Errorf("Binary(%q) = %q，期望 %q", tc.value, got, tc.code)
+		}
+	}
+	if msg := Binary("key", "101", 10, InvalidKeyLength).Message(En); msg != "key must be a 10-bit binary string" {
+		t.Errorf("英文提示 = %q", msg)
+	}
+}
@@ -0,0 +1,181 @@
+// Package auth 可选的接口认证：静态 API 密钥与 HMAC-SHA256 签名的令牌，二者都带有作用域。
+//
+// 令牌格式为 base64url(JSON 声明) + "." + base64url(HMAC-SHA256(密钥, 前半部分))，
+// 声明包含 sub（主体）、scopes（作用域）、iat（签发时间）与 exp（过期时间，Unix 秒）。
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// 作用域
+const (
+	ScopeEncrypt = "encrypt" // 加解密、MAC、哈希等常规操作
+	ScopeCrack   = "crack"   // 暴力破解与各类攻击演示
+	ScopeAdmin   = "admin"   // 操作历史、指标与签发令牌，拥有全部作用域
+)
+
+// Scopes 全部作用域
+var Scopes = []string{ScopeEncrypt, ScopeCrack, ScopeAdmin}
+
+// PrincipalKey gin.Context 中已认证调用方（*Principal）的键
+const PrincipalKey = "auth.principal"
+
+// APIKeyHeader 传递 API 密钥的请求头，也可以使用 Authorization: Bearer <密钥>
+const APIKeyHeader = "X-API-Key"
+
+var (
+	// ErrInvalidKey API 密钥不存在
+	ErrInvalidKey = errors.New("API 密钥无效")
+	// ErrMalformed 令牌格式错误
+	ErrMalformed = errors.New("令牌格式错误")
+	// ErrSignature 令牌签名不匹配
+	ErrSignature = errors.New("令牌签名无效")
+	// ErrExpired 令牌已过期
+	ErrExpired = errors.New("令牌已过期")
+)
+
+// Key 一个静态 API 密钥，ID 用于日志与限流，不是密钥本身
+type Key struct {
+	ID     string   `yaml:"id" toml:"id"`
+	Key    string   `yaml:"key" toml:"key"`
+	Scopes []string `yaml:"scopes" toml:"scopes"`
+}
+
+// Claims 令牌声明
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Scopes    []string `json:"scopes"`
+	IssuedAt  int64    `json:"iat"`
+	ExpiresAt int64    `json:"exp"`
+}
+
+// Principal 已认证的调用方
+type Principal struct {
+	// ID 形如 key:<密钥 ID> 或 token:<主体>
+	ID     string
+	Scopes []string
+}
+
+// Has 是否拥有作用域，admin 拥有全部作用域
+func (p *Principal) Has(scope string) bool {
+	return p != nil && (slices.Contains(p.Scopes, scope) || slices.Contains(p.Scopes, ScopeAdmin))
+}
+
+var encoding = base64.RawURLEncoding
+
+// Sign 签发令牌
+func Sign(secret []byte, c Claims) string {
+	payload, _ := json.Marshal(c)
+	head := encoding.EncodeToString(payload)
+	return head + "." + encoding.EncodeToString(mac(secret, head))
+}
+
+// Verify 校验令牌签名与有效期，返回其中的声明
+func Verify(secret []byte, token string, now time.Time) (*Claims, error) {
+	head, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrMalformed
+	}
+	got, err := encoding.DecodeString(sig)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	if !hmac.Equal(got, mac(secret, head)) {
+		return nil, ErrSignature
+	}
+	payload, err := encoding.DecodeString(head)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	var c Claims
+	if err := json.Unmarshal(payload, &c); err != nil || c.Subject == "" {
+		return nil, ErrMalformed
+	}
+	if now.Unix() >= c.ExpiresAt {
+		return nil, ErrExpired
+	}
+	return &c, nil
+}
+
+func mac(secret []byte, data string) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// Authenticator 按配置的 API 密钥与令牌密钥识别调用方
+type Authenticator struct {
+	// keys 以密钥的 SHA-256 为索引，查找耗时与密钥内容无关
+	keys   map[[sha256.Size]byte]*Principal
+	secret []byte
+	public []string
+	now    func() time.Time
+}
+
+// New 创建认证器；secret 为空时不接受令牌，public 为未认证请求也拥有的作用域
+func New(keys []Key, secret string, public []string) *Authenticator {
+	a := &Authenticator{
+		keys:   make(map[[sha256.Size]byte]*Principal, len(keys)),
+		public: public,
+		now:    time.Now,
+	}
+	if secret != "" {
+		a.secret = []byte(secret)
+	}
+	for _, k := range keys {
+		a.keys[sha256.Sum256([]byte(k.Key))] = &Principal{ID: "key:" + k.ID, Scopes: k.Scopes}
+	}
+	return a
+}
+
+// Tokens 是否可以签发与校验令牌
+func (a *Authenticator) Tokens() bool {
+	return a.secret != nil
+}
+
+// Issue 签发有效期为 ttl 的令牌
+func (a *Authenticator) Issue(subject string, scopes []string, ttl time.Duration) (string, *Claims) {
+	now := a.now()
+	c := Claims{Subject: subject, Scopes: scopes, IssuedAt: now.Unix(), ExpiresAt: now.Add(ttl).Unix()}
+	return Sign(a.secret, c), &c
+}
+
+// Authenticate 从请求头中识别调用方
+// 未提供凭据时返回 nil, nil（匿名）；凭据无效时返回错误
+// Authorization: Bearer 的值含 "." 且配置了令牌密钥时按令牌校验，否则视为 API 密钥
+func (a *Authenticator) Authenticate(r *http.Request) (*Principal, error) {
+	credential := r.Header.Get(APIKeyHeader)
+	if credential == "" {
+		scheme, value, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			return nil, nil
+		}
+		credential = strings.TrimSpace(value)
+		if a.secret != nil && strings.Contains(credential, ".") {
+			c, err := Verify(a.secret, credential, a.now())
+			if err != nil {
+				return nil, err
+			}
+			return &Principal{ID: "token:" + c.Subject, Scopes: c.Scopes}, nil
+		}
+	}
+	p, ok := a.keys[sha256.Sum256([]byte(credential))]
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+	return p, nil
+}
+
+// Allowed 调用方（可为 nil，即匿名）是否可以使用需要 scope 的接口
+func (a *Authenticator) Allowed(p *Principal, scope string) bool {
+	return scope == "" || slices.Contains(a.public, scope) || p.Has(scope)
+}
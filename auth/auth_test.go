@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var secret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestTokens(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0)
+	token := Sign(secret, Claims{Subject: "alice", Scopes: []string{ScopeCrack}, ExpiresAt: now.Add(time.Hour).Unix()})
+
+	c, err := Verify(secret, token, now)
+	if err != nil || c.Subject != "alice" || c.Scopes[0] != ScopeCrack {
+		t.Fatalf("Verify = %+v, %v", c, err)
+	}
+	if _, err := Verify(secret, token, now.Add(time.Hour)); !errors.Is(err, ErrExpired) {
+		t.Errorf("过期令牌: %v", err)
+	}
+	if _, err := Verify([]byte("another secret, 32 bytes long..."), token, now); !errors.Is(err, ErrSignature) {
+		t.Errorf("其他密钥签名的令牌: %v", err)
+	}
+	// 篡改声明后签名不再匹配
+	head, sig, _ := strings.Cut(token, ".")
+	forged := Sign([]byte("x"), Claims{Subject: "alice", Scopes: []string{ScopeAdmin}, ExpiresAt: now.Add(time.Hour).Unix()})
+	forgedHead, _, _ := strings.Cut(forged, ".")
+	if _, err := Verify(secret, forgedHead+"."+sig, now); !errors.Is(err, ErrSignature) {
+		t.Errorf("篡改的令牌: %v", err)
+	}
+	for _, bad := range []string{"", "abc", head + ".!!"} {
+		if _, err := Verify(secret, bad, now); err == nil {
+			t.Errorf("Verify(%q) 应失败", bad)
+		}
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	a := New([]Key{
+		{ID: "student", Key: "student-key-000000", Scopes: []string{ScopeEncrypt}},
+		{ID: "teacher", Key: "teacher-key-000000", Scopes: []string{ScopeAdmin}},
+	}, string(secret), []string{ScopeEncrypt})
+	token, _ := a.Issue("bob", []string{ScopeCrack}, time.Hour)
+
+	cases := []struct {
+		header, value string
+		id            string
+		err           error
+	}{
+		{"", "", "", nil},
+		{"X-API-Key", "student-key-000000", "key:student", nil},
+		{"Authorization", "Bearer teacher-key-000000", "key:teacher", nil},
+		{"Authorization", "bearer " + token, "token:bob", nil},
+		{"Authorization", "Basic dXNlcjpwYXNz", "", nil},
+		{"X-API-Key", "wrong-key-00000000", "", ErrInvalidKey},
+		{"Authorization", "Bearer " + token + "x", "", ErrSignature},
+	}
+	for _, tc := range cases {
+		r := httptest.NewRequest("GET", "/", nil)
+		if tc.header != "" {
+			r.Header.Set(tc.header, tc.value)
+		}
+		p, err := a.Authenticate(r)
+		var id string
+		if p != nil {
+			id = p.ID
+		}
+		if id != tc.id || !errors.Is(err, tc.err) {
+			t.Errorf("%s: %s → %q, %v", tc.header, tc.value, id, err)
+		}
+	}
+
+	teacher := &Principal{ID: "key:teacher", Scopes: []string{ScopeAdmin}}
+	bob := &Principal{ID: "token:bob", Scopes: []string{ScopeCrack}}
+	if !a.Allowed(nil, ScopeEncrypt) || a.Allowed(nil, ScopeCrack) || !a.Allowed(nil, "") {
+		t.Error("匿名请求只应拥有公开作用域")
+	}
+	if !a.Allowed(teacher, ScopeCrack) || !a.Allowed(bob, ScopeCrack) || a.Allowed(bob, ScopeAdmin) {
+		t.Error("作用域判断错误")
+	}
+}
@@ -0,0 +1,606 @@
+// Package config 服务端配置
+// 优先级从低到高：默认值 < 配置文件（YAML 或 TOML）< SDES_* 环境变量 < 命令行参数。
+package config
+
+import (
+	"SDES/auth"
+	"SDES/logging"
+	"SDES/ratelimit"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Duration 可在配置文件中写成 "30m"、"1h" 的时长
+type Duration time.Duration
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalText 实现 encoding.TextMarshaler
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Features 可单独关闭的功能模块，关闭后对应接口不注册（返回 404）
+type Features struct {
+	Blasting  bool `yaml:"blasting" toml:"blasting"`   // 暴力破解与 TMTO
+	Analysis  bool `yaml:"analysis" toml:"analysis"`   // 多轮分析、相关密钥、密钥扩展
+	Oracle    bool `yaml:"oracle" toml:"oracle"`       // 预言机挑战
+	Exercises bool `yaml:"exercises" toml:"exercises"` // 练习题
+	Classical bool `yaml:"classical" toml:"classical"` // 古典密码
+	Metrics   bool `yaml:"metrics" toml:"metrics"`     // /metrics 指标接口
+}
+
+// History 操作历史配置
+type History struct {
+	Enabled bool   `yaml:"enabled" toml:"enabled"`
+	File    string `yaml:"file" toml:"file"`
+	// Redact 不保存密钥，默认开启
+	Redact bool `yaml:"redact" toml:"redact"`
+	// Public 未启用认证时也注册 /history 接口；默认只在启用认证（需要 admin 作用域）时注册
+	Public bool `yaml:"public" toml:"public"`
+}
+
+// Auth 认证配置，API 密钥与令牌密钥只能在配置文件中设置
+type Auth struct {
+	// Enabled 为 false 时不做认证，所有接口公开
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// Public 未认证的请求也拥有的作用域，如 [encrypt] 表示公开加解密
+	Public []string `yaml:"public" toml:"public"`
+	// APIKeys 静态 API 密钥
+	APIKeys []auth.Key `yaml:"api_keys" toml:"api_keys"`
+	// TokenSecret 签发与校验令牌的 HMAC 密钥，为空时不接受令牌
+	TokenSecret string `yaml:"token_secret" toml:"token_secret"`
+	// TokenTTL 签发令牌的默认有效期
+	TokenTTL Duration `yaml:"token_ttl" toml:"token_ttl"`
+}
+
+// minTokenSecret 令牌密钥的最短长度（字节）
+const minTokenSecret = 32
+
+// Config 服务端配置
+type Config struct {
+	Addr string `yaml:"addr" toml:"addr"`
+	// Mode Gin 运行模式：debug、release 或 test
+	Mode string `yaml:"mode" toml:"mode"`
+	// CORSOrigins 允许跨域访问的来源，包含 "*" 时允许任意来源，为空时不发送 CORS 响应头
+	CORSOrigins []string `yaml:"cors_origins" toml:"cors_origins"`
+	// TrustedProxies 可信反向代理的 IP 或 CIDR，只有来自这些地址的请求才采用 X-Forwarded-For 中的客户端 IP；
+	// 为空时一律使用连接的对端地址，客户端无法通过伪造请求头绕过按 IP 的限流
+	TrustedProxies []string `yaml:"trusted_proxies" toml:"trusted_proxies"`
+	// TLSCert、TLSKey 同时设置时启用 HTTPS
+	TLSCert string `yaml:"tls_cert" toml:"tls_cert"`
+	TLSKey  string `yaml:"tls_key" toml:"tls_key"`
+	// MaxBodyBytes 请求体大小上限
+	MaxBodyBytes int64 `yaml:"max_body_bytes" toml:"max_body_bytes"`
+	// MaxPlaintextBytes ASCII 明文、Base64 解码后的密文及各类文本输入的长度上限
+	MaxPlaintextBytes int `yaml:"max_plaintext_bytes" toml:"max_plaintext_bytes"`
+	// RateLimits 按路由模板（如 /api/v1/crack）设置的令牌桶预算，default 为其余路由共用的预算；为空时不限流
+	// 已弃用的旧路径与对应的 v1 路径共用预算
+	RateLimits map[string]ratelimit.Budget `yaml:"rate_limits" toml:"rate_limits"`
+	// BruteForceWorkers 逐密钥穷举时使用的协程数；MaxBruteForceJobs 同时进行的暴力破解请求上限
+	BruteForceWorkers int `yaml:"brute_force_workers" toml:"brute_force_workers"`
+	MaxBruteForceJobs int `yaml:"max_brute_force_jobs" toml:"max_brute_force_jobs"`
+	// 日志级别（debug、info、warn、error）与格式（text、json）
+	LogLevel  string `yaml:"log_level" toml:"log_level"`
+	LogFormat string `yaml:"log_format" toml:"log_format"`
+	// LogSecrets 在日志中输出密钥与明密文，仅在 debug 级别下允许开启
+	LogSecrets bool `yaml:"log_secrets" toml:"log_secrets"`
+	// 服务器超时设置
+	ReadTimeout     Duration `yaml:"read_timeout" toml:"read_timeout"`
+	WriteTimeout    Duration `yaml:"write_timeout" toml:"write_timeout"`
+	IdleTimeout     Duration `yaml:"idle_timeout" toml:"idle_timeout"`
+	ShutdownTimeout Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+	// StaticDir 非空时从磁盘提供前端文件（前端开发用）
+	StaticDir string `yaml:"static_dir" toml:"static_dir"`
+	// Codebook 完整密码本持久化路径，为空时只在内存中构建
+	Codebook  string   `yaml:"codebook" toml:"codebook"`
+	OracleTTL Duration `yaml:"oracle_ttl" toml:"oracle_ttl"`
+	History   History  `yaml:"history" toml:"history"`
+	Features  Features `yaml:"features" toml:"features"`
+	Auth      Auth     `yaml:"auth" toml:"auth"`
+}
+
+// Default 返回默认配置，与引入配置前的行为一致
+func Default() *Config {
+	return &Config{
+		Addr:              ":8080",
+		Mode:              "debug",
+		CORSOrigins:       []string{"*"},
+		MaxBodyBytes:      1 << 20,
+		MaxPlaintextBytes: 4096,
+		RateLimits: map[string]ratelimit.Budget{
+			ratelimit.DefaultRoute:    {Rate: 20, Burst: 40},
+			"/api/v1/crack":           {Rate: 2, Burst: 5},
+			"/api/v1/crack/tmto":      {Rate: 0.5, Burst: 2},
+			"/api/v1/analysis/rounds": {Rate: 1, Burst: 3},
+			"/api/v1/hash/collision":  {Rate: 1, Burst: 3},
+			"/api/v1/mac/forge":       {Rate: 1, Burst: 3},
+		},
+		BruteForceWorkers: 2,
+		MaxBruteForceJobs: 8,
+		LogLevel:          "info",
+		LogFormat:         "text",
+		ReadTimeout:       Duration(15 * time.Second),
+		WriteTimeout:      Duration(60 * time.Second),
+		IdleTimeout:       Duration(120 * time.Second),
+		ShutdownTimeout:   Duration(10 * time.Second),
+		OracleTTL:         Duration(30 * time.Minute),
+		History: History{
+			Enabled: true,
+			File:    "history.jsonl",
+			Redact:  true,
+		},
+		Features: Features{
+			Blasting:  true,
+			Analysis:  true,
+			Oracle:    true,
+			Exercises: true,
+			Classical: true,
+			Metrics:   true,
+		},
+		Auth: Auth{
+			TokenTTL: Duration(12 * time.Hour),
+		},
+	}
+}
+
+// option 一个配置项：命令行参数名、环境变量名，以及从字符串设置与读取当前值的方法
+type option struct {
+	flag  string
+	env   string
+	usage string
+	// isBool 为 true 时命令行参数可以不带值，如 -history-redact
+	isBool bool
+	set    func(c *Config, v string) error
+	get    func(c *Config) string
+}
+
+func stringOption(name, usage string, field func(c *Config) *string) option {
+	return option{
+		usage: usage,
+		set:   func(c *Config, v string) error { *field(c) = v; return nil },
+		get:   func(c *Config) string { return *field(c) },
+	}.named(name)
+}
+
+func intOption(name, usage string, field func(c *Config) *int) option {
+	return option{
+		usage: usage,
+		set: func(c *Config, v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("必须是整数: %s", v)
+			}
+			*field(c) = n
+			return nil
+		},
+		get: func(c *Config) string { return strconv.Itoa(*field(c)) },
+	}.named(name)
+}
+
+func boolOption(name, usage string, field func(c *Config) *bool) option {
+	return option{
+		usage:  usage,
+		isBool: true,
+		set: func(c *Config, v string) error {
+			b, err := parseBool(v)
+			if err != nil {
+				return err
+			}
+			*field(c) = b
+			return nil
+		},
+		get: func(c *Config) string { return strconv.FormatBool(*field(c)) },
+	}.named(name)
+}
+
+// listOption 逗号分隔的字符串列表，空字符串表示空列表
+func listOption(name, usage string, field func(c *Config) *[]string) option {
+	return option{
+		usage: usage,
+		set: func(c *Config, v string) error {
+			list := field(c)
+			*list = nil
+			for _, item := range strings.Split(v, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					*list = append(*list, item)
+				}
+			}
+			return nil
+		},
+		get: func(c *Config) string { return strings.Join(*field(c), ",") },
+	}.named(name)
+}
+
+func durationOption(name, usage string, field func(c *Config) *Duration) option {
+	return option{
+		usage: usage,
+		set: func(c *Config, v string) error {
+			if err := field(c).UnmarshalText([]byte(v)); err != nil {
+				return fmt.Errorf("必须是时长（如 30s、5m）: %s", v)
+			}
+			return nil
+		},
+		get: func(c *Config) string { return field(c).String() },
+	}.named(name)
+}
+
+// named 由配置项名（如 history-file）生成参数名与环境变量名（SDES_HISTORY_FILE）
+func (o option) named(name string) option {
+	o.flag = name
+	o.env = "SDES_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+	return o
+}
+
+// parseBool 在 strconv.ParseBool 的基础上接受 on/off、yes/no
+func parseBool(v string) (bool, error) {
+	switch strings.ToLower(v) {
+	case "on", "yes":
+		return true, nil
+	case "off", "no":
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("必须是 true 或 false: %s", v)
+	}
+	return b, nil
+}
+
+var options = []option{
+	stringOption("addr", "监听地址", func(c *Config) *string { return &c.Addr }),
+	stringOption("mode", "运行模式：debug、release 或 test", func(c *Config) *string { return &c.Mode }),
+	listOption("cors-origins", "允许跨域的来源，逗号分隔，* 表示任意来源", func(c *Config) *[]string { return &c.CORSOrigins }),
+	listOption("trusted-proxies", "可信反向代理的 IP 或 CIDR，逗号分隔；为空时忽略 X-Forwarded-For", func(c *Config) *[]string { return &c.TrustedProxies }),
+	stringOption("tls-cert", "TLS 证书文件", func(c *Config) *string { return &c.TLSCert }),
+	stringOption("tls-key", "TLS 私钥文件", func(c *Config) *string { return &c.TLSKey }),
+	option{
+		usage: "请求体大小上限（字节）",
+		set: func(c *Config, v string) error {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return fmt.Errorf("必须是整数: %s", v)
+			}
+			c.MaxBodyBytes = n
+			return nil
+		},
+		get: func(c *Config) string { return strconv.FormatInt(c.MaxBodyBytes, 10) },
+	}.named("max-body-bytes"),
+	intOption("max-plaintext-bytes", "ASCII 明文、密文与文本输入的长度上限（字节）", func(c *Config) *int { return &c.MaxPlaintextBytes }),
+	option{
+		usage: "限流预算，格式为 路由=每秒令牌数:突发容量，逗号分隔，default 为其余路由共用，空字符串表示不限流",
+		set: func(c *Config, v string) error {
+			limits, err := parseRateLimits(v)
+			if err != nil {
+				return err
+			}
+			c.RateLimits = limits
+			return nil
+		},
+		get: func(c *Config) string { return formatRateLimits(c.RateLimits) },
+	}.named("rate-limits"),
+	intOption("brute-force-workers", "逐密钥穷举使用的协程数", func(c *Config) *int { return &c.BruteForceWorkers }),
+	intOption("max-brute-force-jobs", "同时进行的暴力破解请求上限", func(c *Config) *int { return &c.MaxBruteForceJobs }),
+	stringOption("static-dir", "从磁盘目录提供前端文件（前端开发用），默认使用编译进程序的文件", func(c *Config) *string { return &c.StaticDir }),
+	stringOption("codebook", "完整密码本持久化路径", func(c *Config) *string { return &c.Codebook }),
+	stringOption("log-level", "日志级别：debug、info、warn 或 error", func(c *Config) *string { return &c.LogLevel }),
+	stringOption("log-format", "日志格式：text 或 json", func(c *Config) *string { return &c.LogFormat }),
+	boolOption("log-secrets", "在日志中输出密钥与明密文（仅 debug 级别可用）", func(c *Config) *bool { return &c.LogSecrets }),
+	durationOption("read-timeout", "读取整个请求（含请求体）的超时时间", func(c *Config) *Duration { return &c.ReadTimeout }),
+	durationOption("write-timeout", "写出响应的超时时间，须大于最慢的暴力破解请求", func(c *Config) *Duration { return &c.WriteTimeout }),
+	durationOption("idle-timeout", "keep-alive 连接的空闲超时时间", func(c *Config) *Duration { return &c.IdleTimeout }),
+	durationOption("shutdown-timeout", "收到 SIGINT/SIGTERM 后等待处理中请求完成的最长时间", func(c *Config) *Duration { return &c.ShutdownTimeout }),
+	durationOption("oracle-ttl", "预言机挑战会话有效期，例如 30m、1h", func(c *Config) *Duration { return &c.OracleTTL }),
+	boolOption("history", "是否记录操作历史", func(c *Config) *bool { return &c.History.Enabled }),
+	stringOption("history-file", "操作历史文件", func(c *Config) *string { return &c.History.File }),
+	boolOption("history-redact", "操作历史中不保存密钥", func(c *Config) *bool { return &c.History.Redact }),
+	boolOption("history-public", "未启用认证时也开放操作历史接口", func(c *Config) *bool { return &c.History.Public }),
+	boolOption("feature-blasting", "启用暴力破解与 TMTO 接口", func(c *Config) *bool { return &c.Features.Blasting }),
+	boolOption("feature-analysis", "启用多轮分析、相关密钥与密钥扩展接口", func(c *Config) *bool { return &c.Features.Analysis }),
+	boolOption("feature-oracle", "启用预言机挑战接口", func(c *Config) *bool { return &c.Features.Oracle }),
+	boolOption("feature-exercises", "启用练习题接口", func(c *Config) *bool { return &c.Features.Exercises }),
+	boolOption("feature-classical", "启用古典密码接口", func(c *Config) *bool { return &c.Features.Classical }),
+	boolOption("feature-metrics", "启用 /metrics 指标接口", func(c *Config) *bool { return &c.Features.Metrics }),
+	boolOption("auth", "启用认证（API 密钥与令牌密钥在配置文件的 auth 中设置）", func(c *Config) *bool { return &c.Auth.Enabled }),
+	option{
+		usage: "启用认证时未认证请求也拥有的作用域，逗号分隔（encrypt、crack、admin）",
+		set: func(c *Config, v string) error {
+			c.Auth.Public = nil
+			for _, scope := range strings.Split(v, ",") {
+				if scope = strings.TrimSpace(scope); scope != "" {
+					c.Auth.Public = append(c.Auth.Public, scope)
+				}
+			}
+			return nil
+		},
+		get: func(c *Config) string { return strings.Join(c.Auth.Public, ",") },
+	}.named("auth-public"),
+	durationOption("auth-token-ttl", "签发令牌的默认有效期", func(c *Config) *Duration { return &c.Auth.TokenTTL }),
+}
+
+// parseRateLimits 解析 default=20:40,/api/v1/crack=2:5
+func parseRateLimits(v string) (map[string]ratelimit.Budget, error) {
+	limits := make(map[string]ratelimit.Budget)
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		route, budget, ok := strings.Cut(item, "=")
+		rate, burst, ok2 := strings.Cut(budget, ":")
+		if !ok || !ok2 {
+			return nil, fmt.Errorf("限流预算格式应为 路由=每秒令牌数:突发容量: %s", item)
+		}
+		r, err := strconv.ParseFloat(rate, 64)
+		if err != nil {
+			return nil, fmt.Errorf("每秒令牌数必须是数字: %s", item)
+		}
+		b, err := strconv.Atoi(burst)
+		if err != nil {
+			return nil, fmt.Errorf("突发容量必须是整数: %s", item)
+		}
+		limits[strings.TrimSpace(route)] = ratelimit.Budget{Rate: r, Burst: b}
+	}
+	return limits, nil
+}
+
+// formatRateLimits 按路由排序输出，default 在前
+func formatRateLimits(limits map[string]ratelimit.Budget) string {
+	routes := make([]string, 0, len(limits))
+	for route := range limits {
+		routes = append(routes, route)
+	}
+	slices.SortFunc(routes, func(a, b string) int {
+		if (a == ratelimit.DefaultRoute) != (b == ratelimit.DefaultRoute) {
+			if a == ratelimit.DefaultRoute {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+	items := make([]string, len(routes))
+	for i, route := range routes {
+		b := limits[route]
+		items[i] = fmt.Sprintf("%s=%s:%d", route, strconv.FormatFloat(b.Rate, 'g', -1, 64), b.Burst)
+	}
+	return strings.Join(items, ",")
+}
+
+// Load 依次应用配置文件、环境变量与命令行参数并校验
+// 配置文件由 -config 参数或 SDES_CONFIG 环境变量指定，扩展名为 .yaml、.yml 或 .toml
+// lookupEnv 与 os.LookupEnv 相同：设置为空字符串的环境变量同样生效，如 SDES_RATE_LIMITS= 关闭限流
+func Load(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	fs := flag.NewFlagSet("sdes", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	defaultConfigFile, _ := lookupEnv("SDES_CONFIG")
+	configFile := fs.String("config", defaultConfigFile, "配置文件（YAML 或 TOML）")
+	flagValues := make(map[string]string)
+	defaults := Default()
+	for _, o := range options {
+		usage := fmt.Sprintf("%s（环境变量 %s，默认 %s）", o.usage, o.env, o.get(defaults))
+		record := func(v string) error {
+			flagValues[o.flag] = v
+			return nil
+		}
+		if o.isBool {
+			fs.BoolFunc(o.flag, usage, record)
+		} else {
+			fs.Func(o.flag, usage, record)
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.SetOutput(os.Stderr)
+			fs.PrintDefaults()
+		}
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("无法识别的参数: %s", strings.Join(fs.Args(), " "))
+	}
+
+	cfg := Default()
+	if *configFile != "" {
+		if err := cfg.loadFile(*configFile); err != nil {
+			return nil, err
+		}
+	}
+	for _, o := range options {
+		if v, ok := lookupEnv(o.env); ok {
+			if err := o.set(cfg, v); err != nil {
+				return nil, fmt.Errorf("环境变量 %s %w", o.env, err)
+			}
+		}
+	}
+	for _, o := range options {
+		if v, ok := flagValues[o.flag]; ok {
+			if err := o.set(cfg, v); err != nil {
+				return nil, fmt.Errorf("参数 -%s %w", o.flag, err)
+			}
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadFile 读取配置文件，文件中未出现的字段保持原值，未知字段视为错误
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取配置文件失败: %w", err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalWithOptions(data, c, yaml.Strict())
+	case ".toml":
+		d := toml.NewDecoder(strings.NewReader(string(data)))
+		d.DisallowUnknownFields()
+		err = d.Decode(c)
+	default:
+		return fmt.Errorf("不支持的配置文件格式: %s（支持 .yaml、.yml、.toml）", path)
+	}
+	if err != nil {
+		return fmt.Errorf("解析配置文件 %s 失败: %w", path, err)
+	}
+	return nil
+}
+
+// Validate 校验配置
+func (c *Config) Validate() error {
+	if c.Addr == "" {
+		return errors.New("监听地址不能为空")
+	}
+	if !slices.Contains([]string{"debug", "release", "test"}, c.Mode) {
+		return fmt.Errorf("运行模式必须是 debug、release 或 test: %s", c.Mode)
+	}
+	for _, origin := range c.CORSOrigins {
+		if origin != "*" && !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			return fmt.Errorf("跨域来源必须是 * 或以 http:// / https:// 开头: %s", origin)
+		}
+	}
+	for _, proxy := range c.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err != nil {
+			if _, err := netip.ParseAddr(proxy); err != nil {
+				return fmt.Errorf("可信代理必须是 IP 或 CIDR: %s", proxy)
+			}
+		}
+	}
+	if (c.TLSCert == "") != (c.TLSKey == "") {
+		return errors.New("TLS 证书与私钥必须同时设置")
+	}
+	for _, f := range []string{c.TLSCert, c.TLSKey} {
+		if f == "" {
+			continue
+		}
+		if _, err := os.Stat(f); err != nil {
+			return fmt.Errorf("TLS 文件不可用: %w", err)
+		}
+	}
+	if c.MaxBodyBytes < 1024 {
+		return fmt.Errorf("请求体大小上限不能小于 1024 字节: %d", c.MaxBodyBytes)
+	}
+	if c.MaxPlaintextBytes < 1 {
+		return fmt.Errorf("文本长度上限必须大于 0: %d", c.MaxPlaintextBytes)
+	}
+	for route, b := range c.RateLimits {
+		if route != ratelimit.DefaultRoute && !strings.HasPrefix(route, "/") {
+			return fmt.Errorf("限流路由必须是 default 或以 / 开头的路由模板: %s", route)
+		}
+		if b.Rate < 0 || (b.Rate > 0 && b.Burst < 1) {
+			return fmt.Errorf("限流预算 %s 无效：每秒令牌数不能为负，限流时突发容量至少为 1", route)
+		}
+	}
+	if c.BruteForceWorkers < 1 || c.BruteForceWorkers > 256 {
+		return fmt.Errorf("暴力破解协程数必须在 1~256 之间: %d", c.BruteForceWorkers)
+	}
+	if c.MaxBruteForceJobs < 1 {
+		return fmt.Errorf("同时进行的暴力破解请求上限必须大于 0: %d", c.MaxBruteForceJobs)
+	}
+	if _, err := logging.ParseLevel(c.LogLevel); err != nil {
+		return err
+	}
+	if c.LogFormat != "text" && c.LogFormat != "json" {
+		return fmt.Errorf("日志格式必须是 text 或 json: %s", c.LogFormat)
+	}
+	if c.LogSecrets && strings.ToLower(c.LogLevel) != "debug" {
+		return errors.New("log-secrets 只能在 log-level 为 debug 时开启")
+	}
+	for name, d := range map[string]Duration{
+		"read-timeout":     c.ReadTimeout,
+		"write-timeout":    c.WriteTimeout,
+		"idle-timeout":     c.IdleTimeout,
+		"shutdown-timeout": c.ShutdownTimeout,
+	} {
+		if d <= 0 {
+			return fmt.Errorf("%s 必须大于 0: %s", name, d)
+		}
+	}
+	if c.StaticDir != "" {
+		if info, err := os.Stat(c.StaticDir); err != nil || !info.IsDir() {
+			return fmt.Errorf("静态文件目录不存在: %s", c.StaticDir)
+		}
+	}
+	if c.OracleTTL <= 0 {
+		return fmt.Errorf("预言机会话有效期必须大于 0: %s", c.OracleTTL)
+	}
+	if c.History.Enabled && c.History.File == "" {
+		return errors.New("启用操作历史时必须指定历史文件")
+	}
+	if c.Auth.Enabled {
+		return c.Auth.validate()
+	}
+	return nil
+}
+
+func (a *Auth) validate() error {
+	if len(a.APIKeys) == 0 && a.TokenSecret == "" {
+		return errors.New("启用认证时至少需要配置一个 API 密钥或令牌密钥")
+	}
+	for _, scope := range a.Public {
+		if !slices.Contains(auth.Scopes, scope) {
+			return fmt.Errorf("未知的公开作用域: %s（可选 %s）", scope, strings.Join(auth.Scopes, "、"))
+		}
+	}
+	ids := make(map[string]bool)
+	keys := make(map[string]bool)
+	for _, k := range a.APIKeys {
+		if k.ID == "" || ids[k.ID] {
+			return fmt.Errorf("API 密钥的 id 不能为空且不能重复: %q", k.ID)
+		}
+		if len(k.Key) < 16 || strings.ContainsAny(k.Key, ". ") || keys[k.Key] {
+			return fmt.Errorf("API 密钥 %s 无效：至少 16 个字符，不能含 \".\" 或空格，且不能与其他密钥相同", k.ID)
+		}
+		for _, scope := range k.Scopes {
+			if !slices.Contains(auth.Scopes, scope) {
+				return fmt.Errorf("API 密钥 %s 的作用域未知: %s", k.ID, scope)
+			}
+		}
+		ids[k.ID], keys[k.Key] = true, true
+	}
+	if a.TokenSecret != "" && len(a.TokenSecret) < minTokenSecret {
+		return fmt.Errorf("令牌密钥至少 %d 字节", minTokenSecret)
+	}
+	if a.TokenTTL <= 0 {
+		return fmt.Errorf("令牌有效期必须大于 0: %s", a.TokenTTL)
+	}
+	return nil
+}
+
+// TLS 是否启用 HTTPS
+func (c *Config) TLS() bool {
+	return c.TLSCert != ""
+}
+
+// Print 输出生效的配置，每行一项
+func (c *Config) Print(w io.Writer) {
+	fmt.Fprintln(w, "生效配置:")
+	for _, o := range options {
+		fmt.Fprintf(w, "  %-22s %s\n", o.flag, o.get(c))
+	}
+}
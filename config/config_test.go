@@ -0,0 +1,161 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(k string) (string, bool) {
+		v, ok := vars[k]
+		return v, ok
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, "sdes.yaml")
+	os.WriteFile(yamlFile, []byte(`
+addr: ":9000"
+mode: release
+cors_origins: ["https://a.example"]
+oracle_ttl: 1h
+history:
+  file: ops.jsonl
+features:
+  oracle: false
+`), 0o600)
+
+	cfg, err := Load([]string{"-config", yamlFile, "-addr", ":9002", "-feature-analysis=false", "-log-level", "debug", "-log-secrets"}, env(map[string]string{
+		"SDES_ADDR":                ":9001",
+		"SDES_BRUTE_FORCE_WORKERS": "4",
+		"SDES_HISTORY":             "off",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Addr != ":9002" {
+		t.Errorf("命令行参数应覆盖环境变量与配置文件，addr = %s", cfg.Addr)
+	}
+	if cfg.Mode != "release" || cfg.CORSOrigins[0] != "https://a.example" || time.Duration(cfg.OracleTTL) != time.Hour {
+		t.Errorf("配置文件未生效: %+v", cfg)
+	}
+	if !cfg.History.Redact || cfg.History.File != "ops.jsonl" {
+		t.Errorf("配置文件中未出现的字段应保持默认值: %+v", cfg.History)
+	}
+	if cfg.BruteForceWorkers != 4 || cfg.History.Enabled {
+		t.Errorf("环境变量未生效: %+v", cfg)
+	}
+	if cfg.Features.Oracle || cfg.Features.Analysis || !cfg.Features.Blasting || !cfg.LogSecrets {
+		t.Errorf("功能开关错误: %+v", cfg.Features)
+	}
+
+	tomlFile := filepath.Join(dir, "sdes.toml")
+	os.WriteFile(tomlFile, []byte("addr = \":9100\"\n[features]\nclassical = false\n"), 0o600)
+	cfg, err = Load(nil, env(map[string]string{"SDES_CONFIG": tomlFile}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Addr != ":9100" || cfg.Features.Classical {
+		t.Errorf("TOML 配置未生效: %+v", cfg)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	dir := t.TempDir()
+	unknown := filepath.Join(dir, "bad.yaml")
+	os.WriteFile(unknown, []byte("adress: \":1\"\n"), 0o600)
+
+	cases := []struct {
+		args []string
+		env  map[string]string
+		want string
+	}{
+		{[]string{"-mode", "prod"}, nil, "运行模式"},
+		{[]string{"-tls-cert", "cert.pem"}, nil, "同时设置"},
+		{nil, map[string]string{"SDES_ORACLE_TTL": "abc"}, "SDES_ORACLE_TTL"},
+		{[]string{"-brute-force-workers", "0"}, nil, "协程数"},
+		{[]string{"-cors-origins", "example.com"}, nil, "跨域来源"},
+		{[]string{"-trusted-proxies", "10.0.0.0/8,proxy.local"}, nil, "可信代理"},
+		{[]string{"-config", unknown}, nil, "解析配置文件"},
+		{[]string{"-unknown"}, nil, "-unknown"},
+		{[]string{"-log-secrets"}, nil, "只能在 log-level 为 debug 时开启"},
+		{[]string{"-log-format", "xml"}, nil, "日志格式"},
+		{[]string{"-auth"}, nil, "至少需要配置"},
+	}
+	for _, tc := range cases {
+		_, err := Load(tc.args, env(tc.env))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%v %v: 错误应包含 %q，实际 %v", tc.args, tc.env, tc.want, err)
+		}
+	}
+}
+
+func TestRateLimitsOption(t *testing.T) {
+	cfg, err := Load([]string{"-rate-limits", "default=10:20, /api/blasting=0.5:2"}, env(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.RateLimits) != 2 || cfg.RateLimits["/api/blasting"].Rate != 0.5 || cfg.RateLimits["default"].Burst != 20 {
+		t.Errorf("限流预算解析错误: %+v", cfg.RateLimits)
+	}
+	if got := formatRateLimits(cfg.RateLimits); got != "default=10:20,/api/blasting=0.5:2" {
+		t.Errorf("formatRateLimits = %s", got)
+	}
+	for _, bad := range []string{"default=10", "api=1:1", "default=1:0"} {
+		if _, err := Load([]string{"-rate-limits", bad}, env(nil)); err == nil {
+			t.Errorf("%s 应返回错误", bad)
+		}
+	}
+}
+
+// 设置为空字符串的环境变量同样覆盖默认值，未设置的环境变量不生效
+func TestEmptyEnvOverridesDefault(t *testing.T) {
+	cfg, err := Load(nil, env(map[string]string{"SDES_RATE_LIMITS": "", "SDES_CORS_ORIGINS": ""}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.RateLimits) != 0 || len(cfg.CORSOrigins) != 0 {
+		t.Errorf("空环境变量未生效: rate_limits=%v cors_origins=%v", cfg.RateLimits, cfg.CORSOrigins)
+	}
+	cfg, _ = Load(nil, env(nil))
+	if len(cfg.RateLimits) == 0 || len(cfg.CORSOrigins) == 0 {
+		t.Errorf("未设置的环境变量不应覆盖默认值: %+v", cfg)
+	}
+}
+
+func TestAuthConfig(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "sdes.yaml")
+	os.WriteFile(file, []byte(`
+auth:
+  enabled: true
+  public: [encrypt]
+  token_secret: "0123456789abcdef0123456789abcdef"
+  api_keys:
+    - { id: teacher, key: "teacher-key-0123456789", scopes: [crack, admin] }
+`), 0o600)
+	cfg, err := Load([]string{"-config", file}, env(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Auth.Enabled || cfg.Auth.Public[0] != "encrypt" || cfg.Auth.APIKeys[0].Scopes[1] != "admin" ||
+		time.Duration(cfg.Auth.TokenTTL) != 12*time.Hour {
+		t.Errorf("认证配置未生效: %+v", cfg.Auth)
+	}
+
+	for _, bad := range []struct{ yaml, want string }{
+		{"auth: {enabled: true, token_secret: short}", "令牌密钥至少"},
+		{"auth: {enabled: true, public: [everything], token_secret: 0123456789abcdef0123456789abcdef}", "未知的公开作用域"},
+		{"auth: {enabled: true, api_keys: [{id: a, key: 0123456789abcdef, scopes: [root]}]}", "作用域未知"},
+		{"auth: {enabled: true, api_keys: [{id: a, key: short}]}", "至少 16 个字符"},
+	} {
+		os.WriteFile(file, []byte(bad.yaml), 0o600)
+		if _, err := Load([]string{"-config", file}, env(nil)); err == nil || !strings.Contains(err.Error(), bad.want) {
+			t.Errorf("%s: 错误应包含 %q，实际 %v", bad.yaml, bad.want, err)
+		}
+	}
+}
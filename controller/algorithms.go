@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"SDES/dto/response"
+	"SDES/utils/cipher"
+	"SDES/utils/classical"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AlgorithmsHandler 列出所有已注册的算法及其参数
+func AlgorithmsHandler(c *gin.Context) {
+	list := cipher.List()
+	algorithms := make([]response.AlgorithmInfo, 0, len(list))
+	for _, alg := range list {
+		algorithms = append(algorithms, response.AlgorithmInfo{
+			Name:        alg.Name(),
+			DisplayName: alg.DisplayName(),
+			BlockBits:   alg.BlockBits(),
+			KeyBits:     alg.KeyBits(),
+			Default:     alg.Name() == cipher.DefaultName,
+		})
+	}
+
+	classicalList := classical.List()
+	classicals := make([]response.ClassicalInfo, 0, len(classicalList))
+	for _, cl := range classicalList {
+		classicals = append(classicals, response.ClassicalInfo{
+			Name:        cl.Name(),
+			DisplayName: cl.DisplayName(),
+			KeyFormat:   cl.KeyFormat(),
+		})
+	}
+
+	c.JSON(http.StatusOK, response.AlgorithmsResponse{
+		Algorithms: algorithms,
+		Classical:  classicals,
+		Success:    true,
+	})
+}
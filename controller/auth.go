@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"SDES/apierror"
+	"SDES/auth"
+	"SDES/dto/request"
+	"SDES/dto/response"
+	"SDES/logging"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TokenHandler 返回 POST /api/v1/auth/token 的处理函数，用 a 签发令牌，defaultTTL 为未指定有效期时的默认值
+// 只能签发调用方自己拥有的作用域（admin 拥有全部作用域）
+func TokenHandler(a *auth.Authenticator, defaultTTL time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req request.TokenRequest
+		if !bindJSON(c, &req) {
+			return
+		}
+		caller, _ := c.Value(auth.PrincipalKey).(*auth.Principal)
+		for _, scope := range req.Scopes {
+			if !slices.Contains(auth.Scopes, scope) {
+				apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.InvalidValue, "scopes", "scopes", "enum", strings.Join(auth.Scopes, ", ")))
+				return
+			}
+			if !caller.Has(scope) {
+				apierror.Abort(c, apierror.New(http.StatusForbidden, apierror.InsufficientScope, "scopes", scope))
+				return
+			}
+		}
+		ttl := defaultTTL
+		if req.ExpiresIn > 0 {
+			ttl = time.Duration(req.ExpiresIn) * time.Second
+		}
+		token, claims := a.Issue(req.Subject, req.Scopes, ttl)
+		// 日志中的 principal 字段为签发者
+		logging.FromContext(c.Request.Context()).Info("签发令牌", "subject", req.Subject, "scopes", req.Scopes, "ttl", ttl.String())
+		c.JSON(http.StatusOK, response.TokenResponse{
+			Token:     token,
+			Subject:   claims.Subject,
+			Scopes:    claims.Scopes,
+			ExpiresAt: time.Unix(claims.ExpiresAt, 0).UTC().Format(time.RFC3339),
+			Success:   true,
+		})
+	}
+}
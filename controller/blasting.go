@@ -1,11 +1,13 @@
 package controller
 
 import (
+	"SDES/apierror"
 	"SDES/dto/request"
 	"SDES/dto/response"
 	"SDES/utils"
+	"SDES/utils/cipher"
+	"context"
 	"fmt"
-	"log"
 	"net/http"
 	"sync"
 	"time"
@@ -13,45 +15,130 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+var (
+	// bruteForceWorkers 逐密钥穷举使用的协程数
+	bruteForceWorkers = 2
+	// bruteForceSlots 限制同时进行的暴力破解请求数
+	bruteForceSlots = make(chan struct{}, 8)
+	// bruteForceCtx 服务器关闭时取消，正在进行的暴力破解随之中止
+	bruteForceCtx, cancelBruteForce = context.WithCancel(context.Background())
+)
+
+// errBruteForceCancelled 暴力破解因服务器关闭而中止
+var errBruteForceCancelled = apierror.New(http.StatusServiceUnavailable, apierror.ShuttingDown, "")
+
+// CancelBruteForceJobs 取消所有正在进行的暴力破解并拒绝新的请求，在服务器关闭时调用
+func CancelBruteForceJobs() {
+	cancelBruteForce()
+}
+
+// ConfigureBruteForce 设置穷举协程数与同时进行的暴力破解请求上限，须在启动服务前调用
+func ConfigureBruteForce(workers, maxJobs int) {
+	bruteForceWorkers = workers
+	bruteForceSlots = make(chan struct{}, maxJobs)
+}
+
+// bruteForceQueueTimeout 名额已满时排队等待的最长时间
+const bruteForceQueueTimeout = 5 * time.Second
+
+// acquireBruteForce 占用一个暴力破解名额，名额已满时排队等待；
+// 排队超时、客户端断开或服务器正在关闭时写入 503 响应并返回 false
+func acquireBruteForce(c *gin.Context) bool {
+	reject := func(reason string, code apierror.Code) bool {
+		bruteForceRejected.Inc(reason)
+		apierror.Abort(c, apierror.New(http.StatusServiceUnavailable, code, ""))
+		return false
+	}
+	if bruteForceCtx.Err() != nil {
+		return reject("shutdown", apierror.ShuttingDown)
+	}
+	select {
+	case bruteForceSlots <- struct{}{}:
+		return true
+	default:
+	}
+
+	bruteForceWaiting.Inc()
+	defer bruteForceWaiting.Dec()
+	timer := time.NewTimer(bruteForceQueueTimeout)
+	defer timer.Stop()
+	select {
+	case bruteForceSlots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return reject("queue_timeout", apierror.BruteForceBusy)
+	case <-bruteForceCtx.Done():
+		return reject("shutdown", apierror.ShuttingDown)
+	case <-c.Request.Context().Done():
+		return reject("client_gone", apierror.RequestCancelled)
+	}
+}
+
+func releaseBruteForce() {
+	<-bruteForceSlots
+}
+
+// BlastingHandler API 处理函数
+// 已弃用：与 /api/v1/crack 共用实现，响应保持原格式；未找到密钥时 success 为 false
 func BlastingHandler(c *gin.Context) {
 	var req request.BlastingRequest
 	var startTime = time.Now()
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, response.BlastingResponse{
-			Success: false,
-			Message: "无效的请求格式",
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
-	if req.Plaintext == "" || req.Ciphertext == "" {
-		c.JSON(http.StatusBadRequest, response.BlastingResponse{
-			Success: false,
-			Message: "plaintext和ciphertext不能为空",
-		})
+	res, ok := runCrack(c, request.CrackRequest{
+		Algorithm:  req.Algorithm,
+		Plaintext:  req.Plaintext,
+		Ciphertext: req.Ciphertext,
+		Rounds:     req.Rounds,
+	}, startTime)
+	if !ok {
 		return
 	}
-	if !utils.IsValidBinary(req.Plaintext, 8) {
-		c.JSON(http.StatusBadRequest, response.BlastingResponse{
+	var duration = time.Since(startTime)
+	var timeString = fmt.Sprintf("%.2fms", float64(duration.Nanoseconds())/1000000)
+	// 根据找到的密钥数量返回相应结果
+	if len(res.keys) == 0 {
+		detail := apierror.Detail(c, apierror.New(http.StatusOK, apierror.NoKeyFound, ""))
+		c.JSON(http.StatusOK, response.BlastingResponse{
 			Success: false,
-			Message: "plaintext必须是8位二进制字符串（只包含0和1）",
+			Message: detail.Message,
+			Error:   detail,
+			Time:    timeString,
 		})
 		return
 	}
-	if !utils.IsValidBinary(req.Ciphertext, 8) {
-		c.JSON(http.StatusBadRequest, response.BlastingResponse{
-			Success: false,
-			Message: "ciphertext必须是8位二进制字符串（只包含0和1）",
-		})
-		return
+	var message string
+	if len(res.keys) == 1 {
+		message = fmt.Sprintf("成功破解！找到1个密钥：%s（十进制：%d）", res.keys[0], res.keysDecimal[0])
+	} else {
+		message = fmt.Sprintf("成功破解！找到%d个可能的密钥", len(res.keys))
 	}
-	// 将输入的明文和密文转换为位数组
-	plaintextBits := utils.StringToBits(req.Plaintext, 8)
-	ciphertextBits := utils.StringToBits(req.Ciphertext, 8)
-	log.Printf("plaintextBits: %s", utils.BitsToString(plaintextBits))
-	log.Printf("ciphertextBits: %s", utils.BitsToString(ciphertextBits))
-	log.Println("开始暴力破解...")
-
-	// 用于收集所有匹配的密钥
+	c.JSON(http.StatusOK, response.BlastingResponse{
+		Success:     true,
+		Message:     message,
+		Plaintext:   req.Plaintext,
+		Ciphertext:  req.Ciphertext,
+		Keys:        res.keys,
+		KeysDecimal: res.keysDecimal,
+		KeyCount:    len(res.keys),
+		Time:        timeString,
+	})
+}
+
+// bruteForceContext 返回在客户端断开或服务器关闭时取消的上下文，调用方须在结束时调用返回的函数
+func bruteForceContext(c *gin.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	stop := context.AfterFunc(bruteForceCtx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// bruteForce 用 bruteForceWorkers 个协程逐个密钥穷举，适用于没有位切片实现的算法
+// ctx 取消时提前返回 errBruteForceCancelled
+func bruteForce(ctx context.Context, alg cipher.Cipher, plaintextBits, ciphertextBits []int) ([]string, []int, error) {
 	var (
 		foundKeys        []string
 		foundKeysDecimal []int
@@ -61,11 +148,11 @@ func BlastingHandler(c *gin.Context) {
 		mu sync.Mutex
 	)
 
-	// 用两个线程进行破解
-	ranges := [][2]int{{0, 512}, {512, 1024}}
+	keySpace := 1 << alg.KeyBits()
+	workers := min(bruteForceWorkers, keySpace)
 
-	for _, r := range ranges {
-		start, end := r[0], r[1]
+	for w := 0; w < workers; w++ {
+		start, end := keySpace*w/workers, keySpace*(w+1)/workers
 		wg.Add(1)
 		go func(start, end int) {
 			defer wg.Done()
@@ -74,11 +161,15 @@ func BlastingHandler(c *gin.Context) {
 			localKeysDecimal := make([]int, 0, 4)
 
 			for i := start; i < end; i++ {
-				keyBits := utils.IntTo10BitKey(i)
-				encryptedBits := utils.Encrypt(plaintextBits, keyBits)
+				// 每 1024 个密钥检查一次是否已取消
+				if i%1024 == 0 && ctx.Err() != nil {
+					return
+				}
+				keyBits := utils.IntToBits(i, alg.KeyBits())
+				encryptedBits := alg.Encrypt(plaintextBits, keyBits)
 
 				match := true
-				for j := 0; j < 8; j++ {
+				for j := range ciphertextBits {
 					if encryptedBits[j] != ciphertextBits[j] {
 						match = false
 						break
@@ -86,10 +177,8 @@ func BlastingHandler(c *gin.Context) {
 				}
 
 				if match {
-					keyString := utils.BitsToString(keyBits)
-					localKeys = append(localKeys, keyString)
+					localKeys = append(localKeys, utils.BitsToString(keyBits))
 					localKeysDecimal = append(localKeysDecimal, i)
-					log.Printf("找到匹配密钥：%s（十进制：%d）", keyString, i)
 				}
 			}
 
@@ -101,35 +190,8 @@ func BlastingHandler(c *gin.Context) {
 	}
 
 	wg.Wait()
-	var endTime = time.Now()
-	var duration = endTime.Sub(startTime)
-	var timeString = fmt.Sprintf("%.2fms", float64(duration.Nanoseconds())/1000000)
-	// 根据找到的密钥数量返回相应结果
-	if len(foundKeys) > 0 {
-		var message string
-		if len(foundKeys) == 1 {
-			message = fmt.Sprintf("成功破解！找到1个密钥：%s（十进制：%d）", foundKeys[0], foundKeysDecimal[0])
-		} else {
-			message = fmt.Sprintf("成功破解！找到%d个可能的密钥", len(foundKeys))
-		}
-		log.Printf("暴力破解完成！总共找到%d个匹配的密钥", len(foundKeys))
-
-		c.JSON(http.StatusOK, response.BlastingResponse{
-			Success:     true,
-			Message:     message,
-			Plaintext:   req.Plaintext,
-			Ciphertext:  req.Ciphertext,
-			Keys:        foundKeys,
-			KeysDecimal: foundKeysDecimal,
-			KeyCount:    len(foundKeys),
-			Time:        timeString,
-		})
-	} else {
-		log.Println("暴力破解完成，未找到匹配的密钥")
-		c.JSON(http.StatusOK, response.BlastingResponse{
-			Success: false,
-			Message: "暴力破解失败：未找到匹配的密钥",
-			Time:    timeString,
-		})
+	if ctx.Err() != nil {
+		return nil, nil, errBruteForceCancelled
 	}
+	return foundKeys, foundKeysDecimal, nil
 }
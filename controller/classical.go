@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"SDES/apierror"
+	"SDES/dto/request"
+	"SDES/dto/response"
+	"SDES/utils/classical"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClassicalEncryptHandler 古典密码加密
+func ClassicalEncryptHandler(c *gin.Context) {
+	classicalTransform(c, true)
+}
+
+// ClassicalDecryptHandler 古典密码解密
+func ClassicalDecryptHandler(c *gin.Context) {
+	classicalTransform(c, false)
+}
+
+// classicalTransform 加密与解密共用的请求处理
+func classicalTransform(c *gin.Context, encrypt bool) {
+	var req request.ClassicalRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if textTooLong(c, "text", len(req.Text)) {
+		return
+	}
+
+	cl, ok := lookupClassical(c, req.Algorithm)
+	if !ok {
+		return
+	}
+
+	var (
+		result string
+		err    error
+	)
+	if encrypt {
+		result, err = cl.Encrypt(req.Text, req.Key)
+	} else {
+		result, err = cl.Decrypt(req.Text, req.Key)
+	}
+	if err != nil {
+		apierror.Abort(c, classicalError(cl, "text", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.ClassicalResponse{
+		Algorithm: cl.Name(),
+		Result:    result,
+		Success:   true,
+	})
+}
+
+// ClassicalCrackHandler 古典密码唯密文分析（频率分析、Kasiski 测试与重合指数）
+func ClassicalCrackHandler(c *gin.Context) {
+	var req request.ClassicalCrackRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if textTooLong(c, "ciphertext", len(req.Ciphertext)) {
+		return
+	}
+
+	cl, ok := lookupClassical(c, req.Algorithm)
+	if !ok {
+		return
+	}
+
+	startTime := time.Now()
+	// 密文本身的统计特征，即使无法自动破解也一并返回
+	resp := response.ClassicalCrackResponse{
+		Algorithm: cl.Name(),
+		IC:        classical.IndexOfCoincidence(req.Ciphertext),
+		Frequency: classical.FrequencyReport(req.Ciphertext),
+	}
+
+	result, err := cl.Crack(req.Ciphertext)
+	resp.Time = fmt.Sprintf("%.2fms", float64(time.Since(startTime).Nanoseconds())/1000000)
+	if err != nil {
+		e := classicalError(cl, "ciphertext", err)
+		resp.Error = apierror.Detail(c, e)
+		resp.Message = resp.Error.Message
+		c.JSON(e.Status, resp)
+		return
+	}
+
+	resp.Key = result.Key
+	resp.Plaintext = result.Plaintext
+	resp.Score = result.Score
+	resp.Details = result.Details
+	resp.Success = true
+	c.JSON(http.StatusOK, resp)
+}
+
+// lookupClassical 按名称查找古典密码，不支持时写入 UNSUPPORTED_ALGORITHM
+func lookupClassical(c *gin.Context, name string) (classical.Cipher, bool) {
+	cl, err := classical.Lookup(name)
+	if err != nil {
+		apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.UnsupportedAlgorithm, "algorithm", name))
+		return nil, false
+	}
+	return cl, true
+}
+
+// classicalError 将古典密码的错误类别映射为 API 错误，field 为文本所在的请求字段
+func classicalError(cl classical.Cipher, field string, err error) *apierror.Error {
+	switch {
+	case errors.Is(err, classical.ErrInvalidKey):
+		return apierror.New(http.StatusBadRequest, apierror.InvalidClassicalKey, "key", cl.Name())
+	case errors.Is(err, classical.ErrOddLetters):
+		return apierror.New(http.StatusBadRequest, apierror.OddLetterCount, field, cl.Name(), field)
+	case errors.Is(err, classical.ErrTooShort):
+		return apierror.New(http.StatusBadRequest, apierror.CiphertextTooShort, field, field)
+	}
+	return apierror.New(http.StatusUnprocessableEntity, apierror.CrackFailed, field)
+}
@@ -1,87 +1,87 @@
 package controller
 
 import (
+	"SDES/apierror"
 	"SDES/dto/request"
 	"SDES/dto/response"
-	"SDES/utils"
-	"encoding/base64"
+	"SDES/utils/cipher"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// DecryptHandler API 处理函数
+// 已弃用：请求转换为 /api/v1/decrypt 的结构后执行，响应保持原格式
 func DecryptHandler(c *gin.Context) {
 	var req request.DecryptRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, response.DecryptResponse{
-			Success: false,
-			Message: "无效的请求格式",
-		})
+	startTime := time.Now()
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	if req.Ciphertext == "" && req.CiphertextBase64 == nil {
-		c.JSON(http.StatusBadRequest, response.DecryptResponse{
-			Success: false,
-			Message: "必须提供二进制密文或 Base64 密文",
-		})
+		apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.MissingField, "ciphertext", "ciphertext / ciphertext_base64"))
 		return
 	}
 
-	if !utils.IsValidBinary(req.Key, 10) {
-		c.JSON(http.StatusBadRequest, response.DecryptResponse{
-			Success: false,
-			Message: "密钥必须是10位二进制字符串（只包含0和1）",
-		})
+	alg, ok := lookupCipher(c, req.Algorithm)
+	if !ok {
 		return
 	}
 
-	keyBits := utils.StringToBits(req.Key, 10)
-
-	if req.CiphertextBase64 != nil {
-		ciphertextBytes, err := base64.StdEncoding.DecodeString(*req.CiphertextBase64)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, response.DecryptResponse{
-				Success: false,
-				Message: "Base64 密文解析失败",
-			})
+	v1 := request.CipherRequest{Algorithm: alg.Name(), Key: req.Key, MACKey: req.MACKey}
+	if req.Tag != nil {
+		// 旧接口以是否提供 tag 判断是否启用认证
+		if *req.Tag == "" {
+			apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.MissingField, "tag", "tag"))
 			return
 		}
-		if len(ciphertextBytes) == 0 {
-			c.JSON(http.StatusBadRequest, response.DecryptResponse{
-				Success: false,
-				Message: "Base64 密文不能为空",
-			})
+		v1.Tag = *req.Tag
+	} else {
+		v1.MACKey = ""
+	}
+	field := "ciphertext_base64"
+	if req.CiphertextBase64 != nil {
+		// 旧接口沿用 0x00 补齐
+		v1.Data, v1.Padding = *req.CiphertextBase64, cipher.PaddingZero
+	} else {
+		// 二进制输入为单个分组
+		field = "ciphertext"
+		if !checkBinary(c, "key", req.Key, alg.KeyBits(), apierror.InvalidKeyLength) ||
+			!checkBinary(c, "ciphertext", req.Ciphertext, alg.BlockBits(), apierror.InvalidBlockLength) {
 			return
 		}
-
-		plaintextBytes := utils.DecryptBytes(ciphertextBytes, keyBits)
-
-		c.JSON(http.StatusOK, response.DecryptResponse{
-			PlaintextASCII: utils.BytesToASCIIString(plaintextBytes),
-			Success:        true,
-		})
-		return
+		v1.Data = req.Ciphertext
+		v1.InputEncoding, v1.OutputEncoding, v1.Padding = encodingBinary, encodingBinary, cipher.PaddingNone
 	}
 
-	// 验证输入
-	if !utils.IsValidBinary(req.Ciphertext, 8) {
-		c.JSON(http.StatusBadRequest, response.DecryptResponse{
-			Success: false,
-			Message: "密文必须是8位二进制字符串（只包含0和1）",
+	res, e := runCipher(c, v1, false, startTime)
+	if e != nil {
+		e = legacyError(e, map[string]string{"data": field})
+		if res == nil {
+			apierror.Abort(c, e)
+			return
+		}
+		// 标签不匹配
+		detail := apierror.Detail(c, e)
+		c.JSON(e.Status, response.DecryptResponse{
+			TagValid: res.tagValid,
+			Success:  false,
+			Message:  detail.Message,
+			Error:    detail,
 		})
 		return
 	}
 
-	// 转换为位数组
-	ciphertextBits := utils.StringToBits(req.Ciphertext, 8)
-
-	// 解密
-	plaintextBits := utils.Decrypt(ciphertextBits, keyBits)
-	plaintext := utils.BitsToString(plaintextBits)
-
-	c.JSON(http.StatusOK, response.DecryptResponse{
-		Plaintext: plaintext,
-		Success:   true,
-	})
+	resp := response.DecryptResponse{
+		TagValid: res.tagValid,
+		Success:  true,
+	}
+	if req.CiphertextBase64 != nil {
+		resp.PlaintextASCII = res.data
+	} else {
+		resp.Plaintext = res.data
+	}
+	c.JSON(http.StatusOK, resp)
 }
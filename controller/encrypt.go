@@ -1,89 +1,92 @@
 package controller
 
 import (
+	"SDES/apierror"
 	"SDES/dto/request"
 	"SDES/dto/response"
 	"SDES/utils"
-	"encoding/base64"
+	"SDES/utils/cipher"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 // EncryptHandler API 处理函数
+// 已弃用：请求转换为 /api/v1/encrypt 的结构后执行，响应保持原格式
 func EncryptHandler(c *gin.Context) {
 	var req request.EncryptRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, response.EncryptResponse{
-			Success: false,
-			Message: "无效的请求格式",
-		})
+	startTime := time.Now()
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	if req.Plaintext == "" && req.PlaintextASCII == nil {
-		c.JSON(http.StatusBadRequest, response.EncryptResponse{
-			Success: false,
-			Message: "必须提供二进制明文或 ASCII 明文",
-		})
+		apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.MissingField, "plaintext", "plaintext / plaintext_ascii"))
 		return
 	}
 
-	if !utils.IsValidBinary(req.Key, 10) {
-		c.JSON(http.StatusBadRequest, response.EncryptResponse{
-			Success: false,
-			Message: "密钥必须是10位二进制字符串（只包含0和1）",
-		})
+	alg, ok := lookupCipher(c, req.Algorithm)
+	if !ok {
 		return
 	}
 
-	keyBits := utils.StringToBits(req.Key, 10)
-
-	if req.PlaintextASCII != nil {
-		plaintextBytes, err := utils.ASCIIStringToBytes(*req.PlaintextASCII)
+	// 自动生成密钥；旧接口只有 ECB，不需要 IV
+	var generatedKey *int
+	if req.AutoKey {
+		k, err := randomKey(alg, req.ExcludeWeakKeys)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, response.EncryptResponse{
-				Success: false,
-				Message: err.Error(),
-			})
+			apierror.Abort(c, apierror.New(http.StatusInternalServerError, apierror.KeyGenerationFailed, ""))
 			return
 		}
-		if len(plaintextBytes) == 0 {
-			c.JSON(http.StatusBadRequest, response.EncryptResponse{
-				Success: false,
-				Message: "ASCII 明文不能为空",
-			})
+		generatedKey = &k
+		req.Key = utils.BitsToString(utils.IntToBits(k, alg.KeyBits()))
+	}
+
+	v1 := request.CipherRequest{Algorithm: alg.Name(), Key: req.Key}
+	if req.Authenticated {
+		if req.MACKey == "" {
+			apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.MissingField, "mac_key", "mac_key"))
 			return
 		}
-
-		ciphertextBytes := utils.EncryptBytes(plaintextBytes, keyBits)
-		ciphertextBase64 := base64.StdEncoding.EncodeToString(ciphertextBytes)
-
-		c.JSON(http.StatusOK, response.EncryptResponse{
-			CiphertextBase64: ciphertextBase64,
-			Success:          true,
-		})
-		return
+		v1.MACKey = req.MACKey
+	}
+	field := "plaintext_ascii"
+	if req.PlaintextASCII != nil {
+		// 旧接口沿用 0x00 补齐
+		v1.Data, v1.Padding = *req.PlaintextASCII, cipher.PaddingZero
+	} else {
+		// 二进制输入为单个分组
+		field = "plaintext"
+		if !checkBinary(c, "key", req.Key, alg.KeyBits(), apierror.InvalidKeyLength) ||
+			!checkBinary(c, "plaintext", req.Plaintext, alg.BlockBits(), apierror.InvalidBlockLength) {
+			return
+		}
+		v1.Data = req.Plaintext
+		v1.InputEncoding, v1.OutputEncoding, v1.Padding = encodingBinary, encodingBinary, cipher.PaddingNone
 	}
 
-	// 验证二进制输入
-	if !utils.IsValidBinary(req.Plaintext, 8) {
-		c.JSON(http.StatusBadRequest, response.EncryptResponse{
-			Success: false,
-			Message: "明文必须是8位二进制字符串（只包含0和1）",
-		})
+	res, e := runCipher(c, v1, true, startTime)
+	if e != nil {
+		apierror.Abort(c, legacyError(e, map[string]string{"data": field}))
 		return
 	}
 
-	// 转换为位数组
-	plaintextBits := utils.StringToBits(req.Plaintext, 8)
-
-	// 加密
-	ciphertextBits := utils.Encrypt(plaintextBits, keyBits)
-	ciphertext := utils.BitsToString(ciphertextBits)
-
-	c.JSON(http.StatusOK, response.EncryptResponse{
-		CiphertextBinary: ciphertext,
-		Success:          true,
-	})
+	// 仅在自动生成时回传密钥
+	var respKey string
+	if generatedKey != nil {
+		respKey = req.Key
+	}
+	resp := response.EncryptResponse{
+		Key:        respKey,
+		KeyDecimal: generatedKey,
+		Tag:        res.tag,
+		Success:    true,
+	}
+	if req.PlaintextASCII != nil {
+		resp.CiphertextBase64 = res.data
+	} else {
+		resp.CiphertextBinary = res.data
+	}
+	c.JSON(http.StatusOK, resp)
 }
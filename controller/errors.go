@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"SDES/apierror"
+	"SDES/utils/cipher"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bindJSON 解析请求体，失败时写入 INVALID_REQUEST 并返回 false
+func bindJSON(c *gin.Context, req any) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.InvalidRequest, ""))
+		return false
+	}
+	return true
+}
+
+// lookupCipher 按名称查找算法，不支持时写入 UNSUPPORTED_ALGORITHM
+func lookupCipher(c *gin.Context, name string) (cipher.Cipher, bool) {
+	alg, err := cipher.Lookup(name)
+	if err != nil {
+		apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.UnsupportedAlgorithm, "algorithm", name))
+		return nil, false
+	}
+	return alg, true
+}
+
+// checkBinary 校验二进制字符串字段，失败时写入错误响应并返回 false
+func checkBinary(c *gin.Context, field, value string, bits int, lengthCode apierror.Code) bool {
+	if e := apierror.Binary(field, value, bits, lengthCode); e != nil {
+		apierror.Abort(c, e)
+		return false
+	}
+	return true
+}
+
+// checkRange 校验取值在 1~max 之间的整数字段，失败时写入 INVALID_VALUE 并返回 false
+func checkRange(c *gin.Context, field string, value, max int) bool {
+	switch {
+	case value < 1:
+		apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.InvalidValue, field, field, "min", "1"))
+	case value > max:
+		apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.InvalidValue, field, field, "max", strconv.Itoa(max)))
+	default:
+		return true
+	}
+	return false
+}
+
+// parseKey 经由算法解析二进制密钥，失败时返回 MISSING_FIELD、INVALID_BINARY 或 INVALID_KEY_LENGTH
+func parseKey(alg cipher.Cipher, field, value string) ([]int, *apierror.Error) {
+	key, err := alg.ParseKey(value)
+	if err != nil {
+		return nil, apierror.Binary(field, value, alg.KeyBits(), apierror.InvalidKeyLength)
+	}
+	return key, nil
+}
+
+// parseBlock 经由算法解析二进制分组，失败时返回 MISSING_FIELD、INVALID_BINARY 或 INVALID_BLOCK_LENGTH
+func parseBlock(alg cipher.Cipher, field, value string) ([]int, *apierror.Error) {
+	block, err := alg.ParseBlock(value)
+	if err != nil {
+		return nil, apierror.Binary(field, value, alg.BlockBits(), apierror.InvalidBlockLength)
+	}
+	return block, nil
+}
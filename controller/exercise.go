@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"SDES/apierror"
+	"SDES/dto/request"
+	"SDES/dto/response"
+	"SDES/utils"
+	"SDES/utils/exercise"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 未指定数量时生成的题目数
+const defaultExerciseCount = 4
+
+// exerciseSigner 为下发的题目 ID 签名，默认使用进程内随机密钥，可通过 ConfigureExercises 替换
+var exerciseSigner, _ = exercise.NewSigner(nil)
+
+// ConfigureExercises 使用给定密钥（通常为令牌密钥）签名题目 ID，使 ID 在重启与多实例间保持有效；
+// secret 为空时保留随机密钥。须在启动服务前调用
+func ConfigureExercises(secret []byte) error {
+	if len(secret) == 0 {
+		return nil
+	}
+	signer, err := exercise.NewSigner(secret)
+	if err != nil {
+		return err
+	}
+	exerciseSigner = signer
+	return nil
+}
+
+// ExercisesHandler 生成练习题（不含答案）
+func ExercisesHandler(c *gin.Context) {
+	var req request.ExerciseRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	resp, e := exerciseSet(req, false)
+	if e != nil {
+		apierror.Abort(c, e)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ExerciseExportHandler 导出含标准答案与中间步骤的题目 JSON，供教学平台导入
+// 查询参数：seed、count、kinds（逗号分隔）
+func ExerciseExportHandler(c *gin.Context) {
+	var req request.ExerciseRequest
+	var err error
+	if s := c.Query("seed"); s != "" {
+		if req.Seed, err = strconv.ParseUint(s, 10, 64); err != nil {
+			apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.InvalidType, "seed", "seed", "integer"))
+			return
+		}
+	}
+	if s := c.Query("count"); s != "" {
+		if req.Count, err = strconv.Atoi(s); err != nil {
+			apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.InvalidType, "count", "count", "integer"))
+			return
+		}
+	}
+	if s := c.Query("kinds"); s != "" {
+		req.Kinds = strings.Split(s, ",")
+	}
+
+	resp, e := exerciseSet(req, true)
+	if e != nil {
+		apierror.Abort(c, e)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="sdes-exercises-%d.json"`, resp.Seed))
+	c.JSON(http.StatusOK, resp)
+}
+
+// exerciseSet 按请求生成题目，withSolutions 为 true 时附带答案
+func exerciseSet(req request.ExerciseRequest, withSolutions bool) (response.ExerciseResponse, *apierror.Error) {
+	if req.Count == 0 {
+		req.Count = defaultExerciseCount
+	}
+	if req.Count < 1 {
+		return response.ExerciseResponse{}, apierror.New(http.StatusBadRequest, apierror.InvalidValue, "count", "count", "min", "1")
+	}
+	if req.Count > exercise.MaxExercises {
+		return response.ExerciseResponse{}, apierror.New(http.StatusBadRequest, apierror.InvalidValue, "count", "count", "max", strconv.Itoa(exercise.MaxExercises))
+	}
+	kinds := make([]exercise.Kind, 0, len(req.Kinds))
+	for _, name := range req.Kinds {
+		kind, err := exercise.ParseKind(strings.TrimSpace(name))
+		if err != nil {
+			names := make([]string, len(exercise.Kinds))
+			for i, k := range exercise.Kinds {
+				names[i] = string(k)
+			}
+			return response.ExerciseResponse{}, apierror.New(http.StatusBadRequest, apierror.InvalidValue, "kinds", "kinds", "oneof", strings.Join(names, " "))
+		}
+		kinds = append(kinds, kind)
+	}
+	if req.Seed == 0 {
+		seed, err := utils.RandomBits(31)
+		if err != nil {
+			return response.ExerciseResponse{}, apierror.New(http.StatusInternalServerError, apierror.RandomUnavailable, "seed")
+		}
+		req.Seed = uint64(seed) + 1
+	}
+
+	// 数量与题型已校验，GenerateSet 不会出错
+	exercises, solutions, _ := exercise.GenerateSet(req.Seed, req.Count, kinds)
+	resp := response.ExerciseResponse{Seed: req.Seed, Success: true}
+	for i, ex := range exercises {
+		item := response.Exercise{
+			ID:               exerciseSigner.Sign(ex.ID),
+			Kind:             string(ex.Kind),
+			Prompt:           ex.Prompt,
+			Points:           ex.Points,
+			Key:              ex.Key,
+			Plaintext:        ex.Plaintext,
+			CiphertextBase64: ex.CiphertextBase64,
+		}
+		for _, p := range ex.Pairs {
+			item.Pairs = append(item.Pairs, response.ExercisePair{Plaintext: p.Plaintext, Ciphertext: p.Ciphertext})
+		}
+		sol := solutions[i]
+		for _, s := range sol.Stages {
+			item.Stages = append(item.Stages, s.Name)
+		}
+		if withSolutions {
+			item.Solution = &response.ExerciseSolution{
+				Answer:   sol.Answer,
+				Accepted: sol.Accepted,
+			}
+			for _, s := range sol.Stages {
+				item.Solution.Stages = append(item.Solution.Stages, response.TraceStep{Name: s.Name, Value: s.Value})
+			}
+		}
+		resp.Exercises = append(resp.Exercises, item)
+		resp.TotalPoints += ex.Points
+	}
+	return resp, nil
+}
+
+// GradeHandler 批量评分，最终答案错误时按正确的中间步骤给部分分数
+func GradeHandler(c *gin.Context) {
+	var req request.GradeRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if len(req.Answers) > exercise.MaxExercises {
+		apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.InvalidValue, "answers", "answers", "maxItems", strconv.Itoa(exercise.MaxExercises)))
+		return
+	}
+
+	resp := response.GradeResponse{Success: true}
+	for _, a := range req.Answers {
+		// 签名无效或 ID 无法解析的题目单独标记，不影响其他题目评分
+		id, err := exerciseSigner.Verify(a.ID)
+		var result exercise.Result
+		if err == nil {
+			result, err = exercise.Grade(exercise.Answer{ID: id, Answer: a.Answer, Stages: a.Stages})
+		}
+		if err != nil {
+			detail := apierror.Detail(c, apierror.New(http.StatusBadRequest, apierror.InvalidExerciseID, "id", "id"))
+			resp.Results = append(resp.Results, response.GradeResult{ID: a.ID, Feedback: detail.Message, Error: detail})
+			continue
+		}
+		item := response.GradeResult{
+			ID:       a.ID,
+			Kind:     string(result.Kind),
+			Points:   result.Points,
+			Score:    result.Score,
+			Correct:  result.Correct,
+			Feedback: result.Feedback,
+		}
+		for _, s := range result.Stages {
+			item.Stages = append(item.Stages, response.StageResult{Name: s.Name, Given: s.Given, Correct: s.Correct})
+		}
+		resp.Results = append(resp.Results, item)
+		resp.Score += result.Score
+		resp.TotalPoints += result.Points
+	}
+	c.JSON(http.StatusOK, resp)
+}
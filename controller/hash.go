@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"SDES/apierror"
+	"SDES/dto/request"
+	"SDES/dto/response"
+	"SDES/utils"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 碰撞搜索的默认与最大尝试次数
+const (
+	defaultCollisionTrials = 1 << 12
+	maxCollisionTrials     = 1 << 16
+)
+
+// HashHandler 计算基于 S-DES 的玩具哈希
+func HashHandler(c *gin.Context) {
+	var req request.HashRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	construction, ok := hashParams(c, req.Construction, &req.Bits)
+	if !ok {
+		return
+	}
+
+	if textTooLong(c, "message_ascii", len(req.MessageASCII)) {
+		return
+	}
+	message, e := decodeData("message_ascii", req.MessageASCII, encodingASCII)
+	if e != nil {
+		apierror.Abort(c, e)
+		return
+	}
+	if len(message) > utils.MaxHashMessage {
+		apierror.Abort(c, apierror.New(http.StatusRequestEntityTooLarge, apierror.PlaintextTooLong, "message_ascii", "message_ascii", utils.MaxHashMessage))
+		return
+	}
+
+	// 参数均已校验，Hash 不会出错
+	digest, _ := utils.Hash(construction, message, req.Bits)
+
+	// 第一条链的中间值，便于逐步讲解
+	chain, _ := utils.HashChain(construction, message, 0)
+	chainStrings := make([]string, len(chain))
+	for i, h := range chain {
+		chainStrings[i] = utils.BitsToString(utils.ByteToBits(h))
+	}
+
+	c.JSON(http.StatusOK, response.HashResponse{
+		Construction: string(construction),
+		Bits:         req.Bits,
+		Hash:         utils.BitsToString(utils.IntToBits(int(digest), req.Bits)),
+		HashHex:      hashHex(digest, req.Bits),
+		Chain:        chainStrings,
+		Success:      true,
+	})
+}
+
+// CollisionHandler 生日攻击碰撞搜索
+func CollisionHandler(c *gin.Context) {
+	var req request.CollisionRequest
+	var startTime = time.Now()
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	construction, ok := hashParams(c, req.Construction, &req.Bits)
+	if !ok {
+		return
+	}
+	if req.MaxTrials <= 0 {
+		req.MaxTrials = defaultCollisionTrials
+	}
+	if req.MaxTrials > maxCollisionTrials {
+		req.MaxTrials = maxCollisionTrials
+	}
+
+	result, _ := utils.FindCollision(construction, req.Bits, req.Seed, req.MaxTrials)
+	var timeString = fmt.Sprintf("%.2fms", float64(time.Since(startTime).Nanoseconds())/1000000)
+
+	if !result.Found {
+		detail := apierror.Detail(c, apierror.New(http.StatusOK, apierror.NoCollisionFound, "max_trials", result.Trials))
+		c.JSON(http.StatusOK, response.CollisionResponse{
+			Construction:   string(construction),
+			Bits:           req.Bits,
+			Trials:         result.Trials,
+			ExpectedTrials: result.Expected,
+			Success:        false,
+			Message:        detail.Message,
+			Error:          detail,
+			Time:           timeString,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.CollisionResponse{
+		Construction:   string(construction),
+		Bits:           req.Bits,
+		Message1Hex:    hex.EncodeToString(result.Message1),
+		Message2Hex:    hex.EncodeToString(result.Message2),
+		HashHex:        hashHex(result.Digest, req.Bits),
+		Trials:         result.Trials,
+		ExpectedTrials: result.Expected,
+		Success:        true,
+		Time:           timeString,
+	})
+}
+
+// hashParams 解析压缩函数构造与哈希长度（默认 8 位），无效时写入 INVALID_VALUE 并返回 false
+func hashParams(c *gin.Context, name string, bits *int) (utils.Construction, bool) {
+	construction, err := utils.ParseConstruction(name)
+	if err != nil {
+		names := make([]string, len(utils.Constructions))
+		for i, c := range utils.Constructions {
+			names[i] = string(c)
+		}
+		apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.InvalidValue, "construction", "construction", "oneof", strings.Join(names, " ")))
+		return "", false
+	}
+	if *bits == 0 {
+		*bits = 8
+	}
+	if *bits != 8 && *bits != 16 {
+		apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.InvalidValue, "bits", "bits", "oneof", "8 16"))
+		return "", false
+	}
+	return construction, true
+}
+
+// hashHex 按哈希长度格式化十六进制
+func hashHex(digest uint16, bits int) string {
+	return fmt.Sprintf("%0*x", bits/4, digest)
+}
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"SDES/apierror"
+	"SDES/dto/response"
+	"SDES/logging"
+	"SDES/utils/history"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// historyStore 操作历史存储，为 nil 时不记录
+var historyStore *history.Store
+
+// ConfigureHistory 设置操作历史存储，传入 nil 表示禁用
+func ConfigureHistory(store *history.Store) {
+	historyStore = store
+}
+
+// recordHistory 记录一次操作及其耗时，写入失败只记日志不影响响应
+func recordHistory(c *gin.Context, r history.Record, startTime time.Time) {
+	if historyStore == nil {
+		return
+	}
+	r.DurationMs = float64(time.Since(startTime).Nanoseconds()) / 1000000
+	if err := historyStore.Add(r); err != nil {
+		logging.FromContext(c.Request.Context()).Error("写入历史记录失败", "operation", r.Operation, "error", err)
+	}
+}
+
+// historyFilter 从查询参数解析过滤条件：operation、algorithm、success、since、until（RFC3339）、limit、offset
+func historyFilter(c *gin.Context) (history.Filter, *apierror.Error) {
+	f := history.Filter{
+		Operation: c.Query("operation"),
+		Algorithm: c.Query("algorithm"),
+	}
+	if s := c.Query("success"); s != "" {
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return f, apierror.New(http.StatusBadRequest, apierror.InvalidType, "success", "success", "boolean")
+		}
+		f.Success = &v
+	}
+	for name, dst := range map[string]*time.Time{"since": &f.Since, "until": &f.Until} {
+		if s := c.Query(name); s != "" {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return f, apierror.New(http.StatusBadRequest, apierror.InvalidValue, name, name, "format", "date-time")
+			}
+			*dst = t
+		}
+	}
+	for name, dst := range map[string]*int{"limit": &f.Limit, "offset": &f.Offset} {
+		if s := c.Query(name); s != "" {
+			v, err := strconv.Atoi(s)
+			if err != nil || v < 0 {
+				return f, apierror.New(http.StatusBadRequest, apierror.InvalidValue, name, name, "min", "0")
+			}
+			*dst = v
+		}
+	}
+	return f, nil
+}
+
+// historyAvailable 未启用历史记录时写入错误响应
+func historyAvailable(c *gin.Context) bool {
+	if historyStore == nil {
+		apierror.Abort(c, apierror.New(http.StatusNotFound, apierror.HistoryDisabled, ""))
+		return false
+	}
+	return true
+}
+
+// HistoryHandler 查询操作历史，默认返回最近 100 条
+func HistoryHandler(c *gin.Context) {
+	if !historyAvailable(c) {
+		return
+	}
+	f, e := historyFilter(c)
+	if e != nil {
+		apierror.Abort(c, e)
+		return
+	}
+	if f.Limit == 0 {
+		f.Limit = 100
+	}
+
+	records, total := historyStore.List(f)
+	items := make([]response.HistoryRecord, len(records))
+	for i, r := range records {
+		items[i] = response.HistoryRecord{
+			ID:         r.ID,
+			Time:       r.Time.Format(time.RFC3339),
+			Operation:  r.Operation,
+			Algorithm:  r.Algorithm,
+			Mode:       r.Mode,
+			Key:        r.Key,
+			Input:      r.Input,
+			Output:     r.Output,
+			Keys:       r.Keys,
+			DurationMs: r.DurationMs,
+			Success:    r.Success,
+		}
+	}
+	c.JSON(http.StatusOK, response.HistoryResponse{
+		Records: items,
+		Total:   total,
+		Success: true,
+	})
+}
+
+// HistoryExportHandler 按过滤条件导出全部匹配记录，format 为 csv 或 json（默认 json）
+func HistoryExportHandler(c *gin.Context) {
+	if !historyAvailable(c) {
+		return
+	}
+	f, e := historyFilter(c)
+	if e != nil {
+		apierror.Abort(c, e)
+		return
+	}
+	records, _ := historyStore.List(f)
+	filename := "sdes-history-" + time.Now().Format("20060102-150405")
+
+	switch format := c.DefaultQuery("format", "json"); format {
+	case "csv":
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`.csv"`)
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Status(http.StatusOK)
+		if err := history.WriteCSV(c.Writer, records); err != nil {
+			logging.FromContext(c.Request.Context()).Error("导出历史记录失败", "error", err)
+		}
+	case "json":
+		if records == nil {
+			records = []history.Record{}
+		}
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`.json"`)
+		c.JSON(http.StatusOK, records)
+	default:
+		apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.UnsupportedFormat, "format", format))
+	}
+}
+
+// HistoryClearHandler 清空操作历史
+func HistoryClearHandler(c *gin.Context) {
+	if !historyAvailable(c) {
+		return
+	}
+	if err := historyStore.Clear(); err != nil {
+		logging.FromContext(c.Request.Context()).Error("清空历史记录失败", "error", err)
+		apierror.Abort(c, apierror.New(http.StatusInternalServerError, apierror.HistoryStorageFailed, ""))
+		return
+	}
+	c.JSON(http.StatusOK, response.HistoryResponse{
+		Success: true,
+		Message: "历史记录已清空",
+	})
+}
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"SDES/apierror"
+	"SDES/dto/request"
+	"SDES/dto/response"
+	"SDES/utils"
+	"SDES/utils/cipher"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RandomKeyHandler 生成随机密钥与 IV
+// 查询参数 exclude_weak=true 时排除弱密钥与等价冗余密钥
+func RandomKeyHandler(c *gin.Context) {
+	excludeWeak := c.Query("exclude_weak") == "true"
+
+	key, err := utils.RandomKey(excludeWeak)
+	if err != nil {
+		apierror.Abort(c, apierror.New(http.StatusInternalServerError, apierror.KeyGenerationFailed, ""))
+		return
+	}
+	iv, err := utils.RandomBlock()
+	if err != nil {
+		apierror.Abort(c, apierror.New(http.StatusInternalServerError, apierror.KeyGenerationFailed, "iv"))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.RandomKeyResponse{
+		Key:        utils.BitsToString(utils.IntTo10BitKey(key)),
+		KeyDecimal: key,
+		IV:         utils.BitsToString(utils.IntToBits(iv, 8)),
+		IVDecimal:  iv,
+		Success:    true,
+	})
+}
+
+// randomKey 为算法生成随机密钥（十进制）
+// excludeWeak 为 true 且算法实现了 cipher.WeakKeyFilter 时排除弱密钥与等价冗余密钥
+func randomKey(alg cipher.Cipher, excludeWeak bool) (int, error) {
+	if f, ok := alg.(cipher.WeakKeyFilter); ok {
+		return f.RandomKey(excludeWeak)
+	}
+	return utils.RandomBits(alg.KeyBits())
+}
+
+// sdesCipher 返回注册表中的 S-DES；MAC、PRNG、密钥编排等只面向 S-DES 的接口经由它解析密钥
+func sdesCipher() cipher.Cipher {
+	alg, err := cipher.Lookup("sdes")
+	if err != nil {
+		panic(err)
+	}
+	return alg
+}
+
+// KeyScheduleHandler 展示密钥扩展的中间值，或由子密钥反推所有可能的密钥
+func KeyScheduleHandler(c *gin.Context) {
+	var req request.KeyScheduleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if req.Key == "" && req.K1 == "" && req.K2 == "" {
+		apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.MissingField, "key", "key / k1 / k2"))
+		return
+	}
+	var key []int
+	if req.Key != "" {
+		var e *apierror.Error
+		if key, e = parseKey(sdesCipher(), "key", req.Key); e != nil {
+			apierror.Abort(c, e)
+			return
+		}
+	}
+	if (req.K1 != "" && !checkBinary(c, "k1", req.K1, 8, apierror.InvalidKeyLength)) ||
+		(req.K2 != "" && !checkBinary(c, "k2", req.K2, 8, apierror.InvalidKeyLength)) {
+		return
+	}
+
+	var resp response.KeyScheduleResponse
+	if req.Key != "" {
+		trace := utils.KeyScheduleTrace(key)
+		resp.Key = req.Key
+		resp.P10 = utils.BitsToString(trace.P10)
+		for _, s := range trace.Shifts {
+			resp.Shifts = append(resp.Shifts, response.ScheduleShift{
+				Name:  s.Name,
+				Left:  utils.BitsToString(s.Left),
+				Right: utils.BitsToString(s.Right),
+			})
+		}
+		resp.K1 = utils.BitsToString(trace.K1)
+		resp.K2 = utils.BitsToString(trace.K2)
+	}
+
+	if req.K1 != "" || req.K2 != "" {
+		k1, k2 := -1, -1
+		if req.K1 != "" {
+			k1 = utils.BitsToInt(utils.StringToBits(req.K1, 8))
+		}
+		if req.K2 != "" {
+			k2 = utils.BitsToInt(utils.StringToBits(req.K2, 8))
+		}
+		keys := utils.KeysForSubkeys(k1, k2)
+		resp.Keys = keyStrings(keys)
+		count := len(keys)
+		resp.KeyCount = &count
+		resp.Message = fmt.Sprintf("共有 %d 个密钥产生给定的子密钥", count)
+	}
+
+	resp.Success = true
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"SDES/apierror"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxPlaintextBytes ASCII 明文、Base64 解码后的密文及各类文本输入的长度上限
+var maxPlaintextBytes = 4096
+
+// ConfigureLimits 设置文本输入的长度上限，须在启动服务前调用
+func ConfigureLimits(maxPlaintext int) {
+	maxPlaintextBytes = maxPlaintext
+}
+
+// textTooLong 检查文本输入长度，超出上限时写入 413 响应并返回 true
+func textTooLong(c *gin.Context, field string, n int) bool {
+	if n <= maxPlaintextBytes {
+		return false
+	}
+	apierror.Abort(c, apierror.New(http.StatusRequestEntityTooLarge, apierror.PlaintextTooLong, field, field, maxPlaintextBytes))
+	return true
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"SDES/apierror"
+	"SDES/dto/request"
+	"SDES/dto/response"
+	"SDES/utils"
+	"encoding/base64"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MACHandler 计算 CBC-MAC 或 CMAC 标签
+func MACHandler(c *gin.Context) {
+	var req request.MACRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	keyBits, e := parseKey(sdesCipher(), "key", req.Key)
+	if e != nil {
+		apierror.Abort(c, e)
+		return
+	}
+
+	if textTooLong(c, "message_ascii", len(req.MessageASCII)) {
+		return
+	}
+	message, e := decodeData("message_ascii", req.MessageASCII, encodingASCII)
+	if e != nil {
+		apierror.Abort(c, e)
+		return
+	}
+
+	var tag byte
+	switch req.Algorithm {
+	case "", "cmac":
+		req.Algorithm = "cmac"
+		tag = utils.CMAC(message, keyBits)
+	case "cbc-mac":
+		tag = utils.CBCMAC(message, keyBits)
+	default:
+		apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.InvalidValue, "algorithm", "algorithm", "oneof", "cmac cbc-mac"))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.MACResponse{
+		Algorithm: req.Algorithm,
+		Tag:       utils.BitsToString(utils.ByteToBits(tag)),
+		Success:   true,
+	})
+}
+
+// ForgeryHandler 短标签伪造演示
+// 服务端扮演验证方，攻击者篡改密文后逐个尝试截断标签，统计被接受前的提交次数
+func ForgeryHandler(c *gin.Context) {
+	var req request.ForgeryRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if req.TagBits == 0 {
+		req.TagBits = utils.MACTagBits
+	}
+	if !checkRange(c, "tag_bits", req.TagBits, utils.MACTagBits) {
+		return
+	}
+
+	if textTooLong(c, "plaintext_ascii", len(req.PlaintextASCII)) {
+		return
+	}
+	plaintext, e := decodeData("plaintext_ascii", req.PlaintextASCII, encodingASCII)
+	if e != nil {
+		apierror.Abort(c, e)
+		return
+	}
+
+	encKey, ok := demoKey(c, "key", req.Key)
+	if !ok {
+		return
+	}
+	macKey, ok := demoKey(c, "mac_key", req.MACKey)
+	if !ok {
+		return
+	}
+
+	ciphertext, tag := utils.Seal(plaintext, encKey, macKey)
+	verify := func(ct []byte, t byte) bool {
+		return utils.VerifyTag(ct, t, req.TagBits, macKey) == nil
+	}
+
+	// tag_bits 已校验，ForgeTag 不会出错
+	result, _ := utils.ForgeTag(ciphertext, req.TagBits, verify)
+
+	c.JSON(http.StatusOK, response.ForgeryResponse{
+		TagBits:          req.TagBits,
+		OriginalBase64:   base64.StdEncoding.EncodeToString(ciphertext),
+		OriginalTag:      tagString(utils.TruncateTag(tag, req.TagBits), req.TagBits),
+		ForgedBase64:     base64.StdEncoding.EncodeToString(result.Ciphertext),
+		ForgedTag:        tagString(result.Tag, req.TagBits),
+		Attempts:         result.Attempts,
+		ExpectedAttempts: result.Expected,
+		Success:          true,
+	})
+}
+
+// demoKey 解析演示用密钥，为空时随机生成
+func demoKey(c *gin.Context, field, key string) ([]int, bool) {
+	if key == "" {
+		k, err := utils.RandomKey(true)
+		if err != nil {
+			apierror.Abort(c, apierror.New(http.StatusInternalServerError, apierror.KeyGenerationFailed, field))
+			return nil, false
+		}
+		return utils.IntTo10BitKey(k), true
+	}
+	keyBits, e := parseKey(sdesCipher(), field, key)
+	if e != nil {
+		apierror.Abort(c, e)
+		return nil, false
+	}
+	return keyBits, true
+}
+
+// tagString 将截断标签格式化为 bits 位二进制字符串
+func tagString(tag byte, bits int) string {
+	return utils.BitsToString(utils.IntToBits(int(tag), bits))
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"SDES/metrics"
+	"time"
+)
+
+// 暴力破解相关指标；HTTP 请求的次数与耗时由 metrics.Middleware 统一统计
+var (
+	bruteForceDuration = metrics.Default.NewHistogram("sdes_bruteforce_duration_seconds",
+		"暴力破解任务耗时", nil, "algorithm", "method")
+	bruteForceKeys = metrics.Default.NewCounter("sdes_bruteforce_keys_tested_total",
+		"暴力破解测试的密钥总数，rate() 即每秒测试的密钥数", "algorithm", "method")
+	bruteForceKeyRate = metrics.Default.NewGauge("sdes_bruteforce_keys_per_second",
+		"最近一次暴力破解任务每秒测试的密钥数", "algorithm", "method")
+	bruteForceWaiting = metrics.Default.NewGauge("sdes_bruteforce_jobs_waiting",
+		"排队等待暴力破解名额的请求数（队列深度）")
+	bruteForceRejected = metrics.Default.NewCounter("sdes_bruteforce_jobs_rejected_total",
+		"因排队超时、服务器关闭或取消而被拒绝的暴力破解请求数", "reason")
+)
+
+func init() {
+	bruteForceWaiting.Set(0)
+	metrics.Default.NewGaugeFunc("sdes_bruteforce_jobs_active", "正在进行的暴力破解任务数", func() float64 {
+		return float64(len(bruteForceSlots))
+	})
+	metrics.Default.NewGaugeFunc("sdes_bruteforce_jobs_capacity", "同时进行的暴力破解任务上限", func() float64 {
+		return float64(cap(bruteForceSlots))
+	})
+}
+
+// observeBruteForce 记录一次完成的暴力破解任务，keys 为测试的密钥数（0 表示不统计）
+func observeBruteForce(algorithm, method string, keys int, d time.Duration) {
+	bruteForceDuration.Observe(d.Seconds(), algorithm, method)
+	if keys > 0 {
+		bruteForceKeys.Add(float64(keys), algorithm, method)
+		if d > 0 {
+			bruteForceKeyRate.Set(float64(keys)/d.Seconds(), algorithm, method)
+		}
+	}
+}
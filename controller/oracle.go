@@ -0,0 +1,194 @@
+package controller
+
+import (
+	"SDES/apierror"
+	"SDES/dto/request"
+	"SDES/dto/response"
+	"SDES/utils"
+	"SDES/utils/cipher"
+	"SDES/utils/oracle"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// oracleStore 预言机会话存储，可通过 ConfigureOracle 替换配置
+var oracleStore = oracle.NewStore(oracle.DefaultConfig)
+
+// ConfigureOracle 使用新配置重建会话存储，须在启动服务前调用
+func ConfigureOracle(cfg oracle.Config) {
+	oracleStore = oracle.NewStore(cfg)
+}
+
+// OracleSessionHandler 创建挑战会话，服务端生成并保存随机密钥
+func OracleSessionHandler(c *gin.Context) {
+	var req request.OracleSessionRequest
+	// 请求体可以为空
+	if c.Request.ContentLength > 0 {
+		if !bindJSON(c, &req) {
+			return
+		}
+	}
+	alg, ok := lookupCipher(c, req.Algorithm)
+	if !ok {
+		return
+	}
+
+	snap, err := oracleStore.Create(alg)
+	if err != nil {
+		abortOracle(c, oracle.Snapshot{}, err)
+		return
+	}
+	c.JSON(http.StatusOK, response.OracleResponse{
+		Session: oracleSession(snap),
+		Success: true,
+		Message: fmt.Sprintf("会话已创建，可进行 %d 次加密、%d 次解密查询", snap.EncryptLeft, snap.DecryptLeft),
+	})
+}
+
+// OracleStatusHandler 查询会话状态与得分
+func OracleStatusHandler(c *gin.Context) {
+	snap, err := oracleStore.Get(c.Param("id"))
+	if err != nil {
+		abortOracle(c, oracle.Snapshot{}, err)
+		return
+	}
+	c.JSON(http.StatusOK, response.OracleResponse{
+		Session: oracleSession(snap),
+		Success: true,
+	})
+}
+
+// OracleEncryptHandler 选择明文查询
+func OracleEncryptHandler(c *gin.Context) {
+	oracleQuery(c, true)
+}
+
+// OracleDecryptHandler 选择密文查询
+func OracleDecryptHandler(c *gin.Context) {
+	oracleQuery(c, false)
+}
+
+// oracleQuery 加密与解密查询共用的请求处理
+func oracleQuery(c *gin.Context, encrypt bool) {
+	var req request.OracleQueryRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	snap, err := oracleStore.Get(req.SessionID)
+	if err != nil {
+		abortOracle(c, oracle.Snapshot{}, err)
+		return
+	}
+	alg, _ := cipher.Lookup(snap.Algorithm)
+	block, e := parseBlock(alg, "block", req.Block)
+	if e != nil {
+		apierror.Abort(c, e)
+		return
+	}
+
+	var result []int
+	if encrypt {
+		result, snap, err = oracleStore.Encrypt(req.SessionID, block)
+	} else {
+		result, snap, err = oracleStore.Decrypt(req.SessionID, block)
+	}
+	if err != nil {
+		abortOracle(c, snap, err)
+		return
+	}
+	c.JSON(http.StatusOK, response.OracleResponse{
+		Session: oracleSession(snap),
+		Result:  utils.BitsToString(result),
+		Success: true,
+	})
+}
+
+// OracleSubmitHandler 提交猜测的密钥
+func OracleSubmitHandler(c *gin.Context) {
+	var req request.OracleSubmitRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	snap, err := oracleStore.Get(req.SessionID)
+	if err != nil {
+		abortOracle(c, oracle.Snapshot{}, err)
+		return
+	}
+	alg, _ := cipher.Lookup(snap.Algorithm)
+	key, e := parseKey(alg, "key", req.Key)
+	if e != nil {
+		apierror.Abort(c, e)
+		return
+	}
+
+	correct, snap, err := oracleStore.Submit(req.SessionID, key)
+	if err != nil {
+		abortOracle(c, snap, err)
+		return
+	}
+	message := fmt.Sprintf("密钥错误，还可提交 %d 次", snap.AttemptsLeft)
+	if correct {
+		message = fmt.Sprintf("破解成功！得分 %d", snap.Score)
+	}
+	c.JSON(http.StatusOK, response.OracleResponse{
+		Session: oracleSession(snap),
+		Correct: &correct,
+		Success: true,
+		Message: message,
+	})
+}
+
+// oracleError 将预言机错误映射为 API 错误
+// 查询或提交次数用完后重试不会成功，返回 409 而不是与限流相同的 429
+func oracleError(err error) *apierror.Error {
+	switch {
+	case errors.Is(err, oracle.ErrSessionNotFound):
+		return apierror.New(http.StatusNotFound, apierror.OracleSessionNotFound, "session_id")
+	case errors.Is(err, oracle.ErrBudgetExhausted):
+		return apierror.New(http.StatusConflict, apierror.OracleBudgetExhausted, "session_id")
+	case errors.Is(err, oracle.ErrAttemptsExhausted):
+		return apierror.New(http.StatusConflict, apierror.OracleAttemptsExhausted, "session_id")
+	case errors.Is(err, oracle.ErrAlreadySolved):
+		return apierror.New(http.StatusConflict, apierror.OracleAlreadySolved, "session_id")
+	case errors.Is(err, oracle.ErrTooManySessions):
+		return apierror.New(http.StatusServiceUnavailable, apierror.OracleSessionsFull, "")
+	}
+	return apierror.New(http.StatusInternalServerError, apierror.KeyGenerationFailed, "")
+}
+
+// abortOracle 写入预言机错误响应，会话仍存在时一并返回其状态
+func abortOracle(c *gin.Context, snap oracle.Snapshot, err error) {
+	e := oracleError(err)
+	detail := apierror.Detail(c, e)
+	c.AbortWithStatusJSON(e.Status, response.OracleResponse{
+		Session: oracleSession(snap),
+		Success: false,
+		Message: detail.Message,
+		Error:   detail,
+	})
+}
+
+// oracleSession 将会话状态转换为响应结构，不含密钥
+func oracleSession(snap oracle.Snapshot) *response.OracleSession {
+	if snap.ID == "" {
+		return nil
+	}
+	return &response.OracleSession{
+		SessionID:     snap.ID,
+		Algorithm:     snap.Algorithm,
+		BlockBits:     snap.BlockBits,
+		KeyBits:       snap.KeyBits,
+		EncryptLeft:   snap.EncryptLeft,
+		DecryptLeft:   snap.DecryptLeft,
+		AttemptsLeft:  snap.AttemptsLeft,
+		Queries:       snap.Queries,
+		WrongAttempts: snap.WrongAttempts,
+		Solved:        snap.Solved,
+		Score:         snap.Score,
+		ExpiresAt:     snap.ExpiresAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"SDES/apierror"
+	"SDES/dto/request"
+	"SDES/dto/response"
+	"SDES/utils"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PRNG 输出长度限制（字节）
+const (
+	defaultPRNGLength = 1024
+	maxPRNGLength     = 1 << 16
+)
+
+// PRNGHandler 生成伪随机字节并运行统计检验
+func PRNGHandler(c *gin.Context) {
+	var req request.PRNGRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if req.Length <= 0 {
+		req.Length = defaultPRNGLength
+	}
+	if !checkRange(c, "length", req.Length, maxPRNGLength) {
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = string(utils.PRNGModeOFB)
+	}
+	if req.Mode != "crypto" && req.Mode != string(utils.PRNGModeCTR) && req.Mode != string(utils.PRNGModeOFB) {
+		apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.InvalidValue, "mode", "mode", "oneof", "ctr ofb crypto"))
+		return
+	}
+
+	output := make([]byte, req.Length)
+	var period int
+
+	if req.Mode == "crypto" {
+		if _, err := rand.Read(output); err != nil {
+			apierror.Abort(c, apierror.New(http.StatusInternalServerError, apierror.RandomUnavailable, ""))
+			return
+		}
+	} else {
+		sdes := sdesCipher()
+		keyBits, e := parseKey(sdes, "key", req.Key)
+		if e != nil {
+			apierror.Abort(c, e)
+			return
+		}
+		ivBits, e := parseBlock(sdes, "iv", req.IV)
+		if e != nil {
+			apierror.Abort(c, e)
+			return
+		}
+		// 模式已校验，NewPRNG 不会出错
+		prng, _ := utils.NewPRNG(keyBits, utils.BitsToByte(ivBits), utils.PRNGMode(req.Mode))
+		_, _ = prng.Read(output)
+		period = prng.Period()
+	}
+
+	resp := response.PRNGResponse{
+		Mode:      req.Mode,
+		Length:    req.Length,
+		OutputHex: hex.EncodeToString(output),
+		Period:    period,
+		Report:    statReport(utils.RunStatTests(output)),
+		Success:   true,
+	}
+
+	if req.Compare {
+		reference := make([]byte, req.Length)
+		if _, err := rand.Read(reference); err == nil {
+			resp.CryptoReport = statReport(utils.RunStatTests(reference))
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// statReport 转换为响应结构
+func statReport(r utils.StatReport) *response.StatReport {
+	results := make([]response.StatResult, len(r.Results))
+	for i, res := range r.Results {
+		results[i] = response.StatResult{
+			Name:      res.Name,
+			Statistic: res.Statistic,
+			PValue:    res.PValue,
+			Passed:    res.Passed,
+			Detail:    res.Detail,
+		}
+	}
+	return &response.StatReport{
+		Bits:    r.Bits,
+		Period:  r.Period,
+		Results: results,
+		Passed:  r.Passed,
+		Total:   r.Total,
+	}
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"SDES/apierror"
+	"SDES/dto/request"
+	"SDES/dto/response"
+	"SDES/utils"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RelatedKeysHandler 相关密钥分析与滑动攻击演示
+func RelatedKeysHandler(c *gin.Context) {
+	var req request.RelatedKeysRequest
+	startTime := time.Now()
+	if !bindJSON(c, &req) {
+		return
+	}
+	var keyBits []int
+	if req.Key != "" {
+		var e *apierror.Error
+		if keyBits, e = parseKey(sdesCipher(), "key", req.Key); e != nil {
+			apierror.Abort(c, e)
+			return
+		}
+	}
+	if req.Subkey != "" && !checkBinary(c, "subkey", req.Subkey, 8, apierror.InvalidKeyLength) {
+		return
+	}
+	if req.Rounds == 0 {
+		req.Rounds = 16
+	}
+	if req.KnownPlaintexts == 0 {
+		req.KnownPlaintexts = 64
+	}
+	if req.KnownPlaintexts < 2 {
+		apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.InvalidValue, "known_plaintexts", "known_plaintexts", "min", "2"))
+		return
+	}
+	if !checkRange(c, "known_plaintexts", req.KnownPlaintexts, utils.BlockSpace) {
+		return
+	}
+
+	variant, err := utils.NewSlideFeistel(req.Rounds)
+	if err != nil {
+		apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.InvalidRounds, "rounds", utils.MaxSlideRounds))
+		return
+	}
+	var subkey int
+	if req.Subkey != "" {
+		subkey = utils.BitsToInt(utils.StringToBits(req.Subkey, 8))
+	} else if subkey, err = utils.RandomBits(8); err != nil {
+		apierror.Abort(c, apierror.New(http.StatusInternalServerError, apierror.KeyGenerationFailed, "subkey"))
+		return
+	}
+
+	report := utils.RelatedKeys()
+	resp := response.RelatedKeysResponse{
+		SingleBitDiffs:  subkeyDifferences(report.SingleBitDiffs),
+		ZeroK1Diffs:     subkeyDifferences(report.ZeroK1Diffs),
+		ZeroK2Diffs:     subkeyDifferences(report.ZeroK2Diffs),
+		SharedK1Pairs:   report.SharedK1Pairs,
+		SharedK2Pairs:   report.SharedK2Pairs,
+		EquivalentPairs: report.EquivalentPairs,
+		SlidePairs:      report.SlidePairs,
+		Success:         true,
+	}
+	if req.Key != "" {
+		key := utils.BitsToInt(keyBits)
+		resp.Key = req.Key
+		resp.SameK1 = keyStrings(utils.KeysSharingK1(key))
+		resp.SameK2 = keyStrings(utils.KeysSharingK2(key))
+		resp.SlidePartners = keyStrings(utils.SlidePartners(key))
+	}
+
+	slide, err := utils.SlideAttack(variant, subkey, req.KnownPlaintexts, req.Seed)
+	resp.Slide = &response.SlideAttackResult{
+		Rounds:                 slide.Rounds,
+		Subkey:                 utils.BitsToString(utils.IntToBits(subkey, 8)),
+		KnownPlaintexts:        req.KnownPlaintexts,
+		PairsTested:            slide.PairsTested,
+		SlidPairs:              slide.SlidPairs,
+		CandidateKeys:          make([]string, 0, len(slide.CandidateKeys)),
+		Recovered:              slide.Recovered,
+		FEvaluations:           slide.FEvaluations,
+		BruteForceFEvaluations: slide.BruteForceFEvaluations,
+	}
+	for _, k := range slide.CandidateKeys {
+		resp.Slide.CandidateKeys = append(resp.Slide.CandidateKeys, utils.BitsToString(utils.IntToBits(k, 8)))
+	}
+	if err != nil {
+		resp.Slide.Message = err.Error()
+	} else if slide.Recovered {
+		resp.Slide.RecoveredKey = utils.BitsToString(utils.IntToBits(slide.RecoveredKey, 8))
+	}
+
+	resp.Time = fmt.Sprintf("%.2fms", float64(time.Since(startTime).Nanoseconds())/1000000)
+	c.JSON(http.StatusOK, resp)
+}
+
+// subkeyDifferences 将子密钥差分转换为二进制字符串形式
+func subkeyDifferences(diffs []utils.SubkeyDifference) []response.SubkeyDifference {
+	result := make([]response.SubkeyDifference, len(diffs))
+	for i, d := range diffs {
+		result[i] = response.SubkeyDifference{
+			KeyDiff: utils.BitsToString(utils.IntTo10BitKey(d.KeyDiff)),
+			K1Diff:  utils.BitsToString(utils.IntToBits(d.K1Diff, 8)),
+			K2Diff:  utils.BitsToString(utils.IntToBits(d.K2Diff, 8)),
+		}
+	}
+	return result
+}
+
+// keyStrings 将十进制密钥转换为 10 位二进制字符串
+func keyStrings(keys []int) []string {
+	result := make([]string, len(keys))
+	for i, k := range keys {
+		result[i] = utils.BitsToString(utils.IntTo10BitKey(k))
+	}
+	return result
+}
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"SDES/apierror"
+	"SDES/dto/request"
+	"SDES/dto/response"
+	"SDES/utils"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TMTOHandler 在固定选择明文上构造 Hellman 表或彩虹表，评估整个密钥空间的恢复成功率
+func TMTOHandler(c *gin.Context) {
+	var req request.TMTORequest
+	startTime := time.Now()
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	// 参数在占用暴力破解名额之前校验，无效请求不会挤占并发槽位
+	method, err := utils.ParseTMTOMethod(req.Method)
+	if err != nil {
+		apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.InvalidValue, "method", "method", "oneof", "hellman rainbow"))
+		return
+	}
+	if req.ChainLength == 0 {
+		req.ChainLength = 32
+	}
+	if req.Chains == 0 {
+		req.Chains = 32
+	}
+	if req.Tables == 0 {
+		req.Tables = 4
+	}
+	if !checkRange(c, "chain_length", req.ChainLength, utils.MaxTMTOChainLength) ||
+		!checkRange(c, "chains", req.Chains, utils.MaxTMTOChains) ||
+		!checkRange(c, "tables", req.Tables, utils.MaxTMTOTables) {
+		return
+	}
+	var key []int
+	if req.Key != "" {
+		var e *apierror.Error
+		if key, e = parseKey(sdesCipher(), "key", req.Key); e != nil {
+			apierror.Abort(c, e)
+			return
+		}
+	}
+
+	if !acquireBruteForce(c) {
+		return
+	}
+	defer releaseBruteForce()
+
+	jobStart := time.Now()
+	tables, err := utils.BuildTMTO(utils.TMTOParams{
+		Method:      method,
+		ChainLength: req.ChainLength,
+		Chains:      req.Chains,
+		Tables:      req.Tables,
+		Seed:        req.Seed,
+	}, utils.DefaultCodebook())
+	if err != nil {
+		apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.InvalidRequest, ""))
+		return
+	}
+	// 预计算与评估之间检查服务器是否正在关闭
+	if bruteForceCtx.Err() != nil {
+		apierror.Abort(c, errBruteForceCancelled)
+		return
+	}
+	report := tables.Evaluate()
+	observeBruteForce("sdes", "tmto-"+string(method), 0, time.Since(jobStart))
+
+	resp := response.TMTOResponse{
+		Method:          string(method),
+		Plaintext:       utils.BitsToString(utils.ByteToBits(utils.TMTOPlaintext[0])) + utils.BitsToString(utils.ByteToBits(utils.TMTOPlaintext[1])),
+		ChainLength:     req.ChainLength,
+		Chains:          req.Chains,
+		Tables:          req.Tables,
+		StoredChains:    report.StoredChains,
+		MemoryBytes:     report.MemoryBytes,
+		CodebookBytes:   utils.CodebookSize,
+		PrecomputeEvals: report.PrecomputeEvals,
+		SuccessRate:     report.SuccessRate,
+		AvgEvaluations:  report.AvgEvaluations,
+		FalseAlarms:     report.FalseAlarms,
+		Success:         true,
+		Message:         fmt.Sprintf("%d 条链覆盖了 %.1f%% 的密钥", report.StoredChains, report.SuccessRate*100),
+	}
+
+	if req.Key != "" {
+		ciphertext := tables.EncryptChosen(utils.BitsToInt(key))
+		key, ok, _, _ := tables.Recover(ciphertext)
+		resp.TargetKey = req.Key
+		resp.TargetCiphertext = utils.BitsToString(utils.IntToBits(int(ciphertext), 16))
+		resp.Recovered = &ok
+		if ok {
+			resp.RecoveredKey = utils.BitsToString(utils.IntTo10BitKey(key))
+		}
+	}
+
+	resp.Time = fmt.Sprintf("%.2fms", float64(time.Since(startTime).Nanoseconds())/1000000)
+	c.JSON(http.StatusOK, resp)
+}
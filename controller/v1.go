@@ -0,0 +1,415 @@
+package controller
+
+import (
+	"SDES/apierror"
+	"SDES/dto/request"
+	"SDES/dto/response"
+	"SDES/logging"
+	"SDES/utils"
+	"SDES/utils/cipher"
+	"SDES/utils/history"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// /api/v1 的加解密与暴力破解
+// 旧接口 /api/encrypt、/api/decrypt、/api/blasting 将请求转换为 v1 的结构后调用同一实现（见 encrypt.go 等）
+
+// 数据编码
+const (
+	encodingBinary = "binary"
+	encodingHex    = "hex"
+	encodingBase64 = "base64"
+	encodingASCII  = "ascii"
+)
+
+// cipherResult v1 加解密结果
+type cipherResult struct {
+	alg      cipher.Cipher
+	req      request.CipherRequest // 已填充默认值
+	data     string                // 按 OutputEncoding 编码的结果
+	tag      string
+	tagValid *bool
+	// generated 密钥与 IV 由 auto_key 生成，需在响应中返回
+	generated bool
+}
+
+// CipherEncryptHandler POST /api/v1/encrypt
+func CipherEncryptHandler(c *gin.Context) {
+	cipherHandler(c, true)
+}
+
+// CipherDecryptHandler POST /api/v1/decrypt
+func CipherDecryptHandler(c *gin.Context) {
+	cipherHandler(c, false)
+}
+
+func cipherHandler(c *gin.Context, encrypt bool) {
+	startTime := time.Now()
+	var req request.CipherRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	res, e := runCipher(c, req, encrypt, startTime)
+	if e != nil {
+		if res == nil {
+			apierror.Abort(c, e)
+			return
+		}
+		// 标签不匹配时仍返回 tag_valid
+		c.JSON(e.Status, response.CipherResponse{
+			Algorithm: res.alg.Name(),
+			Mode:      res.req.Mode,
+			Encoding:  res.req.OutputEncoding,
+			TagValid:  res.tagValid,
+			Success:   false,
+			Error:     apierror.Detail(c, e),
+		})
+		return
+	}
+	resp := response.CipherResponse{
+		Algorithm: res.alg.Name(),
+		Mode:      res.req.Mode,
+		Encoding:  res.req.OutputEncoding,
+		Data:      res.data,
+		Tag:       res.tag,
+		TagValid:  res.tagValid,
+		Success:   true,
+	}
+	if res.generated {
+		resp.Key, resp.IV = res.req.Key, res.req.IV
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// withDefaults 填充默认的算法、工作模式、补齐方式与编码
+func withDefaults(req request.CipherRequest, encrypt bool) request.CipherRequest {
+	if req.Algorithm == "" {
+		req.Algorithm = cipher.DefaultName
+	}
+	if req.Mode == "" {
+		req.Mode = cipher.ModeECB
+	}
+	if req.Padding == "" {
+		req.Padding = cipher.PaddingPKCS7
+	}
+	in, out := encodingASCII, encodingBase64
+	if !encrypt {
+		in, out = out, in
+	}
+	if req.InputEncoding == "" {
+		req.InputEncoding = in
+	}
+	if req.OutputEncoding == "" {
+		req.OutputEncoding = out
+	}
+	return req
+}
+
+// runCipher 执行 v1 加解密，成功时记录历史
+// 返回的错误使用 v1 的字段名；标签不匹配时同时返回结果（tagValid 为 false）
+func runCipher(c *gin.Context, req request.CipherRequest, encrypt bool, startTime time.Time) (*cipherResult, *apierror.Error) {
+	req = withDefaults(req, encrypt)
+	alg, err := cipher.Lookup(req.Algorithm)
+	if err != nil {
+		return nil, apierror.New(http.StatusBadRequest, apierror.UnsupportedAlgorithm, "algorithm", req.Algorithm)
+	}
+	generated := encrypt && req.AutoKey
+	if generated {
+		if req, err = withRandomKey(alg, req); err != nil {
+			return nil, apierror.New(http.StatusInternalServerError, apierror.KeyGenerationFailed, "")
+		}
+	}
+	key, e := parseKey(alg, "key", req.Key)
+	if e != nil {
+		return nil, e
+	}
+
+	// 加密时提供 mac_key 即计算标签，解密时提供 tag 或 mac_key 即先校验标签
+	var (
+		mac    cipher.MACCipher
+		macKey []int
+	)
+	if req.MACKey != "" || (!encrypt && req.Tag != "") {
+		var ok bool
+		if mac, ok = alg.(cipher.MACCipher); !ok {
+			return nil, apierror.New(http.StatusBadRequest, apierror.AuthUnsupported, "algorithm")
+		}
+		if !encrypt {
+			if e := apierror.Binary("tag", req.Tag, mac.TagBits(), apierror.InvalidBlockLength); e != nil {
+				return nil, e
+			}
+		}
+		if e := apierror.Binary("mac_key", req.MACKey, mac.MACKeyBits(), apierror.InvalidKeyLength); e != nil {
+			return nil, e
+		}
+		macKey = utils.StringToBits(req.MACKey, mac.MACKeyBits())
+	}
+
+	var iv []byte
+	if cipher.NeedsIV(req.Mode) {
+		if e := apierror.Binary("iv", req.IV, alg.BlockBits(), apierror.InvalidBlockLength); e != nil {
+			return nil, e
+		}
+		iv, _ = decodeData("iv", req.IV, encodingBinary)
+	}
+
+	data, e := decodeData("data", req.Data, req.InputEncoding)
+	if e != nil {
+		return nil, e
+	}
+	if len(data) > maxPlaintextBytes {
+		return nil, apierror.New(http.StatusRequestEntityTooLarge, apierror.PlaintextTooLong, "data", "data", maxPlaintextBytes)
+	}
+	if len(data) == 0 {
+		return nil, apierror.New(http.StatusBadRequest, apierror.EmptyInput, "data", "data")
+	}
+
+	res := &cipherResult{alg: alg, req: req, generated: generated}
+	var out []byte
+	if encrypt {
+		out, err = cipher.EncryptMode(alg, req.Mode, data, key, iv, req.Padding)
+		if err != nil {
+			return nil, apierror.New(http.StatusBadRequest, apierror.InvalidDataLength, "data", "data", alg.BlockBits())
+		}
+		if mac != nil {
+			res.tag = utils.BitsToString(mac.Tag(out, macKey))
+		}
+	} else {
+		blockBytes := cipher.BlockBytes(alg)
+		if req.Mode != cipher.ModeCTR && len(data)%blockBytes != 0 {
+			return nil, apierror.New(http.StatusBadRequest, apierror.InvalidCiphertextLength, "data", "data", blockBytes)
+		}
+		if mac != nil {
+			valid := mac.VerifyTag(data, utils.StringToBits(req.Tag, mac.TagBits()), macKey)
+			res.tagValid = &valid
+			if !valid {
+				return res, apierror.New(http.StatusBadRequest, apierror.TagMismatch, "tag")
+			}
+		}
+		out, err = cipher.DecryptMode(alg, req.Mode, data, key, iv, req.Padding)
+		if errors.Is(err, cipher.ErrPadding) {
+			return nil, apierror.New(http.StatusBadRequest, apierror.InvalidPadding, "data", "data")
+		}
+		if err != nil {
+			return nil, apierror.New(http.StatusBadRequest, apierror.InvalidCiphertextLength, "data", "data", blockBytes)
+		}
+	}
+	res.data = encodeData(out, req.OutputEncoding)
+
+	operation := "encrypt"
+	if !encrypt {
+		operation = "decrypt"
+	}
+	recordHistory(c, history.Record{
+		Operation: operation,
+		Algorithm: alg.Name(),
+		Mode:      req.Mode + "/" + req.InputEncoding,
+		Key:       req.Key,
+		Input:     req.Data,
+		Output:    res.data,
+		Success:   true,
+	}, startTime)
+	return res, nil
+}
+
+// withRandomKey 用 crypto/rand 生成密钥，非 ECB 模式同时生成 IV
+func withRandomKey(alg cipher.Cipher, req request.CipherRequest) (request.CipherRequest, error) {
+	k, err := randomKey(alg, req.ExcludeWeakKeys)
+	if err != nil {
+		return req, err
+	}
+	req.Key = utils.BitsToString(utils.IntToBits(k, alg.KeyBits()))
+	req.IV = ""
+	if cipher.NeedsIV(req.Mode) {
+		iv, err := utils.RandomBits(alg.BlockBits())
+		if err != nil {
+			return req, err
+		}
+		req.IV = utils.BitsToString(utils.IntToBits(iv, alg.BlockBits()))
+	}
+	return req, nil
+}
+
+// decodeData 按编码解码输入
+func decodeData(field, s, encoding string) ([]byte, *apierror.Error) {
+	switch encoding {
+	case encodingBinary:
+		if strings.Trim(s, "01") != "" {
+			return nil, apierror.New(http.StatusBadRequest, apierror.InvalidBinary, field, field)
+		}
+		if len(s)%8 != 0 {
+			return nil, apierror.New(http.StatusBadRequest, apierror.InvalidDataLength, field, field, 8)
+		}
+		b := make([]byte, len(s)/8)
+		for i := range b {
+			b[i] = utils.BitsToByte(utils.StringToBits(s[i*8:i*8+8], 8))
+		}
+		return b, nil
+	case encodingHex:
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, apierror.New(http.StatusBadRequest, apierror.InvalidHex, field, field)
+		}
+		return b, nil
+	case encodingBase64:
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, apierror.New(http.StatusBadRequest, apierror.Base64DecodeFailed, field, field)
+		}
+		return b, nil
+	}
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r > 255 {
+			return nil, apierror.New(http.StatusBadRequest, apierror.InvalidASCII, field, field, r)
+		}
+		b = append(b, byte(r))
+	}
+	return b, nil
+}
+
+// encodeData 按编码输出结果
+func encodeData(b []byte, encoding string) string {
+	switch encoding {
+	case encodingBinary:
+		var sb strings.Builder
+		for _, x := range b {
+			sb.WriteString(utils.BitsToString(utils.ByteToBits(x)))
+		}
+		return sb.String()
+	case encodingHex:
+		return hex.EncodeToString(b)
+	case encodingBase64:
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	return utils.BytesToASCIIString(b)
+}
+
+// legacyError 将错误中的 v1 字段名替换为旧接口的字段名
+func legacyError(e *apierror.Error, fields map[string]string) *apierror.Error {
+	old, ok := fields[e.Field]
+	if !ok {
+		return e
+	}
+	renamed := *e
+	renamed.Field = old
+	renamed.Args = slices.Clone(e.Args)
+	for i, arg := range renamed.Args {
+		if arg == e.Field {
+			renamed.Args[i] = old
+		}
+	}
+	return &renamed
+}
+
+// CrackHandler POST /api/v1/crack
+func CrackHandler(c *gin.Context) {
+	startTime := time.Now()
+	var req request.CrackRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	res, ok := runCrack(c, req, startTime)
+	if !ok {
+		return
+	}
+	keys := res.keys
+	if keys == nil {
+		keys = []string{}
+	}
+	c.JSON(http.StatusOK, response.CrackResponse{
+		Algorithm:  res.alg.Name(),
+		Keys:       keys,
+		KeyCount:   len(keys),
+		DurationMs: float64(time.Since(startTime).Microseconds()) / 1000,
+		Success:    true,
+	})
+}
+
+// crackResult 暴力破解结果
+type crackResult struct {
+	alg         cipher.Cipher
+	keys        []string
+	keysDecimal []int
+}
+
+// runCrack 校验请求并执行暴力破解，记录指标与历史
+// 出错（参数无效、名额已满、服务器关闭）时写入错误响应并返回 false
+func runCrack(c *gin.Context, req request.CrackRequest, startTime time.Time) (*crackResult, bool) {
+	alg, ok := lookupCipher(c, req.Algorithm)
+	if !ok {
+		return nil, false
+	}
+	// 将输入的明文和密文转换为位数组
+	plaintextBits, e := parseBlock(alg, "plaintext", req.Plaintext)
+	if e != nil {
+		apierror.Abort(c, e)
+		return nil, false
+	}
+	ciphertextBits, e := parseBlock(alg, "ciphertext", req.Ciphertext)
+	if e != nil {
+		apierror.Abort(c, e)
+		return nil, false
+	}
+	logger := logging.FromContext(c.Request.Context())
+	logger.Debug("开始暴力破解", "algorithm", alg.Name(), "rounds", req.Rounds,
+		"plaintext", req.Plaintext, "ciphertext", req.Ciphertext)
+
+	if !acquireBruteForce(c) {
+		return nil, false
+	}
+	defer releaseBruteForce()
+
+	res := &crackResult{alg: alg}
+	jobStart := time.Now()
+	method := "generic"
+	if searcher, ok := alg.(cipher.KeySearcher); ok {
+		var err error
+		res.keysDecimal, method, err = searcher.SearchKeys(plaintextBits, ciphertextBits, req.Rounds)
+		if err != nil {
+			apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.InvalidRounds, "rounds", utils.MaxFeistelRounds))
+			return nil, false
+		}
+		for _, k := range res.keysDecimal {
+			res.keys = append(res.keys, utils.BitsToString(utils.IntToBits(k, alg.KeyBits())))
+		}
+	} else {
+		ctx, cancel := bruteForceContext(c)
+		defer cancel()
+		var err error
+		res.keys, res.keysDecimal, err = bruteForce(ctx, alg, plaintextBits, ciphertextBits)
+		if err != nil {
+			logger.Warn("暴力破解已取消", "algorithm", alg.Name())
+			bruteForceRejected.Inc("cancelled")
+			apierror.Abort(c, errBruteForceCancelled)
+			return nil, false
+		}
+	}
+	// 查密码本不逐个测试密钥，与 TMTO 一样不计入测试的密钥数
+	keysTested := 1 << alg.KeyBits()
+	if method == cipher.SearchCodebook {
+		keysTested = 0
+	}
+	observeBruteForce(alg.Name(), method, keysTested, time.Since(jobStart))
+	recordHistory(c, history.Record{
+		Operation: "blasting",
+		Algorithm: alg.Name(),
+		Input:     req.Plaintext + "," + req.Ciphertext,
+		Output:    strconv.Itoa(len(res.keys)),
+		Keys:      res.keys,
+		Success:   len(res.keys) > 0,
+	}, startTime)
+	logger.Info("暴力破解完成", "algorithm", alg.Name(), "key_count", len(res.keys))
+	// 找到的密钥只在 debug 级别记录，未开启 LogSecrets 时显示为 ***
+	logger.Debug("暴力破解结果", "algorithm", alg.Name(), "keys", res.keys)
+	return res, true
+}
@@ -1,20 +1,200 @@
 package request
 
 // DecryptRequest API 请求结构体
+// 提供 Tag 时先使用 MACKey 校验 CMAC 标签，校验通过才解密
+// Algorithm 为 sdes（默认）或 saes，决定密钥与分组长度
 type DecryptRequest struct {
 	Ciphertext       string  `json:"ciphertext"`
 	CiphertextBase64 *string `json:"ciphertext_base64"`
 	Key              string  `json:"key" binding:"required"`
+	Tag              *string `json:"tag"`
+	MACKey           string  `json:"mac_key"`
+	Algorithm        string  `json:"algorithm"`
 }
 
 // EncryptRequest API 请求结构体
+// AutoKey 为 true 时忽略 Key，由服务端随机生成密钥
+// Authenticated 为 true 时使用 MACKey 对密文计算 CMAC 标签（先加密后认证）
+// Algorithm 为 sdes（默认）或 saes
 type EncryptRequest struct {
-	Plaintext      string  `json:"plaintext"`
-	PlaintextASCII *string `json:"plaintext_ascii"`
-	Key            string  `json:"key" binding:"required"`
+	Plaintext       string  `json:"plaintext"`
+	PlaintextASCII  *string `json:"plaintext_ascii"`
+	Key             string  `json:"key" binding:"required_without=AutoKey"`
+	AutoKey         bool    `json:"auto_key"`
+	ExcludeWeakKeys bool    `json:"exclude_weak_keys"`
+	Authenticated   bool    `json:"authenticated"`
+	MACKey          string  `json:"mac_key"`
+	Algorithm       string  `json:"algorithm"`
 }
 
+// Rounds 为 0 或 2 时使用标准 S-DES，其余值使用对应轮数的 Feistel 变体（仅 S-DES）
 type BlastingRequest struct {
 	Plaintext  string `json:"plaintext"`
 	Ciphertext string `json:"ciphertext"`
+	Rounds     int    `json:"rounds"`
+	Algorithm  string `json:"algorithm"`
+}
+
+// MACRequest 计算消息认证码
+type MACRequest struct {
+	MessageASCII string `json:"message_ascii"`
+	Key          string `json:"key" binding:"required"`
+	Algorithm    string `json:"algorithm"`
+}
+
+// ForgeryRequest 短标签伪造演示，密钥为空时随机生成
+type ForgeryRequest struct {
+	PlaintextASCII string `json:"plaintext_ascii" binding:"required"`
+	Key            string `json:"key"`
+	MACKey         string `json:"mac_key"`
+	TagBits        int    `json:"tag_bits"`
+}
+
+// HashRequest 计算玩具哈希，Bits 为 8 或 16（默认 8）
+type HashRequest struct {
+	MessageASCII string `json:"message_ascii"`
+	Construction string `json:"construction"`
+	Bits         int    `json:"bits"`
+}
+
+// CollisionRequest 生日攻击碰撞搜索
+type CollisionRequest struct {
+	Construction string `json:"construction"`
+	Bits         int    `json:"bits"`
+	Seed         uint64 `json:"seed"`
+	MaxTrials    int    `json:"max_trials"`
+}
+
+// PRNGRequest 生成伪随机字节并运行统计检验
+// Mode 为 ctr、ofb 或 crypto（crypto/rand 对照组），Compare 为 true 时附带 crypto/rand 的检验报告
+type PRNGRequest struct {
+	Key     string `json:"key"`
+	IV      string `json:"iv"`
+	Mode    string `json:"mode"`
+	Length  int    `json:"length"`
+	Compare bool   `json:"compare"`
+}
+
+// RoundsAnalysisRequest 比较不同轮数变体的安全性
+// 用 Key 加密 Plaintext 后对密文暴力破解；Rounds 为空时默认 2、4、8、16 轮
+type RoundsAnalysisRequest struct {
+	Rounds    []int  `json:"rounds"`
+	Plaintext string `json:"plaintext"`
+	Key       string `json:"key"`
+}
+
+// ClassicalRequest 古典密码加密/解密，Algorithm 为 caesar、affine、vigenere、playfair 或 hill
+type ClassicalRequest struct {
+	Algorithm string `json:"algorithm" binding:"required"`
+	Text      string `json:"text" binding:"required"`
+	Key       string `json:"key" binding:"required"`
+}
+
+// ClassicalCrackRequest 古典密码唯密文分析
+type ClassicalCrackRequest struct {
+	Algorithm  string `json:"algorithm" binding:"required"`
+	Ciphertext string `json:"ciphertext" binding:"required"`
+}
+
+// TMTORequest 时间–存储折中演示，Method 为 hellman 或 rainbow
+// Key 不为空时额外演示用该密钥加密选择明文后能否从表中恢复
+type TMTORequest struct {
+	Method      string `json:"method"`
+	ChainLength int    `json:"chain_length"`
+	Chains      int    `json:"chains"`
+	Tables      int    `json:"tables"`
+	Seed        uint64 `json:"seed"`
+	Key         string `json:"key"`
+}
+
+// RelatedKeysRequest 相关密钥与滑动攻击分析
+// Key 为空时只返回全局统计；滑动攻击在每轮使用同一 8 位子密钥 Subkey（为空时随机）的 Rounds 轮变体上进行
+type RelatedKeysRequest struct {
+	Key             string `json:"key"`
+	Rounds          int    `json:"rounds"`
+	Subkey          string `json:"subkey"`
+	KnownPlaintexts int    `json:"known_plaintexts"`
+	Seed            uint64 `json:"seed"`
+}
+
+// KeyScheduleRequest 密钥扩展检查；Key 不为空时给出中间值，K1、K2 不为空时反推所有可能的密钥
+type KeyScheduleRequest struct {
+	Key string `json:"key"`
+	K1  string `json:"k1"`
+	K2  string `json:"k2"`
+}
+
+// OracleSessionRequest 创建预言机挑战会话，Algorithm 为空时使用 sdes
+type OracleSessionRequest struct {
+	Algorithm string `json:"algorithm"`
+}
+
+// OracleQueryRequest 预言机加密或解密查询，Block 为二进制分组
+type OracleQueryRequest struct {
+	SessionID string `json:"session_id" binding:"required"`
+	Block     string `json:"block" binding:"required"`
+}
+
+// OracleSubmitRequest 提交猜测的密钥
+type OracleSubmitRequest struct {
+	SessionID string `json:"session_id" binding:"required"`
+	Key       string `json:"key" binding:"required"`
+}
+
+// ExerciseRequest 生成练习题，Seed 为 0 时随机选取；Kinds 为空时包含全部题型
+type ExerciseRequest struct {
+	Seed  uint64   `json:"seed"`
+	Count int      `json:"count"`
+	Kinds []string `json:"kinds"`
+}
+
+// ExerciseAnswer 单题答案，Stages 为可选的中间步骤（步骤名 → 二进制值）
+type ExerciseAnswer struct {
+	ID     string            `json:"id" binding:"required"`
+	Answer string            `json:"answer"`
+	Stages map[string]string `json:"stages"`
+}
+
+// GradeRequest 批量评分
+type GradeRequest struct {
+	Answers []ExerciseAnswer `json:"answers" binding:"required"`
+}
+
+// CipherRequest /api/v1/encrypt 与 /api/v1/decrypt 的请求，加密与解密使用同一结构
+// Data 按 InputEncoding 解码后处理，结果按 OutputEncoding 编码；
+// 加密默认 ascii → base64，解密默认 base64 → ascii。Key、IV、Tag 为二进制字符串。
+// Padding 只作用于 ECB、CBC：pkcs7（默认）可无损还原任意数据；zero 以 0x00 补齐、解密后去除多字节分组末尾的 0x00，
+// 明文本身以 0x00 结尾时会丢失这些字节（与旧接口一致）；none 时数据必须是整数个分组
+// MACKey 不为空时加密附带 CMAC 标签，解密先校验 Tag（仅 S-DES）
+// 加密时 AutoKey 为 true 则忽略 Key 与 IV，由服务端随机生成密钥及（非 ECB 模式的）IV 并在响应中返回
+type CipherRequest struct {
+	Algorithm       string `json:"algorithm"`
+	Mode            string `json:"mode" binding:"omitempty,oneof=ecb cbc ctr"`
+	Padding         string `json:"padding" binding:"omitempty,oneof=pkcs7 zero none"`
+	InputEncoding   string `json:"input_encoding" binding:"omitempty,oneof=binary hex base64 ascii"`
+	OutputEncoding  string `json:"output_encoding" binding:"omitempty,oneof=binary hex base64 ascii"`
+	Key             string `json:"key" binding:"required_without=AutoKey"`
+	IV              string `json:"iv"`
+	Data            string `json:"data" binding:"required"`
+	MACKey          string `json:"mac_key"`
+	Tag             string `json:"tag"`
+	AutoKey         bool   `json:"auto_key"`
+	ExcludeWeakKeys bool   `json:"exclude_weak_keys"`
+}
+
+// CrackRequest /api/v1/crack 已知明密文对暴力破解，Plaintext、Ciphertext 为一个分组的二进制字符串
+// Rounds 为 0 或 2 时使用标准 S-DES，其余值使用对应轮数的 Feistel 变体（仅 S-DES）
+type CrackRequest struct {
+	Algorithm  string `json:"algorithm"`
+	Plaintext  string `json:"plaintext" binding:"required"`
+	Ciphertext string `json:"ciphertext" binding:"required"`
+	Rounds     int    `json:"rounds" binding:"min=0,max=64"`
+}
+
+// TokenRequest /api/v1/auth/token 签发令牌，Scopes 取值为 encrypt、crack、admin
+// ExpiresIn 为有效期（秒），为 0 时使用配置的默认有效期
+type TokenRequest struct {
+	Subject   string   `json:"subject" binding:"required,max=64"`
+	Scopes    []string `json:"scopes" binding:"required,min=1"`
+	ExpiresIn int      `json:"expires_in" binding:"min=0,max=2592000"`
 }
@@ -3,24 +3,407 @@ package response
 type EncryptResponse struct {
 	CiphertextBinary string `json:"ciphertext_binary,omitempty"`
 	CiphertextBase64 string `json:"ciphertext_base64,omitempty"`
+	Key              string `json:"key,omitempty"`
+	KeyDecimal       *int   `json:"key_decimal,omitempty"`
+	Tag              string `json:"tag,omitempty"`
 	Success          bool   `json:"success"`
 	Message          string `json:"message,omitempty"`
 }
 
 type DecryptResponse struct {
-	Plaintext      string `json:"plaintext,omitempty"`
-	PlaintextASCII string `json:"plaintext_ascii,omitempty"`
-	Success        bool   `json:"success"`
-	Message        string `json:"message,omitempty"`
+	Plaintext      string       `json:"plaintext,omitempty"`
+	PlaintextASCII string       `json:"plaintext_ascii,omitempty"`
+	TagValid       *bool        `json:"tag_valid,omitempty"`
+	Success        bool         `json:"success"`
+	Message        string       `json:"message,omitempty"`
+	Error          *ErrorDetail `json:"error,omitempty"`
 }
 
 type BlastingResponse struct {
-	Plaintext   string   `json:"plaintext,omitempty"`
-	Ciphertext  string   `json:"ciphertext,omitempty"`
-	Keys        []string `json:"keys,omitempty"`
-	KeysDecimal []int    `json:"keys_decimal,omitempty"`
-	KeyCount    int      `json:"key_count,omitempty"`
-	Success     bool     `json:"success"`
-	Message     string   `json:"message,omitempty"`
-	Time        string   `json:"time,omitempty"`
+	Plaintext   string       `json:"plaintext,omitempty"`
+	Ciphertext  string       `json:"ciphertext,omitempty"`
+	Keys        []string     `json:"keys,omitempty"`
+	KeysDecimal []int        `json:"keys_decimal,omitempty"`
+	KeyCount    int          `json:"key_count,omitempty"`
+	Success     bool         `json:"success"`
+	Message     string       `json:"message,omitempty"`
+	Error       *ErrorDetail `json:"error,omitempty"`
+	Time        string       `json:"time,omitempty"`
+}
+
+type RandomKeyResponse struct {
+	Key        string       `json:"key,omitempty"`
+	KeyDecimal int          `json:"key_decimal"`
+	IV         string       `json:"iv,omitempty"`
+	IVDecimal  int          `json:"iv_decimal"`
+	Success    bool         `json:"success"`
+	Message    string       `json:"message,omitempty"`
+	Error      *ErrorDetail `json:"error,omitempty"`
+}
+
+type MACResponse struct {
+	Algorithm string `json:"algorithm,omitempty"`
+	Tag       string `json:"tag,omitempty"`
+	Success   bool   `json:"success"`
+	Message   string `json:"message,omitempty"`
+}
+
+type ForgeryResponse struct {
+	TagBits          int    `json:"tag_bits,omitempty"`
+	OriginalBase64   string `json:"original_base64,omitempty"`
+	OriginalTag      string `json:"original_tag,omitempty"`
+	ForgedBase64     string `json:"forged_base64,omitempty"`
+	ForgedTag        string `json:"forged_tag,omitempty"`
+	Attempts         int    `json:"attempts,omitempty"`
+	ExpectedAttempts int    `json:"expected_attempts,omitempty"`
+	Success          bool   `json:"success"`
+	Message          string `json:"message,omitempty"`
+}
+
+type HashResponse struct {
+	Construction string   `json:"construction,omitempty"`
+	Bits         int      `json:"bits,omitempty"`
+	Hash         string   `json:"hash,omitempty"`
+	HashHex      string   `json:"hash_hex,omitempty"`
+	Chain        []string `json:"chain,omitempty"`
+	Success      bool     `json:"success"`
+	Message      string   `json:"message,omitempty"`
+}
+
+type CollisionResponse struct {
+	Construction   string       `json:"construction,omitempty"`
+	Bits           int          `json:"bits,omitempty"`
+	Message1Hex    string       `json:"message1_hex,omitempty"`
+	Message2Hex    string       `json:"message2_hex,omitempty"`
+	HashHex        string       `json:"hash_hex,omitempty"`
+	Trials         int          `json:"trials"`
+	ExpectedTrials float64      `json:"expected_trials"`
+	Success        bool         `json:"success"`
+	Message        string       `json:"message,omitempty"`
+	Error          *ErrorDetail `json:"error,omitempty"`
+	Time           string       `json:"time,omitempty"`
+}
+
+type StatResult struct {
+	Name      string  `json:"name"`
+	Statistic float64 `json:"statistic"`
+	PValue    float64 `json:"p_value"`
+	Passed    bool    `json:"passed"`
+	Detail    string  `json:"detail,omitempty"`
+}
+
+type StatReport struct {
+	Bits    int          `json:"bits"`
+	Period  int          `json:"period"`
+	Results []StatResult `json:"results"`
+	Passed  int          `json:"passed"`
+	Total   int          `json:"total"`
+}
+
+type PRNGResponse struct {
+	Mode         string      `json:"mode,omitempty"`
+	Length       int         `json:"length,omitempty"`
+	OutputHex    string      `json:"output_hex,omitempty"`
+	Period       int         `json:"period,omitempty"`
+	Report       *StatReport `json:"report,omitempty"`
+	CryptoReport *StatReport `json:"crypto_report,omitempty"`
+	Success      bool        `json:"success"`
+	Message      string      `json:"message,omitempty"`
+}
+
+type RoundAnalysis struct {
+	Rounds             int     `json:"rounds"`
+	Ciphertext         string  `json:"ciphertext"`
+	CandidateKeys      int     `json:"candidate_keys"`
+	PlaintextAvalanche float64 `json:"plaintext_avalanche"`
+	KeyAvalanche       float64 `json:"key_avalanche"`
+	BestInputDiff      string  `json:"best_input_diff"`
+	BestOutputDiff     string  `json:"best_output_diff"`
+	DiffProbability    float64 `json:"diff_probability"`
+	Time               string  `json:"time"`
+}
+
+type RoundsAnalysisResponse struct {
+	Plaintext string          `json:"plaintext,omitempty"`
+	Key       string          `json:"key,omitempty"`
+	Results   []RoundAnalysis `json:"results,omitempty"`
+	Success   bool            `json:"success"`
+	Message   string          `json:"message,omitempty"`
+}
+
+type AlgorithmInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	BlockBits   int    `json:"block_bits"`
+	KeyBits     int    `json:"key_bits"`
+	Default     bool   `json:"default,omitempty"`
+}
+
+type AlgorithmsResponse struct {
+	Algorithms []AlgorithmInfo `json:"algorithms"`
+	Classical  []ClassicalInfo `json:"classical"`
+	Success    bool            `json:"success"`
+	Message    string          `json:"message,omitempty"`
+}
+
+type ClassicalResponse struct {
+	Algorithm string `json:"algorithm,omitempty"`
+	Result    string `json:"result,omitempty"`
+	Success   bool   `json:"success"`
+	Message   string `json:"message,omitempty"`
+}
+
+type ClassicalCrackResponse struct {
+	Algorithm string             `json:"algorithm,omitempty"`
+	Key       string             `json:"key,omitempty"`
+	Plaintext string             `json:"plaintext,omitempty"`
+	Score     float64            `json:"score,omitempty"`
+	IC        float64            `json:"ic,omitempty"`
+	Frequency map[string]float64 `json:"frequency,omitempty"`
+	Details   []string           `json:"details,omitempty"`
+	Time      string             `json:"time,omitempty"`
+	Success   bool               `json:"success"`
+	Message   string             `json:"message,omitempty"`
+	Error     *ErrorDetail       `json:"error,omitempty"`
+}
+
+type ClassicalInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	KeyFormat   string `json:"key_format"`
+}
+
+type TMTOResponse struct {
+	Method           string       `json:"method,omitempty"`
+	Plaintext        string       `json:"plaintext,omitempty"`
+	ChainLength      int          `json:"chain_length,omitempty"`
+	Chains           int          `json:"chains,omitempty"`
+	Tables           int          `json:"tables,omitempty"`
+	StoredChains     int          `json:"stored_chains,omitempty"`
+	MemoryBytes      int          `json:"memory_bytes,omitempty"`
+	CodebookBytes    int          `json:"codebook_bytes,omitempty"`
+	PrecomputeEvals  int          `json:"precompute_evals,omitempty"`
+	SuccessRate      float64      `json:"success_rate"`
+	AvgEvaluations   float64      `json:"avg_evaluations"`
+	FalseAlarms      int          `json:"false_alarms"`
+	TargetKey        string       `json:"target_key,omitempty"`
+	TargetCiphertext string       `json:"target_ciphertext,omitempty"`
+	RecoveredKey     string       `json:"recovered_key,omitempty"`
+	Recovered        *bool        `json:"recovered,omitempty"`
+	Time             string       `json:"time,omitempty"`
+	Success          bool         `json:"success"`
+	Message          string       `json:"message,omitempty"`
+	Error            *ErrorDetail `json:"error,omitempty"`
+}
+
+type SubkeyDifference struct {
+	KeyDiff string `json:"key_diff"`
+	K1Diff  string `json:"k1_diff"`
+	K2Diff  string `json:"k2_diff"`
+}
+
+type SlideAttackResult struct {
+	Rounds                 int      `json:"rounds"`
+	Subkey                 string   `json:"subkey"`
+	KnownPlaintexts        int      `json:"known_plaintexts"`
+	PairsTested            int      `json:"pairs_tested"`
+	SlidPairs              int      `json:"slid_pairs"`
+	CandidateKeys          []string `json:"candidate_keys"`
+	RecoveredKey           string   `json:"recovered_key,omitempty"`
+	Recovered              bool     `json:"recovered"`
+	FEvaluations           int      `json:"f_evaluations"`
+	BruteForceFEvaluations int      `json:"brute_force_f_evaluations"`
+	Message                string   `json:"message,omitempty"`
+}
+
+type RelatedKeysResponse struct {
+	SingleBitDiffs  []SubkeyDifference `json:"single_bit_diffs,omitempty"`
+	ZeroK1Diffs     []SubkeyDifference `json:"zero_k1_diffs,omitempty"`
+	ZeroK2Diffs     []SubkeyDifference `json:"zero_k2_diffs,omitempty"`
+	SharedK1Pairs   int                `json:"shared_k1_pairs,omitempty"`
+	SharedK2Pairs   int                `json:"shared_k2_pairs,omitempty"`
+	EquivalentPairs int                `json:"equivalent_pairs,omitempty"`
+	SlidePairs      int                `json:"slide_pairs,omitempty"`
+	Key             string             `json:"key,omitempty"`
+	SameK1          []string           `json:"same_k1,omitempty"`
+	SameK2          []string           `json:"same_k2,omitempty"`
+	SlidePartners   []string           `json:"slide_partners,omitempty"`
+	Slide           *SlideAttackResult `json:"slide,omitempty"`
+	Time            string             `json:"time,omitempty"`
+	Success         bool               `json:"success"`
+	Message         string             `json:"message,omitempty"`
+}
+
+type ScheduleShift struct {
+	Name  string `json:"name"`
+	Left  string `json:"left"`
+	Right string `json:"right"`
+}
+
+type KeyScheduleResponse struct {
+	Key      string          `json:"key,omitempty"`
+	P10      string          `json:"p10,omitempty"`
+	Shifts   []ScheduleShift `json:"shifts,omitempty"`
+	K1       string          `json:"k1,omitempty"`
+	K2       string          `json:"k2,omitempty"`
+	Keys     []string        `json:"keys,omitempty"`
+	KeyCount *int            `json:"key_count,omitempty"`
+	Success  bool            `json:"success"`
+	Message  string          `json:"message,omitempty"`
+	Error    *ErrorDetail    `json:"error,omitempty"`
+}
+
+type OracleSession struct {
+	SessionID     string `json:"session_id"`
+	Algorithm     string `json:"algorithm"`
+	BlockBits     int    `json:"block_bits"`
+	KeyBits       int    `json:"key_bits"`
+	EncryptLeft   int    `json:"encrypt_left"`
+	DecryptLeft   int    `json:"decrypt_left"`
+	AttemptsLeft  int    `json:"attempts_left"`
+	Queries       int    `json:"queries"`
+	WrongAttempts int    `json:"wrong_attempts"`
+	Solved        bool   `json:"solved"`
+	Score         int    `json:"score"`
+	ExpiresAt     string `json:"expires_at"`
+}
+
+type OracleResponse struct {
+	Session *OracleSession `json:"session,omitempty"`
+	Result  string         `json:"result,omitempty"`
+	Correct *bool          `json:"correct,omitempty"`
+	Success bool           `json:"success"`
+	Message string         `json:"message,omitempty"`
+	Error   *ErrorDetail   `json:"error,omitempty"`
+}
+
+type ExercisePair struct {
+	Plaintext  string `json:"plaintext"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+type TraceStep struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type ExerciseSolution struct {
+	Answer   string      `json:"answer"`
+	Accepted []string    `json:"accepted,omitempty"`
+	Stages   []TraceStep `json:"stages,omitempty"`
+}
+
+type Exercise struct {
+	ID               string            `json:"id"`
+	Kind             string            `json:"kind"`
+	Prompt           string            `json:"prompt"`
+	Points           int               `json:"points"`
+	Key              string            `json:"key,omitempty"`
+	Plaintext        string            `json:"plaintext,omitempty"`
+	CiphertextBase64 string            `json:"ciphertext_base64,omitempty"`
+	Pairs            []ExercisePair    `json:"pairs,omitempty"`
+	Stages           []string          `json:"stages,omitempty"`
+	Solution         *ExerciseSolution `json:"solution,omitempty"`
+}
+
+type ExerciseResponse struct {
+	Seed        uint64     `json:"seed"`
+	TotalPoints int        `json:"total_points"`
+	Exercises   []Exercise `json:"exercises,omitempty"`
+	Success     bool       `json:"success"`
+	Message     string     `json:"message,omitempty"`
+}
+
+type StageResult struct {
+	Name    string `json:"name"`
+	Given   bool   `json:"given"`
+	Correct bool   `json:"correct"`
+}
+
+type GradeResult struct {
+	ID       string        `json:"id"`
+	Kind     string        `json:"kind,omitempty"`
+	Points   int           `json:"points"`
+	Score    float64       `json:"score"`
+	Correct  bool          `json:"correct"`
+	Stages   []StageResult `json:"stages,omitempty"`
+	Feedback string        `json:"feedback"`
+	Error    *ErrorDetail  `json:"error,omitempty"`
+}
+
+type GradeResponse struct {
+	Results     []GradeResult `json:"results,omitempty"`
+	Score       float64       `json:"score"`
+	TotalPoints int           `json:"total_points"`
+	Success     bool          `json:"success"`
+	Message     string        `json:"message,omitempty"`
+}
+
+type HistoryRecord struct {
+	ID         int64    `json:"id"`
+	Time       string   `json:"time"`
+	Operation  string   `json:"operation"`
+	Algorithm  string   `json:"algorithm"`
+	Mode       string   `json:"mode,omitempty"`
+	Key        string   `json:"key,omitempty"`
+	Input      string   `json:"input"`
+	Output     string   `json:"output"`
+	Keys       []string `json:"keys,omitempty"`
+	DurationMs float64  `json:"duration_ms"`
+	Success    bool     `json:"success"`
+}
+
+type HistoryResponse struct {
+	Records []HistoryRecord `json:"records,omitempty"`
+	Total   int             `json:"total"`
+	Success bool            `json:"success"`
+	Message string          `json:"message,omitempty"`
+}
+
+// ErrorDetail 统一的错误详情：Code 为稳定的错误码，Field 为出错的请求字段，Message 按 Accept-Language 本地化
+type ErrorDetail struct {
+	Code       string `json:"code"`
+	Field      string `json:"field,omitempty"`
+	Message    string `json:"message"`
+	RetryAfter int    `json:"retry_after,omitempty"`
+}
+
+// ErrorResponse 统一的错误响应，Message 与 Error.Message 相同，保留以兼容只读取 message 的客户端
+type ErrorResponse struct {
+	Success bool         `json:"success"`
+	Message string       `json:"message"`
+	Error   *ErrorDetail `json:"error"`
+}
+
+// CipherResponse /api/v1/encrypt 与 /api/v1/decrypt 的响应，Data 按 Encoding 编码
+// Key、IV 仅在 auto_key 自动生成时返回
+type CipherResponse struct {
+	Algorithm string       `json:"algorithm"`
+	Mode      string       `json:"mode"`
+	Encoding  string       `json:"encoding"`
+	Data      string       `json:"data,omitempty"`
+	Key       string       `json:"key,omitempty"`
+	IV        string       `json:"iv,omitempty"`
+	Tag       string       `json:"tag,omitempty"`
+	TagValid  *bool        `json:"tag_valid,omitempty"`
+	Success   bool         `json:"success"`
+	Error     *ErrorDetail `json:"error,omitempty"`
+}
+
+// CrackResponse /api/v1/crack 的响应，未找到密钥时 Keys 为空
+type CrackResponse struct {
+	Algorithm  string   `json:"algorithm"`
+	Keys       []string `json:"keys"`
+	KeyCount   int      `json:"key_count"`
+	DurationMs float64  `json:"duration_ms"`
+	Success    bool     `json:"success"`
+}
+
+// TokenResponse 签发的令牌，ExpiresAt 为 RFC3339 时间
+type TokenResponse struct {
+	Token     string   `json:"token"`
+	Subject   string   `json:"subject"`
+	Scopes    []string `json:"scopes"`
+	ExpiresAt string   `json:"expires_at"`
+	Success   bool     `json:"success"`
 }
@@ -0,0 +1,147 @@
+// Package logging 基于 log/slog 的结构化日志、请求 ID 中间件与敏感字段脱敏
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Redacted 脱敏后的占位符
+const Redacted = "***"
+
+// RequestIDHeader 请求 ID 所在的请求头与响应头
+const RequestIDHeader = "X-Request-ID"
+
+// SecretKeys 视为敏感信息的日志字段名：密钥与明密文
+// 只有在 debug 级别且显式开启 LogSecrets 时才会原样输出
+var SecretKeys = []string{
+	"key", "keys", "mac_key", "k1", "k2",
+	"plaintext", "plaintext_ascii", "ciphertext", "ciphertext_base64",
+	"input", "output",
+}
+
+// Options 日志配置
+type Options struct {
+	Level      string // debug、info、warn 或 error
+	Format     string // text 或 json
+	LogSecrets bool   // 仅在 Level 为 debug 时允许输出敏感字段
+}
+
+// ParseLevel 解析日志级别
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("日志级别必须是 debug、info、warn 或 error: %s", s)
+	}
+	return level, nil
+}
+
+// New 按配置创建日志记录器
+func New(w io.Writer, opts Options) (*slog.Logger, error) {
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+	showSecrets := opts.LogSecrets && level <= slog.LevelDebug
+	handlerOpts := &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if !showSecrets && slices.Contains(SecretKeys, a.Key) {
+				return slog.String(a.Key, Redacted)
+			}
+			return a
+		},
+	}
+	switch opts.Format {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, handlerOpts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, handlerOpts)), nil
+	}
+	return nil, fmt.Errorf("日志格式必须是 text 或 json: %s", opts.Format)
+}
+
+type loggerKey struct{}
+
+// WithLogger 将日志记录器放入上下文
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext 取出上下文中带请求 ID 的日志记录器，没有时返回 slog.Default()
+// 由请求上下文派生的暴力破解等任务同样可以取到
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// validRequestID 客户端传入的请求 ID 只接受不超过 64 位的字母、数字与 ._-，避免日志注入
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// newRequestID 生成 64 位随机请求 ID
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Middleware 请求 ID 与访问日志中间件
+// 沿用合法的 X-Request-ID 请求头或生成新的 ID，写入响应头，并将带 request_id 的日志记录器放入请求上下文
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+
+		logger := slog.Default().With("request_id", id)
+		c.Request = c.Request.WithContext(WithLogger(c.Request.Context(), logger))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		attrs := []any{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", status,
+			"duration_ms", float64(time.Since(start).Nanoseconds()) / 1000000,
+			"bytes", c.Writer.Size(),
+			"client_ip", c.ClientIP(),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "errors", strings.Join(c.Errors.Errors(), "; "))
+		}
+		logger.Log(c.Request.Context(), level, "请求完成", attrs...)
+	}
+}
+
+// Recovery 捕获处理函数中的 panic，记录带请求 ID 的错误日志并返回 500
+// 须注册在 Middleware 之后，才能取到请求上下文中的日志记录器
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, err any) {
+		FromContext(c.Request.Context()).Error("处理请求时发生 panic", "panic", fmt.Sprint(err), "path", c.Request.URL.Path)
+		c.AbortWithStatus(http.StatusInternalServerError)
+	})
+}
@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRedaction(t *testing.T) {
+	cases := []struct {
+		opts     Options
+		redacted bool
+	}{
+		{Options{Level: "info", Format: "json"}, true},
+		{Options{Level: "debug", Format: "json"}, true},
+		{Options{Level: "info", Format: "json", LogSecrets: true}, true},
+		{Options{Level: "debug", Format: "json", LogSecrets: true}, false},
+	}
+	for _, tc := range cases {
+		var buf bytes.Buffer
+		logger, err := New(&buf, tc.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		logger.Warn("暴力破解完成", "key", "1010000010", "keys", []string{"0001110000"}, "algorithm", "sdes")
+		var entry map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("%+v: %v", tc.opts, err)
+		}
+		if got := entry["key"] == Redacted && entry["keys"] == Redacted; got != tc.redacted {
+			t.Errorf("%+v: 脱敏 = %v，期望 %v，日志 %s", tc.opts, got, tc.redacted, buf.String())
+		}
+		if entry["algorithm"] != "sdes" {
+			t.Errorf("非敏感字段不应脱敏: %s", buf.String())
+		}
+	}
+
+	if _, err := New(&bytes.Buffer{}, Options{Level: "verbose", Format: "text"}); err == nil {
+		t.Error("无效的日志级别应返回错误")
+	}
+}
+
+func TestMiddlewareRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	logger, _ := New(&buf, Options{Level: "info", Format: "json"})
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(logger)
+
+	r := gin.New()
+	r.Use(Middleware(), Recovery())
+	r.GET("/job", func(c *gin.Context) {
+		FromContext(c.Request.Context()).Info("任务", "plaintext", "10101010")
+		c.Status(http.StatusOK)
+	})
+	r.GET("/panic", func(c *gin.Context) { panic("boom") })
+
+	req := httptest.NewRequest(http.MethodGet, "/job", nil)
+	req.Header.Set(RequestIDHeader, "abc-123")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if got := w.Header().Get(RequestIDHeader); got != "abc-123" {
+		t.Errorf("应沿用请求中的 ID，实际 %q", got)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("应输出处理函数日志与访问日志两行，实际 %q", buf.String())
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, `"request_id":"abc-123"`) || strings.Contains(line, "10101010") {
+			t.Errorf("日志缺少请求 ID 或泄露明文: %s", line)
+		}
+	}
+
+	buf.Reset()
+	req = httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set(RequestIDHeader, "bad id\nforged")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	id := w.Header().Get(RequestIDHeader)
+	if w.Code != http.StatusInternalServerError || len(id) != 16 {
+		t.Errorf("非法请求 ID 应重新生成，panic 应返回 500：status %d，id %q", w.Code, id)
+	}
+	if !strings.Contains(buf.String(), "panic") || !strings.Contains(buf.String(), id) {
+		t.Errorf("panic 日志缺少请求 ID: %s", buf.String())
+	}
+}
@@ -1,31 +1,116 @@
 package main
 
 import (
+	"SDES/config"
+	"SDES/controller"
+	"SDES/logging"
 	"SDES/router"
+	"SDES/server"
+	"SDES/utils"
+	"SDES/utils/history"
+	"SDES/utils/oracle"
+	"cmp"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	// 配置来源：-config 指定的 YAML/TOML 文件、SDES_* 环境变量与命令行参数
+	cfg, err := config.Load(os.Args[1:], os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "配置错误:", err)
+		os.Exit(2)
+	}
+
+	logger, err := logging.New(os.Stderr, logging.Options{Level: cfg.LogLevel, Format: cfg.LogFormat, LogSecrets: cfg.LogSecrets})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "配置错误:", err)
+		os.Exit(2)
+	}
+	slog.SetDefault(logger)
+
+	// 收到 SIGINT/SIGTERM 时开始优雅关闭
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, cfg); err != nil {
+		slog.Error("服务器错误", "error", err)
+		os.Exit(1)
+	}
+}
+
+// run 初始化各模块并运行服务器，直到 ctx 取消；返回错误时由 main 以非零状态退出
+func run(ctx context.Context, cfg *config.Config) error {
 	fmt.Println("S-DES Web 服务器启动中...")
+	cfg.Print(os.Stdout)
 
 	// 设置Gin模式
-	gin.SetMode(gin.DebugMode)
+	gin.SetMode(cfg.Mode)
 
-	// 创建Gin路由器
-	r := gin.Default()
+	// 完整密码本持久化路径，为空时只在内存中构建
+	utils.SetCodebookPath(cfg.Codebook)
 
-	router.InitRouter(r)
+	oracleCfg := oracle.DefaultConfig
+	oracleCfg.TTL = time.Duration(cfg.OracleTTL)
+	controller.ConfigureOracle(oracleCfg)
+	controller.ConfigureBruteForce(cfg.BruteForceWorkers, cfg.MaxBruteForceJobs)
+	controller.ConfigureLimits(cfg.MaxPlaintextBytes)
+	if err := controller.ConfigureExercises([]byte(cfg.Auth.TokenSecret)); err != nil {
+		return err
+	}
+
+	if cfg.History.Enabled {
+		store, err := history.Open(history.Options{Path: cfg.History.File, Redact: cfg.History.Redact})
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		controller.ConfigureHistory(store)
+	}
+
+	// 创建Gin路由器，访问日志与 panic 恢复由 router 中的 logging 中间件负责
+	r := gin.New()
+
+	router.InitRouter(r, cfg)
+
+	srv := server.New(cfg, r)
+	srv.OnShutdown(controller.CancelBruteForceJobs)
 
 	// 启动服务器
-	fmt.Println("服务器启动在 http://localhost:8080")
+	scheme := "http"
+	if cfg.TLS() {
+		scheme = "https"
+	}
+	host := cfg.Addr
+	if strings.HasPrefix(host, ":") {
+		host = "localhost" + host
+	}
+	fmt.Printf("服务器启动在 %s://%s\n", scheme, host)
+	fmt.Printf("接口文档: %s://%s/api/docs（OpenAPI: /api/openapi.json）\n", scheme, host)
 	fmt.Println("API端点:")
-	fmt.Println("  POST /api/encrypt - 加密")
-	fmt.Println("  POST /api/decrypt - 解密")
-
-	err := r.Run(":8080")
-	if err != nil {
-		return
+	routes := r.Routes()
+	slices.SortFunc(routes, func(a, b gin.RouteInfo) int {
+		return cmp.Or(strings.Compare(a.Path, b.Path), strings.Compare(a.Method, b.Method))
+	})
+	for _, rt := range routes {
+		if strings.HasPrefix(rt.Path, "/api/") {
+			fmt.Printf("  %-6s %s\n", rt.Method, rt.Path)
+		}
 	}
+
+	return srv.Run(ctx)
 }
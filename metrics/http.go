@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	httpRequests = Default.NewCounter("sdes_http_requests_total",
+		"按路由、方法、状态码与结果统计的请求数", "route", "method", "code", "outcome")
+	httpDuration = Default.NewHistogram("sdes_http_request_duration_seconds",
+		"按路由与方法统计的请求耗时", nil, "route", "method")
+	httpInFlight = Default.NewGauge("sdes_http_requests_in_flight",
+		"正在处理的请求数")
+)
+
+// outcome 由状态码归类的请求结果
+func outcome(code int) string {
+	switch {
+	case code >= 500:
+		return "server_error"
+	case code >= 400:
+		return "client_error"
+	default:
+		return "success"
+	}
+}
+
+// Middleware 统计每个请求的次数与耗时
+// 路由标签使用注册时的路径模板（如 /api/oracle/session/:id），未匹配的请求记为 unmatched，避免标签基数失控
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		httpInFlight.Inc()
+		defer httpInFlight.Dec()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		code := c.Writer.Status()
+		httpRequests.Inc(route, c.Request.Method, strconv.Itoa(code), outcome(code))
+		httpDuration.Observe(time.Since(start).Seconds(), route, c.Request.Method)
+	}
+}
+
+// Handler 以 Prometheus 文本格式输出 Default 中的全部指标
+func Handler(c *gin.Context) {
+	c.Header("Content-Type", ContentType)
+	c.Status(http.StatusOK)
+	if err := Default.WriteText(c.Writer); err != nil {
+		c.Error(err)
+	}
+}
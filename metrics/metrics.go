@@ -0,0 +1,327 @@
+// Package metrics 最小化的 Prometheus 指标实现
+// 支持计数器、仪表与直方图（可带标签），以 Prometheus 文本格式（0.0.4）输出，无需引入客户端库。
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType Prometheus 文本格式的 Content-Type
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets 默认的直方图分桶（秒），覆盖 0.5ms ~ 10s
+var DefaultBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// collector 一个指标族
+type collector interface {
+	name() string
+	write(w io.Writer) error
+}
+
+// Registry 指标注册表
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+}
+
+// NewRegistry 创建空的注册表
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Default 全局注册表，/metrics 输出其中的全部指标
+var Default = NewRegistry()
+
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.collectors {
+		if existing.name() == c.name() {
+			panic("重复注册指标: " + c.name())
+		}
+	}
+	r.collectors = append(r.collectors, c)
+}
+
+// WriteText 以 Prometheus 文本格式输出全部指标，按名称排序
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	collectors := slices.Clone(r.collectors)
+	r.mu.Unlock()
+	sort.Slice(collectors, func(i, j int) bool { return collectors[i].name() < collectors[j].name() })
+	for _, c := range collectors {
+		if err := c.write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// desc 指标族的名称、说明与标签名
+type desc struct {
+	metric string
+	help   string
+	kind   string
+	labels []string
+}
+
+func (d *desc) name() string {
+	return d.metric
+}
+
+func (d *desc) header(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.metric, escapeHelp(d.help), d.metric, d.kind)
+	return err
+}
+
+// labelPairs 生成 {a="1",b="2"}，extra 为附加的标签（如直方图的 le）
+func (d *desc) labelPairs(values []string, extra ...string) string {
+	if len(d.labels) == 0 && len(extra) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, l := range d.labels {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `%s="%s"`, l, escapeLabel(values[i]))
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `%s="%s"`, extra[i], escapeLabel(extra[i+1]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func (d *desc) key(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("指标 %s 需要 %d 个标签值，实际 %d 个", d.metric, len(d.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(s)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// sortedKeys 返回按标签值排序的序列键，使输出稳定
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Vec 计数器或仪表（按标签区分的一组数值）
+type Vec struct {
+	desc
+	mu          sync.Mutex
+	values      map[string]float64
+	labelValues map[string][]string
+}
+
+func newVec(r *Registry, kind, name, help string, labels []string) *Vec {
+	v := &Vec{
+		desc:        desc{metric: name, help: help, kind: kind, labels: labels},
+		values:      make(map[string]float64),
+		labelValues: make(map[string][]string),
+	}
+	r.register(v)
+	return v
+}
+
+// NewCounter 注册只增不减的计数器
+func (r *Registry) NewCounter(name, help string, labels ...string) *Vec {
+	return newVec(r, "counter", name, help, labels)
+}
+
+// NewGauge 注册可增可减的仪表
+func (r *Registry) NewGauge(name, help string, labels ...string) *Vec {
+	return newVec(r, "gauge", name, help, labels)
+}
+
+// Add 增加指定标签的数值；计数器不允许传入负数
+func (v *Vec) Add(delta float64, labelValues ...string) {
+	if v.kind == "counter" && delta < 0 {
+		panic("计数器不能减少: " + v.metric)
+	}
+	k := v.key(labelValues)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if _, ok := v.labelValues[k]; !ok {
+		v.labelValues[k] = slices.Clone(labelValues)
+	}
+	v.values[k] += delta
+}
+
+// Inc 数值加一
+func (v *Vec) Inc(labelValues ...string) {
+	v.Add(1, labelValues...)
+}
+
+// Dec 数值减一（仅用于仪表）
+func (v *Vec) Dec(labelValues ...string) {
+	v.Add(-1, labelValues...)
+}
+
+// Set 设置仪表的数值
+func (v *Vec) Set(value float64, labelValues ...string) {
+	k := v.key(labelValues)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.labelValues[k] = slices.Clone(labelValues)
+	v.values[k] = value
+}
+
+// Value 返回指定标签的当前数值
+func (v *Vec) Value(labelValues ...string) float64 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.values[v.key(labelValues)]
+}
+
+func (v *Vec) write(w io.Writer) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.header(w); err != nil {
+		return err
+	}
+	for _, k := range sortedKeys(v.values) {
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", v.metric, v.labelPairs(v.labelValues[k]), formatFloat(v.values[k])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gaugeFunc 输出时调用函数取值的仪表
+type gaugeFunc struct {
+	desc
+	f func() float64
+}
+
+// NewGaugeFunc 注册输出时才计算数值的仪表，适合读取已有状态（如通道长度）
+func (r *Registry) NewGaugeFunc(name, help string, f func() float64) {
+	r.register(&gaugeFunc{desc: desc{metric: name, help: help, kind: "gauge"}, f: f})
+}
+
+func (g *gaugeFunc) write(w io.Writer) error {
+	if err := g.header(w); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s %s\n", g.metric, formatFloat(g.f()))
+	return err
+}
+
+// Histogram 直方图
+type Histogram struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labels []string
+	counts []uint64 // 每个分桶（非累计）的观测次数，最后一个为 +Inf
+	sum    float64
+	count  uint64
+}
+
+// NewHistogram 注册直方图，buckets 为升序的分桶上界，为 nil 时使用 DefaultBuckets
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	if !slices.IsSorted(buckets) {
+		panic("直方图分桶必须升序: " + name)
+	}
+	h := &Histogram{
+		desc:    desc{metric: name, help: help, kind: "histogram", labels: labels},
+		buckets: buckets,
+		series:  make(map[string]*histogramSeries),
+	}
+	r.register(h)
+	return h
+}
+
+// Observe 记录一次观测
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	k := h.key(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[k]
+	if !ok {
+		s = &histogramSeries{labels: slices.Clone(labelValues), counts: make([]uint64, len(h.buckets)+1)}
+		h.series[k] = s
+	}
+	i, _ := slices.BinarySearch(h.buckets, value)
+	s.counts[i]++
+	s.sum += value
+	s.count++
+}
+
+// Count 返回指定标签的观测次数
+func (h *Histogram) Count(labelValues ...string) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s, ok := h.series[h.key(labelValues)]; ok {
+		return s.count
+	}
+	return 0
+}
+
+func (h *Histogram) write(w io.Writer) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err := h.header(w); err != nil {
+		return err
+	}
+	for _, k := range sortedKeys(h.series) {
+		s := h.series[k]
+		var cumulative uint64
+		for i, c := range s.counts {
+			cumulative += c
+			le := math.Inf(1)
+			if i < len(h.buckets) {
+				le = h.buckets[i]
+			}
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.metric, h.labelPairs(s.labels, "le", formatFloat(le)), cumulative); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s_sum%s %s\n%s_count%s %d\n",
+			h.metric, h.labelPairs(s.labels), formatFloat(s.sum),
+			h.metric, h.labelPairs(s.labels), s.count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestWriteText(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("test_requests_total", "请求数", "route", "code")
+	c.Inc("/api/encrypt", "200")
+	c.Add(2, "/api/encrypt", "200")
+	c.Inc(`a"b\c`, "400")
+	h := r.NewHistogram("test_duration_seconds", "耗时", []float64{0.1, 1}, "route")
+	h.Observe(0.05, "/x")
+	h.Observe(0.1, "/x")
+	h.Observe(3, "/x")
+	r.NewGaugeFunc("test_active", "活跃数", func() float64 { return 7 })
+
+	var b strings.Builder
+	if err := r.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP test_active 活跃数
+# TYPE test_active gauge
+test_active 7
+# HELP test_duration_seconds 耗时
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{route="/x",le="0.1"} 2
+test_duration_seconds_bucket{route="/x",le="1"} 2
+test_duration_seconds_bucket{route="/x",le="+Inf"} 3
+test_duration_seconds_sum{route="/x"} 3.15
+test_duration_seconds_count{route="/x"} 3
+# HELP test_requests_total 请求数
+# TYPE test_requests_total counter
+test_requests_total{route="/api/encrypt",code="200"} 3
+test_requests_total{route="a\"b\\c",code="400"} 1
+`
+	if b.String() != want {
+		t.Errorf("输出不符：\n%s\n期望：\n%s", b.String(), want)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/api/oracle/session/:id", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+	r.GET("/metrics", Handler)
+
+	before := httpRequests.Value("/api/oracle/session/:id", "GET", "404", "client_error")
+	for _, path := range []string{"/api/oracle/session/a", "/api/oracle/session/b", "/nope"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	if got := httpRequests.Value("/api/oracle/session/:id", "GET", "404", "client_error") - before; got != 2 {
+		t.Errorf("同一路由模板应合并统计，增加了 %v 次", got)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Header().Get("Content-Type") != ContentType {
+		t.Errorf("Content-Type = %q", w.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(w.Body.String(), `sdes_http_requests_total{route="unmatched",method="GET",code="404",outcome="client_error"}`) {
+		t.Errorf("未匹配的请求应记为 unmatched:\n%s", w.Body.String())
+	}
+}
@@ -0,0 +1,460 @@
+// Package openapi 由请求/响应结构体生成 OpenAPI 3 文档，并按文档校验请求体
+// 字段名取自 json 标签，必填与取值约束取自 gin 的 binding 标签（required、oneof、min、max、len），
+// 因此文档、请求校验与 gin 的绑定校验使用同一份定义。
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version 生成的文档遵循的 OpenAPI 版本
+const Version = "3.0.3"
+
+// Document OpenAPI 文档
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+
+	// types 已生成组件的结构体类型，用于检测重名
+	types map[string]reflect.Type
+}
+
+// Info 文档基本信息
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Components 可复用的组件
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme 认证方式
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+	Name   string `json:"name,omitempty"`
+	In     string `json:"in,omitempty"`
+}
+
+// PathItem 一个路径下各方法的操作
+type PathItem map[string]*OperationObject
+
+// OperationObject 单个接口
+type OperationObject struct {
+	Summary     string               `json:"summary,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Description string               `json:"description,omitempty"`
+	OperationID string               `json:"operationId,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+	// Security 可选的认证方式，满足其一即可
+	Security []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter 路径或查询参数
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody 请求体
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response 响应
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType 某种内容类型的结构
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema JSON Schema（OpenAPI 3.0 子集）
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+}
+
+// Param 查询参数的简化描述
+type Param struct {
+	Name        string
+	Type        string // string、integer、boolean，为空时为 string
+	Description string
+	Enum        []string
+}
+
+// Operation 注册接口时提供的描述
+type Operation struct {
+	Method  string // GET、POST 等
+	Path    string // gin 风格的路径，:id 会转换为 {id}
+	Summary string
+	Tag     string
+	Query   []Param
+	// Request 请求体结构体的零值，为 nil 表示没有请求体
+	Request any
+	// Response 成功（200）时的响应体
+	Response any
+	// Error 处理函数返回错误时的响应体，为 nil 时与 Response 相同
+	Error any
+	// Deprecated 已弃用的接口，Successor 为替代接口的路径
+	Deprecated bool
+	Successor  string
+	// Scope 启用认证时接口所需的作用域，为空表示无需认证
+	Scope string
+}
+
+// errorStatuses 中间件产生的错误状态码及说明，所有接口都可能返回
+var errorStatuses = map[string]string{
+	"413": "请求体或文本输入过大",
+	"429": "请求过于频繁",
+}
+
+// New 创建空文档
+func New(info Info) *Document {
+	return &Document{
+		OpenAPI:    Version,
+		Info:       info,
+		Paths:      make(map[string]*PathItem),
+		Components: Components{Schemas: make(map[string]*Schema)},
+		types:      make(map[string]reflect.Type),
+	}
+}
+
+// Add 添加接口；middlewareError 为限流、请求体过大等中间件错误的响应体
+func (d *Document) Add(op Operation, middlewareError any) {
+	path, params := convertPath(op.Path)
+	item, ok := d.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		d.Paths[path] = item
+	}
+
+	o := &OperationObject{
+		Summary:     op.Summary,
+		OperationID: operationID(op.Method, op.Path),
+		Responses:   make(map[string]*Response),
+		Deprecated:  op.Deprecated,
+	}
+	if op.Successor != "" {
+		o.Description = "已弃用，请改用 " + op.Successor
+	}
+	if op.Tag != "" {
+		o.Tags = []string{op.Tag}
+	}
+	for _, name := range params {
+		o.Parameters = append(o.Parameters, &Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+	for _, p := range op.Query {
+		typ := p.Type
+		if typ == "" {
+			typ = "string"
+		}
+		s := &Schema{Type: typ}
+		for _, v := range p.Enum {
+			s.Enum = append(s.Enum, v)
+		}
+		o.Parameters = append(o.Parameters, &Parameter{Name: p.Name, In: "query", Description: p.Description, Schema: s})
+	}
+	if op.Request != nil {
+		o.RequestBody = &RequestBody{Required: true, Content: jsonContent(d.SchemaOf(reflect.TypeOf(op.Request)))}
+	}
+	if op.Response != nil {
+		o.Responses["200"] = &Response{Description: "成功", Content: jsonContent(d.SchemaOf(reflect.TypeOf(op.Response)))}
+	} else {
+		o.Responses["200"] = &Response{Description: "成功"}
+	}
+	errBody := op.Error
+	if errBody == nil {
+		errBody = op.Response
+	}
+	if errBody != nil {
+		o.Responses["4XX"] = &Response{Description: "请求无效", Content: jsonContent(d.SchemaOf(reflect.TypeOf(errBody)))}
+	}
+	if middlewareError != nil {
+		errSchema := d.SchemaOf(reflect.TypeOf(middlewareError))
+		for status, desc := range errorStatuses {
+			o.Responses[status] = &Response{Description: desc, Content: jsonContent(errSchema)}
+		}
+		if op.Request != nil {
+			// 请求体校验失败时由中间件返回 middlewareError，其余 400 由处理函数返回
+			s := errSchema
+			if errBody != nil && reflect.TypeOf(errBody) != reflect.TypeOf(middlewareError) {
+				s = &Schema{OneOf: []*Schema{errSchema, d.SchemaOf(reflect.TypeOf(errBody))}}
+			}
+			o.Responses["400"] = &Response{Description: "请求体不符合接口定义或参数无效", Content: jsonContent(s)}
+		}
+	}
+	(*item)[strings.ToLower(op.Method)] = o
+}
+
+// 认证方式在 components.securitySchemes 中的名称
+const (
+	SchemeAPIKey = "apiKey"
+	SchemeBearer = "bearer"
+)
+
+// Secure 声明接口需要认证（API 密钥或 Bearer 令牌均可），并添加 401、403 响应
+// scope 写入接口说明；OpenAPI 3.0 中只有 OAuth2 能在 security 里列出作用域
+func (d *Document) Secure(method, ginPath, scope string, errBody any) {
+	o := d.Operation(method, ConvertPath(ginPath))
+	if o == nil {
+		return
+	}
+	if d.Components.SecuritySchemes == nil {
+		d.Components.SecuritySchemes = map[string]*SecurityScheme{
+			SchemeAPIKey: {Type: "apiKey", Name: "X-API-Key", In: "header"},
+			SchemeBearer: {Type: "http", Scheme: "bearer"},
+		}
+	}
+	o.Security = []map[string][]string{{SchemeAPIKey: {}}, {SchemeBearer: {}}}
+	note := "需要 " + scope + " 权限"
+	if o.Description != "" {
+		note = o.Description + "；" + note
+	}
+	o.Description = note
+	errSchema := jsonContent(d.SchemaOf(reflect.TypeOf(errBody)))
+	o.Responses["401"] = &Response{Description: "未提供凭据或凭据无效", Content: errSchema}
+	o.Responses["403"] = &Response{Description: "权限不足", Content: errSchema}
+}
+
+// Operation 返回指定方法与 OpenAPI 路径的接口
+func (d *Document) Operation(method, path string) *OperationObject {
+	item, ok := d.Paths[path]
+	if !ok {
+		return nil
+	}
+	return (*item)[strings.ToLower(method)]
+}
+
+// Resolve 解析 $ref，返回组件中的实际结构
+func (d *Document) Resolve(s *Schema) *Schema {
+	for s != nil && s.Ref != "" {
+		s = d.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	return s
+}
+
+func jsonContent(s *Schema) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: s}}
+}
+
+// ConvertPath 将 gin 风格的路径转换为 OpenAPI 路径
+func ConvertPath(path string) string {
+	p, _ := convertPath(path)
+	return p
+}
+
+// convertPath 将 /a/:id 转换为 /a/{id}，并返回路径参数名
+func convertPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			params = append(params, s[1:])
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// operationID 由方法与路径生成唯一的 operationId，如 post_api_blasting_tmto
+func operationID(method, path string) string {
+	r := strings.NewReplacer("/", "_", ":", "", "-", "_", "*", "")
+	return strings.ToLower(method) + r.Replace(path)
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// SchemaOf 生成类型的结构，结构体注册为组件并返回引用
+func (d *Document) SchemaOf(t reflect.Type) *Schema {
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := d.SchemaOf(t.Elem())
+		if s.Ref != "" {
+			return s
+		}
+		s.Nullable = true
+		return s
+	case reflect.Struct:
+		return &Schema{Ref: "#/components/schemas/" + d.component(t)}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: d.SchemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.SchemaOf(t.Elem())}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := 0.0
+		return &Schema{Type: "integer", Format: "int64", Minimum: &zero}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	}
+	// interface 等无法确定结构的类型允许任意值
+	return &Schema{}
+}
+
+// component 生成结构体组件并返回其名称，不同包的同名结构体以包名区分
+func (d *Document) component(t reflect.Type) string {
+	name := t.Name()
+	if existing, ok := d.types[name]; ok && existing != t {
+		pkg := t.PkgPath()
+		name = pkg[strings.LastIndex(pkg, "/")+1:] + "." + name
+	}
+	if _, ok := d.types[name]; ok {
+		return name
+	}
+	d.types[name] = t
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	// 先占位，避免递归结构无限展开
+	d.Components.Schemas[name] = s
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		jsonName, ok := fieldName(f)
+		if !ok {
+			continue
+		}
+		fs := d.SchemaOf(f.Type)
+		if applyBinding(fs, f.Tag.Get("binding")) {
+			s.Required = append(s.Required, jsonName)
+		}
+		s.Properties[jsonName] = fs
+	}
+	return name
+}
+
+// fieldName 返回字段在 JSON 中的名称，未导出或 json:"-" 的字段返回 false
+func fieldName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+	return name, true
+}
+
+// applyBinding 将 binding 标签中的约束写入结构，返回字段是否必填
+// 仅识别 required、oneof、min、max、len；required_without 等条件必填不计为必填
+func applyBinding(s *Schema, tag string) bool {
+	if tag == "" {
+		return false
+	}
+	// 引用类型的约束无法写在 $ref 旁（OpenAPI 3.0 会忽略），只保留必填
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "oneof":
+			if s.Ref != "" {
+				continue
+			}
+			for _, v := range strings.Fields(arg) {
+				s.Enum = append(s.Enum, enumValue(s.Type, v))
+			}
+		case "min", "max", "len":
+			n, err := strconv.ParseFloat(arg, 64)
+			if err != nil || s.Ref != "" {
+				continue
+			}
+			setBound(s, name, n)
+		}
+	}
+	return required
+}
+
+// setBound 按类型设置数值范围、字符串长度或数组长度
+func setBound(s *Schema, rule string, n float64) {
+	lower, upper := rule == "min" || rule == "len", rule == "max" || rule == "len"
+	switch s.Type {
+	case "integer", "number":
+		if lower {
+			s.Minimum = &n
+		}
+		if upper {
+			s.Maximum = &n
+		}
+	case "string":
+		if lower {
+			s.MinLength = ptr(int(n))
+		}
+		if upper {
+			s.MaxLength = ptr(int(n))
+		}
+	case "array":
+		if lower {
+			s.MinItems = ptr(int(n))
+		}
+		if upper {
+			s.MaxItems = ptr(int(n))
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// enumValue 将 oneof 中的取值转换为字段类型对应的 JSON 值
+func enumValue(typ, v string) any {
+	switch typ {
+	case "integer", "number":
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return n
+		}
+	}
+	return v
+}
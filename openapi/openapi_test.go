@@ -0,0 +1,85 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+type sampleItem struct {
+	ID    string `json:"id" binding:"required"`
+	Score int    `json:"score" binding:"min=0,max=100"`
+}
+
+type sampleRequest struct {
+	Mode    string            `json:"mode" binding:"omitempty,oneof=ctr ofb"`
+	Key     string            `json:"key" binding:"required_without=Auto"`
+	Auto    bool              `json:"auto"`
+	Note    *string           `json:"note"`
+	Items   []sampleItem      `json:"items" binding:"required"`
+	Stages  map[string]string `json:"stages"`
+	Seed    uint64            `json:"seed"`
+	Data    []byte            `json:"data"`
+	Skipped string            `json:"-"`
+	hidden  string
+}
+
+func TestSchemaOf(t *testing.T) {
+	d := New(Info{Title: "test", Version: "1"})
+	ref := d.SchemaOf(reflect.TypeOf(sampleRequest{}))
+	s := d.Resolve(ref)
+	if ref.Ref != "#/components/schemas/sampleRequest" || s == nil {
+		t.Fatalf("结构体应注册为组件: %+v", ref)
+	}
+	if !reflect.DeepEqual(s.Required, []string{"items"}) {
+		t.Errorf("required = %v，条件必填不应计入", s.Required)
+	}
+	if _, ok := s.Properties["Skipped"]; ok || len(s.Properties) != 8 {
+		t.Errorf("json:\"-\" 与未导出字段不应出现: %v", s.Properties)
+	}
+	if got := s.Properties["mode"].Enum; !reflect.DeepEqual(got, []any{"ctr", "ofb"}) {
+		t.Errorf("oneof 应转换为 enum: %v", got)
+	}
+	if !s.Properties["note"].Nullable || s.Properties["data"].Format != "byte" || s.Properties["stages"].AdditionalProperties.Type != "string" {
+		t.Errorf("指针、[]byte 或 map 的结构不正确: %+v", s.Properties)
+	}
+	item := d.Resolve(s.Properties["items"].Items)
+	if *item.Properties["score"].Minimum != 0 || *item.Properties["score"].Maximum != 100 {
+		t.Errorf("min/max 应转换为数值范围: %+v", item.Properties["score"])
+	}
+}
+
+func TestValidateBody(t *testing.T) {
+	d := New(Info{Title: "test", Version: "1"})
+	d.Add(Operation{Method: "POST", Path: "/x/:id", Request: sampleRequest{}}, nil)
+	op := d.Operation("POST", "/x/{id}")
+	if op == nil || len(op.Parameters) != 1 || op.Parameters[0].In != "path" {
+		t.Fatalf("路径参数应转换为 {id}: %+v", d.Paths)
+	}
+
+	cases := []struct {
+		body  string
+		field string
+		rule  string
+	}{
+		{`{"items":[]}`, "", ""},
+		{`{"items":[{"id":"a","score":100}],"note":null,"seed":18446744073709551615,"extra":1}`, "", ""},
+		{`{"mode":"ctr"}`, "items", "required"},
+		{`{"items":[{"id":""}]}`, "items[0].id", "required"},
+		{`{"items":[{"id":"a","score":101}]}`, "items[0].score", "maximum"},
+		{`{"items":[{"id":"a","score":1.5}]}`, "items[0].score", "type"},
+		{`{"items":[],"mode":"ecb"}`, "mode", "enum"},
+		{`{"items":[],"stages":{"IP":1}}`, "stages.IP", "type"},
+		{`{"items":[],"seed":-1}`, "seed", "minimum"},
+		{`{"items":`, "", "syntax"},
+	}
+	for _, tc := range cases {
+		err := d.ValidateBody(op, []byte(tc.body))
+		var field, rule string
+		if err != nil {
+			field, rule = err.Field, err.Rule
+		}
+		if field != tc.field || rule != tc.rule {
+			t.Errorf("%s: 得到 %q/%q，期望 %q/%q", tc.body, field, rule, tc.field, tc.rule)
+		}
+	}
+}
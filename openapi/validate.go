@@ -0,0 +1,175 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// ValidationError 请求体不符合文档时的错误
+type ValidationError struct {
+	Field string // 出错的字段路径，如 answers[0].id；为空表示整个请求体
+	Rule  string // 不满足的规则：syntax、required、type、enum、minimum、maximum、minLength、maxLength、minItems、maxItems
+	// Expected 期望的类型或界限，用于生成提示
+	Expected string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("请求体不符合规则 %s", e.Rule)
+	}
+	return fmt.Sprintf("字段 %s 不符合规则 %s（%s）", e.Field, e.Rule, e.Expected)
+}
+
+// ValidateBody 按接口的请求体结构校验 JSON，返回第一个错误
+// 与 gin 的 binding 一致：required 要求字段存在且不为零值；JSON 中的 null 视为未提供；不拒绝未知字段
+func (d *Document) ValidateBody(op *OperationObject, body []byte) *ValidationError {
+	if op == nil || op.RequestBody == nil {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return &ValidationError{Rule: "syntax"}
+	}
+	return d.validate(op.RequestBody.Content["application/json"].Schema, v, "")
+}
+
+func (d *Document) validate(s *Schema, v any, path string) *ValidationError {
+	s = d.Resolve(s)
+	if s == nil || v == nil {
+		return nil
+	}
+	fail := func(rule, expected string) *ValidationError {
+		return &ValidationError{Field: path, Rule: rule, Expected: expected}
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fail("type", "object")
+		}
+		for _, name := range s.Required {
+			if isZero(obj[name]) {
+				return &ValidationError{Field: join(path, name), Rule: "required"}
+			}
+		}
+		for name, value := range obj {
+			fs, ok := s.Properties[name]
+			if !ok {
+				fs = s.AdditionalProperties
+			}
+			if err := d.validate(fs, value, join(path, name)); err != nil {
+				return err
+			}
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return fail("type", "array")
+		}
+		if err := checkLength(s.MinItems, s.MaxItems, len(arr), "minItems", "maxItems", fail); err != nil {
+			return err
+		}
+		for i, item := range arr {
+			if err := d.validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fail("type", "string")
+		}
+		if len(s.Enum) > 0 && !inEnum(s.Enum, str) {
+			return fail("enum", fmt.Sprint(s.Enum...))
+		}
+		return checkLength(s.MinLength, s.MaxLength, utf8.RuneCountInString(str), "minLength", "maxLength", fail)
+	case "integer", "number":
+		num, ok := v.(json.Number)
+		if !ok {
+			return fail("type", s.Type)
+		}
+		f, err := num.Float64()
+		if err != nil || (s.Type == "integer" && !isInteger(num)) {
+			return fail("type", s.Type)
+		}
+		if len(s.Enum) > 0 && !inEnum(s.Enum, f) {
+			return fail("enum", fmt.Sprint(s.Enum...))
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			return fail("minimum", formatNumber(*s.Minimum))
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			return fail("maximum", formatNumber(*s.Maximum))
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fail("type", "boolean")
+		}
+	}
+	return nil
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// isZero 是否未提供或为零值（与 gin binding 的 required 一致）
+func isZero(v any) bool {
+	switch x := v.(type) {
+	case nil:
+		return true
+	case string:
+		return x == ""
+	case bool:
+		return !x
+	case json.Number:
+		f, err := x.Float64()
+		return err == nil && f == 0
+	}
+	return false
+}
+
+// isInteger 整数可能超出 int64（如 uint64 的种子），依次尝试有符号与无符号解析
+func isInteger(n json.Number) bool {
+	if _, err := strconv.ParseInt(n.String(), 10, 64); err == nil {
+		return true
+	}
+	_, err := strconv.ParseUint(n.String(), 10, 64)
+	return err == nil
+}
+
+func inEnum(enum []any, v any) bool {
+	for _, e := range enum {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func checkLength(min, max *int, n int, minRule, maxRule string, fail func(string, string) *ValidationError) *ValidationError {
+	if min != nil && n < *min {
+		return fail(minRule, strconv.Itoa(*min))
+	}
+	if max != nil && n > *max {
+		return fail(maxRule, strconv.Itoa(*max))
+	}
+	return nil
+}
+
+func formatNumber(f float64) string {
+	if f == math.Trunc(f) {
+		return strconv.FormatFloat(f, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,105 @@
+// Package ratelimit 按客户端与路由划分的令牌桶限流
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Budget 令牌桶参数：每秒补充 Rate 个令牌，最多积累 Burst 个
+// Rate 为 0 表示不限流
+type Budget struct {
+	Rate  float64 `yaml:"rate" toml:"rate"`
+	Burst int     `yaml:"burst" toml:"burst"`
+}
+
+// DefaultRoute 未单独配置的路由共用的预算名
+const DefaultRoute = "default"
+
+// IdentityKey gin.Context 中客户端身份（如 API 密钥 ID）的键，未设置时按客户端 IP 限流
+const IdentityKey = "ratelimit.identity"
+
+// Unlimited 是否不限流
+func (b Budget) Unlimited() bool {
+	return b.Rate <= 0
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time // 桶重新补满的时间，此后丢弃该桶与新建桶等价
+}
+
+// Limiter 令牌桶集合，每个键（客户端 + 路由分组）一个桶，并发安全
+type Limiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+	// 上次清理已补满的桶的时间
+	lastSweep time.Time
+}
+
+// sweepInterval 清理已补满的桶的间隔
+const sweepInterval = time.Minute
+
+// New 创建限流器
+func New() *Limiter {
+	return &Limiter{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow 尝试从 key 对应的桶中取一个令牌
+// 不允许时返回需要等待的时间，调用方据此设置 Retry-After
+func (l *Limiter) Allow(key string, b Budget) (bool, time.Duration) {
+	if b.Unlimited() {
+		return true, 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.sweep(now)
+
+	bk, ok := l.buckets[key]
+	if !ok {
+		bk = &bucket{tokens: float64(b.Burst), last: now}
+		l.buckets[key] = bk
+	}
+	bk.tokens = math.Min(float64(b.Burst), bk.tokens+now.Sub(bk.last).Seconds()*b.Rate)
+	bk.last = now
+	allowed := bk.tokens >= 1
+	if allowed {
+		bk.tokens--
+	}
+	bk.full = now.Add(seconds((float64(b.Burst) - bk.tokens) / b.Rate))
+	if allowed {
+		return true, 0
+	}
+	return false, seconds((1 - bk.tokens) / b.Rate)
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// sweep 定期删除已补满的桶，避免客户端数量增长导致内存无限增长；调用方须持有锁
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, bk := range l.buckets {
+		if !now.Before(bk.full) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Len 当前保存的桶数量
+func (l *Limiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
@@ -0,0 +1,41 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAllow(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := New()
+	l.now = func() time.Time { return now }
+	b := Budget{Rate: 2, Burst: 3}
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := l.Allow("a", b); !ok {
+			t.Fatalf("突发容量内第 %d 次请求被拒绝", i+1)
+		}
+	}
+	ok, wait := l.Allow("a", b)
+	if ok || wait != 500*time.Millisecond {
+		t.Fatalf("超出突发容量应被拒绝并等待 500ms，实际 %v %v", ok, wait)
+	}
+	if ok, _ := l.Allow("b", b); !ok {
+		t.Error("不同的键应使用独立的桶")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := l.Allow("a", b); !ok {
+		t.Error("等待 Retry-After 后应允许")
+	}
+	if ok, _ := l.Allow("a", Budget{}); !ok {
+		t.Error("Rate 为 0 时不限流")
+	}
+
+	// 补满后的桶在清理时删除
+	now = now.Add(2 * sweepInterval)
+	l.Allow("c", b)
+	if l.Len() != 1 {
+		t.Errorf("已补满的桶应被清理，剩余 %d 个", l.Len())
+	}
+}
@@ -0,0 +1,63 @@
+package router
+
+import (
+	"SDES/apierror"
+	"SDES/auth"
+	"SDES/logging"
+	"SDES/ratelimit"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// metricsScope /metrics 所需的作用域
+const metricsScope = auth.ScopeAdmin
+
+// authenticate 认证中间件：识别 API 密钥或令牌，按接口所需的作用域放行
+// scopes 为路由模板到作用域的映射，未列出的路由无需认证；提供了无效凭据的请求一律返回 401。
+// 须放在限流之前，已认证的请求按身份而不是 IP 限流
+func authenticate(a *auth.Authenticator, scopes map[string]string) gin.HandlerFunc {
+	challenge := func(c *gin.Context, e *apierror.Error) {
+		c.Header("WWW-Authenticate", `Bearer realm="sdes"`)
+		apierror.Abort(c, e)
+	}
+	return func(c *gin.Context) {
+		p, err := a.Authenticate(c.Request)
+		if err != nil {
+			code := apierror.InvalidCredentials
+			if errors.Is(err, auth.ErrExpired) {
+				code = apierror.TokenExpired
+			}
+			challenge(c, apierror.New(http.StatusUnauthorized, code, ""))
+			return
+		}
+		if p != nil {
+			c.Set(auth.PrincipalKey, p)
+			c.Set(ratelimit.IdentityKey, p.ID)
+			c.Request = c.Request.WithContext(logging.WithLogger(c.Request.Context(),
+				logging.FromContext(c.Request.Context()).With("principal", p.ID)))
+		}
+		scope := scopes[c.FullPath()]
+		if a.Allowed(p, scope) {
+			c.Next()
+			return
+		}
+		if p == nil {
+			challenge(c, apierror.New(http.StatusUnauthorized, apierror.AuthRequired, "", scope))
+			return
+		}
+		apierror.Abort(c, apierror.New(http.StatusForbidden, apierror.InsufficientScope, "", scope))
+	}
+}
+
+// routeScopes 路由模板到所需作用域的映射
+func routeScopes(routes []route) map[string]string {
+	m := map[string]string{"/metrics": metricsScope}
+	for _, rt := range routes {
+		if rt.Scope != "" {
+			m[rt.Path] = rt.Scope
+		}
+	}
+	return m
+}
@@ -0,0 +1,292 @@
+package router
+
+import (
+	"SDES/config"
+	"SDES/logging"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// call 发送 JSON 请求并解析响应体
+func call(t *testing.T, r http.Handler, method, path, body string) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var resp map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("%s %s: %v %s", method, path, err, w.Body)
+	}
+	return w, resp
+}
+
+// TestV1RoundTrip 各算法、工作模式与编码组合下加密后解密应还原数据
+func TestV1RoundTrip(t *testing.T) {
+	cfg := config.Default()
+	cfg.RateLimits = nil
+	r := newTestRouter(cfg)
+	keys := map[string]string{"sdes": "1010000010", "saes": "1010011100111011"}
+	ivs := map[string]string{"sdes": "11110000", "saes": "0000000011111111"}
+	inputs := []struct{ encoding, data string }{
+		{"ascii", "Hello, S-DES!"},
+		{"hex", "00ff10"},
+		{"base64", "AAECAwQ="},
+		{"binary", "1010101001010101"},
+	}
+	for alg, key := range keys {
+		for _, mode := range []string{"ecb", "cbc", "ctr"} {
+			for _, in := range inputs {
+				enc := map[string]string{
+					"algorithm": alg, "mode": mode, "key": key, "iv": ivs[alg],
+					"data": in.data, "input_encoding": in.encoding, "output_encoding": "hex",
+				}
+				body, _ := json.Marshal(enc)
+				w, resp := call(t, r, "POST", "/api/v1/encrypt", string(body))
+				if w.Code != http.StatusOK || resp["success"] != true || resp["encoding"] != "hex" {
+					t.Fatalf("加密 %s/%s/%s: %d %s", alg, mode, in.encoding, w.Code, w.Body)
+				}
+				dec := map[string]string{
+					"algorithm": alg, "mode": mode, "key": key, "iv": ivs[alg],
+					"data": resp["data"].(string), "input_encoding": "hex", "output_encoding": in.encoding,
+				}
+				body, _ = json.Marshal(dec)
+				w, resp = call(t, r, "POST", "/api/v1/decrypt", string(body))
+				if w.Code != http.StatusOK || resp["data"] != in.data {
+					t.Errorf("解密 %s/%s/%s: %d %s", alg, mode, in.encoding, w.Code, w.Body)
+				}
+			}
+		}
+	}
+
+	// auto_key 生成并返回密钥，非 ECB 模式同时生成 IV
+	for _, mode := range []string{"ecb", "cbc"} {
+		w, resp := call(t, r, "POST", "/api/v1/encrypt", `{"mode":"`+mode+`","auto_key":true,"exclude_weak_keys":true,"data":"auto"}`)
+		key, _ := resp["key"].(string)
+		iv, _ := resp["iv"].(string)
+		if w.Code != http.StatusOK || len(key) != 10 || (mode == "ecb") != (iv == "") || (iv != "" && len(iv) != 8) {
+			t.Fatalf("auto_key %s: %d %s", mode, w.Code, w.Body)
+		}
+		body, _ := json.Marshal(map[string]string{"mode": mode, "key": key, "iv": iv, "data": resp["data"].(string)})
+		if w, resp = call(t, r, "POST", "/api/v1/decrypt", string(body)); w.Code != http.StatusOK || resp["data"] != "auto" {
+			t.Errorf("auto_key %s 解密: %d %s", mode, w.Code, w.Body)
+		}
+	}
+	if w, resp := call(t, r, "POST", "/api/v1/encrypt", `{"key":"1010000010","data":"hi"}`); w.Code != http.StatusOK || resp["key"] != nil {
+		t.Errorf("未自动生成时不应返回密钥: %s", w.Body)
+	}
+
+	// 不补齐时长度必须是分组的整数倍；CTR 不受限制
+	w, resp := call(t, r, "POST", "/api/v1/encrypt", `{"algorithm":"saes","key":"1010011100111011","data":"abc","padding":"none"}`)
+	if w.Code != http.StatusBadRequest || resp["error"].(map[string]any)["code"] != "INVALID_DATA_LENGTH" {
+		t.Errorf("未补齐的奇数长度应返回 INVALID_DATA_LENGTH: %d %s", w.Code, w.Body)
+	}
+	w, _ = call(t, r, "POST", "/api/v1/encrypt", `{"algorithm":"saes","mode":"ctr","key":"1010011100111011","iv":"0000000011111111","data":"abc","padding":"none"}`)
+	if w.Code != http.StatusOK {
+		t.Errorf("CTR 不应要求整数倍长度: %d %s", w.Code, w.Body)
+	}
+	// 默认 PKCS#7 补齐，明文末尾的 0x00 解密后保留
+	_, enc := call(t, r, "POST", "/api/v1/encrypt", `{"algorithm":"saes","key":"1010011100111011","data":"61620000","input_encoding":"hex"}`)
+	w, resp = call(t, r, "POST", "/api/v1/decrypt", `{"algorithm":"saes","key":"1010011100111011","data":"`+enc["data"].(string)+`","output_encoding":"hex"}`)
+	if w.Code != http.StatusOK || resp["data"] != "61620000" {
+		t.Errorf("PKCS#7 往返应保留末尾 0x00: %d %s", w.Code, w.Body)
+	}
+	w, resp = call(t, r, "POST", "/api/v1/decrypt", `{"algorithm":"saes","key":"1010011100111011","data":"AAECAw=="}`)
+	if w.Code != http.StatusBadRequest || resp["error"].(map[string]any)["code"] != "INVALID_PADDING" {
+		t.Errorf("补齐无效应返回 INVALID_PADDING: %d %s", w.Code, w.Body)
+	}
+	w, resp = call(t, r, "POST", "/api/v1/encrypt", `{"mode":"cbc","key":"1010000010","data":"abc"}`)
+	if w.Code != http.StatusBadRequest || resp["error"].(map[string]any)["field"] != "iv" {
+		t.Errorf("CBC 缺少 IV 应报告 iv 字段: %d %s", w.Code, w.Body)
+	}
+}
+
+// TestLegacyMatchesV1 旧接口映射到 v1 后结果与直接调用 v1 一致
+func TestLegacyMatchesV1(t *testing.T) {
+	r := newTestRouter(config.Default())
+	pairs := []struct {
+		legacyPath, legacyBody, legacyField string
+		v1Path, v1Body                      string
+	}{
+		{"/api/encrypt", `{"plaintext":"10101010","key":"1010000010"}`, "ciphertext_binary",
+			"/api/v1/encrypt", `{"key":"1010000010","data":"10101010","input_encoding":"binary","output_encoding":"binary","padding":"none"}`},
+		{"/api/encrypt", `{"plaintext_ascii":"Hello","key":"1010011100111011","algorithm":"saes"}`, "ciphertext_base64",
+			"/api/v1/encrypt", `{"algorithm":"saes","key":"1010011100111011","data":"Hello","padding":"zero"}`},
+		{"/api/encrypt", `{"plaintext_ascii":"Hi","key":"1010000010","authenticated":true,"mac_key":"1111100000"}`, "tag",
+			"/api/v1/encrypt", `{"key":"1010000010","data":"Hi","mac_key":"1111100000","padding":"zero"}`},
+		{"/api/decrypt", `{"ciphertext":"00001001","key":"1010000010"}`, "plaintext",
+			"/api/v1/decrypt", `{"key":"1010000010","data":"00001001","input_encoding":"binary","output_encoding":"binary","padding":"none"}`},
+		{"/api/decrypt", `{"ciphertext_base64":"AAECAw==","key":"1010011100111011","algorithm":"saes"}`, "plaintext_ascii",
+			"/api/v1/decrypt", `{"algorithm":"saes","key":"1010011100111011","data":"AAECAw==","padding":"zero"}`},
+	}
+	for _, p := range pairs {
+		lw, legacy := call(t, r, "POST", p.legacyPath, p.legacyBody)
+		vw, v1 := call(t, r, "POST", p.v1Path, p.v1Body)
+		want := v1["data"]
+		if p.legacyField == "tag" {
+			want = v1["tag"]
+		}
+		if lw.Code != http.StatusOK || vw.Code != http.StatusOK || legacy[p.legacyField] == nil || legacy[p.legacyField] != want {
+			t.Errorf("%s %s\n旧接口 %d %s\nv1 %d %s", p.legacyPath, p.legacyBody, lw.Code, lw.Body, vw.Code, vw.Body)
+		}
+	}
+
+	// 暴力破解找到的密钥一致
+	_, legacy := call(t, r, "POST", "/api/blasting", `{"plaintext":"10101010","ciphertext":"00001001"}`)
+	_, v1 := call(t, r, "POST", "/api/v1/crack", `{"plaintext":"10101010","ciphertext":"00001001"}`)
+	if legacy["key_count"] == nil || legacy["key_count"] != v1["key_count"] || !slices.Equal(strs(legacy["keys"]), strs(v1["keys"])) {
+		t.Errorf("暴力破解结果不一致\n旧接口 %v\nv1 %v", legacy, v1)
+	}
+	// 未找到密钥：旧接口 success 为 false，v1 返回空列表
+	_, legacy = call(t, r, "POST", "/api/blasting", `{"plaintext":"10101010","ciphertext":"00000000","rounds":1}`)
+	_, v1 = call(t, r, "POST", "/api/v1/crack", `{"plaintext":"10101010","ciphertext":"00000000","rounds":1}`)
+	if legacy["success"] != false || v1["success"] != true || v1["key_count"] != 0.0 || len(strs(v1["keys"])) != 0 {
+		t.Errorf("未找到密钥时的结果不一致\n旧接口 %v\nv1 %v", legacy, v1)
+	}
+
+	// 错误中的字段名使用各自的命名
+	fields := []struct{ path, body, field string }{
+		{"/api/encrypt", `{"plaintext_ascii":"中","key":"1010000010"}`, "plaintext_ascii"},
+		{"/api/v1/encrypt", `{"data":"中","key":"1010000010"}`, "data"},
+		{"/api/decrypt", `{"ciphertext_base64":"@@","key":"1010000010"}`, "ciphertext_base64"},
+		{"/api/v1/decrypt", `{"data":"@@","key":"1010000010"}`, "data"},
+	}
+	for _, f := range fields {
+		w, resp := call(t, r, "POST", f.path, f.body)
+		detail, _ := resp["error"].(map[string]any)
+		if w.Code != http.StatusBadRequest || detail == nil || detail["field"] != f.field ||
+			!strings.Contains(detail["message"].(string), f.field) {
+			t.Errorf("%s %s: %d %s", f.path, f.body, w.Code, w.Body)
+		}
+	}
+}
+
+func strs(v any) []string {
+	var out []string
+	items, _ := v.([]any)
+	for _, item := range items {
+		out = append(out, item.(string))
+	}
+	return out
+}
+
+// TestDeprecation 旧接口带 Deprecation 与 Link 响应头并在文档中标记为已弃用，v1 接口不带
+func TestDeprecation(t *testing.T) {
+	r := newTestRouter(config.Default())
+	doc := specOf(t, r)
+	cases := []struct{ method, path, successor string }{
+		{"POST", "/api/encrypt", "/api/v1/encrypt"},
+		{"POST", "/api/decrypt", "/api/v1/decrypt"},
+		{"POST", "/api/blasting", "/api/v1/crack"},
+		{"GET", "/api/algorithms", "/api/v1/algorithms"},
+		{"POST", "/api/v1/encrypt", ""},
+		{"POST", "/api/v1/crack", ""},
+		{"GET", "/api/v1/algorithms", ""},
+	}
+	for _, tc := range cases {
+		w, _ := call(t, r, tc.method, tc.path, "{}")
+		deprecated := tc.successor != ""
+		if got := w.Header().Get("Deprecation") != ""; got != deprecated {
+			t.Errorf("%s %s: Deprecation = %q", tc.method, tc.path, w.Header().Get("Deprecation"))
+		}
+		if deprecated {
+			if w.Header().Get("Deprecation") != "@1792368000" {
+				t.Errorf("%s: Deprecation = %q", tc.path, w.Header().Get("Deprecation"))
+			}
+			if link := `<` + tc.successor + `>; rel="successor-version"`; w.Header().Get("Link") != link {
+				t.Errorf("%s: Link = %q，应为 %q", tc.path, w.Header().Get("Link"), link)
+			}
+		}
+		if op := doc.Operation(tc.method, tc.path); op == nil || op.Deprecated != deprecated {
+			t.Errorf("%s %s: 文档中的 deprecated 标记错误", tc.method, tc.path)
+		}
+	}
+}
+
+// TestExerciseIDsSigned 评分只接受本服务签发的题目 ID，自行构造的 kind-seed ID 被拒绝
+func TestExerciseIDsSigned(t *testing.T) {
+	cfg := config.Default()
+	cfg.Features.Exercises = true
+	r := newTestRouter(cfg)
+
+	_, resp := call(t, r, http.MethodPost, "/api/exercises", `{"seed":42,"count":1,"kinds":["subkeys"]}`)
+	exercises, _ := resp["exercises"].([]any)
+	if len(exercises) != 1 {
+		t.Fatalf("exercises: %v", resp)
+	}
+	id := exercises[0].(map[string]any)["id"].(string)
+	raw, _, ok := strings.Cut(id, ".")
+	if !ok {
+		t.Fatalf("题目 ID 未签名: %s", id)
+	}
+
+	body := func(id string) string {
+		return `{"answers":[{"id":"` + id + `","answer":"x","stages":{"P10":"0"}}]}`
+	}
+	_, resp = call(t, r, http.MethodPost, "/api/exercises/grade", body(id))
+	if result := resp["results"].([]any)[0].(map[string]any); result["kind"] != "subkeys" {
+		t.Errorf("签发的 ID 未被评分: %v", result)
+	}
+	for _, forged := range []string{raw, "subkeys-43." + strings.SplitN(id, ".", 2)[1]} {
+		_, resp = call(t, r, http.MethodPost, "/api/exercises/grade", body(forged))
+		if result := resp["results"].([]any)[0].(map[string]any); result["kind"] != nil || result["points"] != 0.0 {
+			t.Errorf("伪造的 ID %s 被评分: %v", forged, result)
+		}
+	}
+}
+
+// TestCrackLogsKeyCountOnly info 级别只记录找到的密钥数，密钥本身只在 debug 级别且开启 LogSecrets 时输出
+func TestCrackLogsKeyCountOnly(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+	cases := []struct {
+		opts     logging.Options
+		keysLine string // 为空表示日志中不应出现 keys 字段
+	}{
+		{logging.Options{Level: "info", Format: "text"}, ""},
+		{logging.Options{Level: "debug", Format: "text"}, "keys=" + logging.Redacted},
+		{logging.Options{Level: "debug", Format: "text", LogSecrets: true}, "keys=\"[0001110011"},
+	}
+	for _, tc := range cases {
+		var buf bytes.Buffer
+		logger, err := logging.New(&buf, tc.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		slog.SetDefault(logger)
+		r := newTestRouter(config.Default())
+		call(t, r, http.MethodPost, "/api/v1/crack", `{"plaintext":"10101010","ciphertext":"00001001"}`)
+
+		out := buf.String()
+		if !strings.Contains(out, "key_count=4") {
+			t.Errorf("%+v: 缺少 key_count: %s", tc.opts, out)
+		}
+		if tc.keysLine == "" && strings.Contains(out, "keys=") || tc.keysLine != "" && !strings.Contains(out, tc.keysLine) {
+			t.Errorf("%+v: keys 字段不符合预期 %q: %s", tc.opts, tc.keysLine, out)
+		}
+	}
+}
+
+// TestCrackMetricsKeysTested 查密码本不计入测试的密钥数，位切片穷举计入整个密钥空间
+func TestCrackMetricsKeysTested(t *testing.T) {
+	cfg := config.Default()
+	cfg.RateLimits = nil
+	r := newTestRouter(cfg)
+	call(t, r, http.MethodPost, "/api/v1/crack", `{"plaintext":"10101010","ciphertext":"00001001"}`)
+	call(t, r, http.MethodPost, "/api/v1/crack", `{"plaintext":"10101010","ciphertext":"00001001","rounds":3}`)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+	if strings.Contains(body, `sdes_bruteforce_keys_tested_total{algorithm="sdes",method="codebook"}`) {
+		t.Error("查密码本不应计入测试的密钥数")
+	}
+	if !strings.Contains(body, `sdes_bruteforce_keys_tested_total{algorithm="sdes",method="bitslice"}`) {
+		t.Errorf("缺少位切片穷举的密钥数:\n%s", body)
+	}
+}
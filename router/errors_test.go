@@ -0,0 +1,163 @@
+package router
+
+import (
+	"SDES/config"
+	"SDES/controller"
+	"SDES/dto/response"
+	"SDES/utils/oracle"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrorEnvelope(t *testing.T) {
+	r := newTestRouter(config.Default())
+	cases := []struct {
+		path, body, lang string
+		status           int
+		code, field, msg string
+	}{
+		{"/api/encrypt", `{"plaintext":"10101010","key":"10100"}`, "en-US,en;q=0.9", http.StatusBadRequest,
+			"INVALID_KEY_LENGTH", "key", "key must be a 10-bit binary string"},
+		{"/api/encrypt", `{"plaintext":"1010101x","key":"1010000010"}`, "", http.StatusBadRequest,
+			"INVALID_BINARY", "plaintext", "plaintext 只能包含 0 和 1"},
+		{"/api/decrypt", `{"ciphertext_base64":"@@","key":"1010000010"}`, "en", http.StatusBadRequest,
+			"BASE64_DECODE_FAILED", "ciphertext_base64", "ciphertext_base64 is not valid Base64"},
+		{"/api/blasting", `{"plaintext":"10101010","ciphertext":"0101"}`, "zh-CN", http.StatusBadRequest,
+			"INVALID_BLOCK_LENGTH", "ciphertext", "ciphertext 必须是 8 位二进制字符串"},
+		{"/api/blasting", `{`, "en", http.StatusBadRequest,
+			"INVALID_REQUEST", "", "malformed request body"},
+		{"/api/v1/crack/tmto", `{"method":"md5"}`, "en", http.StatusBadRequest,
+			"INVALID_VALUE", "method", "method is invalid (oneof hellman rainbow)"},
+		{"/api/v1/crack/tmto", `{"chains":5000}`, "zh-CN", http.StatusBadRequest,
+			"INVALID_VALUE", "chains", "chains 的取值不符合要求（max 1024）"},
+		{"/api/keyschedule", `{}`, "en", http.StatusBadRequest,
+			"MISSING_FIELD", "key", "key / k1 / k2 is required"},
+		{"/api/keyschedule", `{"k1":"0101"}`, "en", http.StatusBadRequest,
+			"INVALID_KEY_LENGTH", "k1", "k1 must be a 8-bit binary string"},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", tc.lang)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var resp response.ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Error == nil {
+			t.Fatalf("%s %s: 响应缺少 error 字段: %s", tc.path, tc.body, w.Body.String())
+		}
+		if w.Code != tc.status || resp.Success || resp.Error.Code != tc.code || resp.Error.Field != tc.field ||
+			resp.Error.Message != tc.msg || resp.Message != tc.msg {
+			t.Errorf("%s %s: status %d，响应 %s", tc.path, tc.body, w.Code, w.Body.String())
+		}
+	}
+}
+
+// 无效的 TMTO 参数在占用暴力破解名额之前被拒绝，名额已满时也立即返回 400 而不是排队
+func TestTMTOValidatesBeforeQueueing(t *testing.T) {
+	controller.ConfigureBruteForce(2, 0)
+	defer controller.ConfigureBruteForce(2, 8)
+	r := newTestRouter(config.Default())
+
+	start := time.Now()
+	w := post(r, "/api/v1/crack/tmto", strings.NewReader(`{"tables":99}`), -1)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "INVALID_VALUE") {
+		t.Fatalf("status %d，响应 %s", w.Code, w.Body.String())
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("无效请求排队等待了 %v", elapsed)
+	}
+}
+
+// 预言机查询次数用完后返回 409 与专用错误码，不带 Retry-After，与限流区分
+func TestOracleBudgetExhausted(t *testing.T) {
+	cfg := oracle.DefaultConfig
+	cfg.EncryptBudget = 1
+	controller.ConfigureOracle(cfg)
+	defer controller.ConfigureOracle(oracle.DefaultConfig)
+	conf := config.Default()
+	conf.Features.Oracle = true
+	r := newTestRouter(conf)
+
+	_, created := call(t, r, "POST", "/api/oracle/session", "")
+	id := created["session"].(map[string]any)["session_id"].(string)
+	query := `{"session_id":"` + id + `","block":"10101010"}`
+	if w, _ := call(t, r, "POST", "/api/oracle/encrypt", query); w.Code != http.StatusOK {
+		t.Fatalf("第一次查询: %d %s", w.Code, w.Body)
+	}
+	w, resp := call(t, r, "POST", "/api/oracle/encrypt", query)
+	detail, _ := resp["error"].(map[string]any)
+	if w.Code != http.StatusConflict || detail["code"] != "ORACLE_BUDGET_EXHAUSTED" || w.Header().Get("Retry-After") != "" {
+		t.Errorf("预算用完: %d %v %s", w.Code, w.Header(), w.Body)
+	}
+	if resp["session"] == nil {
+		t.Errorf("错误响应应保留会话状态: %s", w.Body)
+	}
+
+	w, resp = call(t, r, "GET", "/api/oracle/session/unknown", "")
+	if detail, _ := resp["error"].(map[string]any); w.Code != http.StatusNotFound || detail["code"] != "ORACLE_SESSION_NOT_FOUND" {
+		t.Errorf("会话不存在: %d %s", w.Code, w.Body)
+	}
+}
+
+// 各组处理函数的错误路径都返回统一的错误信封：success 为 false，message 与 error.message 一致
+func TestHandlerErrorEnvelopes(t *testing.T) {
+	cfg := config.Default()
+	cfg.Features.Analysis = true
+	cfg.Features.Oracle = true
+	cfg.Features.Exercises = true
+	cfg.Features.Classical = true
+	cfg.History.Public = true
+	r := newTestRouter(cfg)
+
+	_, created := call(t, r, "POST", "/api/v1/oracle/session", "")
+	session := created["session"].(map[string]any)["session_id"].(string)
+
+	cases := []struct {
+		method, path, body, lang string
+		status                   int
+		code, field, msg         string
+	}{
+		{"POST", "/api/v1/mac", `{"key":"1010000010","algorithm":"hmac"}`, "", http.StatusBadRequest, "INVALID_VALUE", "algorithm", ""},
+		{"POST", "/api/v1/hash", `{"message_ascii":"abc","bits":12}`, "", http.StatusBadRequest, "INVALID_VALUE", "bits", ""},
+		{"POST", "/api/v1/prng", `{"length":70000}`, "", http.StatusBadRequest, "INVALID_VALUE", "length", ""},
+		{"POST", "/api/v1/analysis/rounds", `{"rounds":[1,2,3,4,5,6,7]}`, "", http.StatusBadRequest, "INVALID_VALUE", "rounds", ""},
+		{"POST", "/api/v1/analysis/related-keys", `{"subkey":"01"}`, "", http.StatusBadRequest, "INVALID_KEY_LENGTH", "subkey", ""},
+		{"POST", "/api/v1/classical/encrypt", `{"algorithm":"caesar","text":"HELLO","key":"x"}`, "en", http.StatusBadRequest,
+			"INVALID_CLASSICAL_KEY", "key", "key does not match the format required by caesar"},
+		{"POST", "/api/v1/exercises", `{"kinds":["md5"]}`, "", http.StatusBadRequest, "INVALID_VALUE", "kinds", ""},
+		{"GET", "/api/v1/history", "", "en", http.StatusNotFound, "HISTORY_DISABLED", "", "operation history is disabled"},
+		{"POST", "/api/v1/oracle/submit", `{"session_id":"` + session + `","key":"01"}`, "", http.StatusBadRequest, "INVALID_KEY_LENGTH", "key", ""},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", tc.lang)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var resp response.ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Error == nil {
+			t.Fatalf("%s %s: 响应缺少 error 字段: %s", tc.path, tc.body, w.Body.String())
+		}
+		if w.Code != tc.status || resp.Success || resp.Error.Code != tc.code || resp.Error.Field != tc.field ||
+			resp.Message != resp.Error.Message || (tc.msg != "" && resp.Message != tc.msg) {
+			t.Errorf("%s %s: status %d，响应 %s", tc.path, tc.body, w.Code, w.Body.String())
+		}
+	}
+
+	// 评分时无效的题目 ID 不影响其余题目，错误详情放在对应的结果中
+	w, resp := call(t, r, "POST", "/api/v1/exercises/grade", `{"answers":[{"id":"forged","answer":"0"}]}`)
+	results, _ := resp["results"].([]any)
+	if w.Code != http.StatusOK || len(results) != 1 {
+		t.Fatalf("评分: %d %s", w.Code, w.Body)
+	}
+	detail, _ := results[0].(map[string]any)["error"].(map[string]any)
+	if detail["code"] != "INVALID_EXERCISE_ID" || detail["field"] != "id" {
+		t.Errorf("无效题目 ID: %s", w.Body)
+	}
+}
@@ -0,0 +1,86 @@
+package router
+
+import (
+	"SDES/apierror"
+	"SDES/ratelimit"
+	"bytes"
+	"errors"
+	"io"
+	"maps"
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimit 令牌桶限流中间件
+// 配置了预算的路由各自使用独立的桶，其余路由共用 default 桶；客户端以认证身份或 IP 区分，
+// IP 只在请求来自 config.TrustedProxies 中的代理时取自 X-Forwarded-For
+// successors 将已弃用的旧路径映射到对应的 v1 路径，二者共用一个桶；只为旧路径配置的预算视为 v1 路径的预算
+func rateLimit(budgets map[string]ratelimit.Budget, successors map[string]string) gin.HandlerFunc {
+	budgets = maps.Clone(budgets)
+	for old, successor := range successors {
+		if b, ok := budgets[old]; ok {
+			if _, set := budgets[successor]; !set {
+				budgets[successor] = b
+			}
+			delete(budgets, old)
+		}
+	}
+	limiter := ratelimit.New()
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if successor, ok := successors[route]; ok {
+			route = successor
+		}
+		budget, ok := budgets[route]
+		if !ok {
+			route = ratelimit.DefaultRoute
+			budget = budgets[route]
+		}
+		identity := c.GetString(ratelimit.IdentityKey)
+		if identity == "" {
+			identity = "ip:" + c.ClientIP()
+		}
+
+		allowed, wait := limiter.Allow(identity+" "+route, budget)
+		if allowed {
+			c.Next()
+			return
+		}
+		retryAfter := int(math.Ceil(wait.Seconds()))
+		e := apierror.New(http.StatusTooManyRequests, apierror.RateLimited, "", retryAfter)
+		e.RetryAfter = retryAfter
+		apierror.Abort(c, e)
+	}
+}
+
+// bodyLimit 请求体大小限制中间件，超出 maxBytes 时返回 413
+// 请求体在此读入内存（上限即 maxBytes），使分块传输的请求同样能在处理函数之前得到 413
+func bodyLimit(maxBytes int64) gin.HandlerFunc {
+	tooLarge := func(c *gin.Context) {
+		apierror.Abort(c, apierror.New(http.StatusRequestEntityTooLarge, apierror.BodyTooLarge, "", maxBytes))
+	}
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > maxBytes {
+			tooLarge(c)
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes))
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				tooLarge(c)
+				return
+			}
+			apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.InvalidBody, ""))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
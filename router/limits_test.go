@@ -0,0 +1,114 @@
+package router
+
+import (
+	"SDES/config"
+	"SDES/ratelimit"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestRouter(cfg *config.Config) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	InitRouter(r, cfg)
+	return r
+}
+
+func post(r http.Handler, path string, body io.Reader, contentLength int64) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, body)
+	req.Header.Set("Content-Type", "application/json")
+	req.ContentLength = contentLength
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit(t *testing.T) {
+	cfg := config.Default()
+	cfg.RateLimits = map[string]ratelimit.Budget{
+		ratelimit.DefaultRoute: {Rate: 100, Burst: 100},
+		"/api/blasting":        {Rate: 0.5, Burst: 2},
+	}
+	r := newTestRouter(cfg)
+	body := `{"plaintext":"10101010","ciphertext":"00001001"}`
+
+	for i := 0; i < 2; i++ {
+		if w := post(r, "/api/blasting", strings.NewReader(body), -1); w.Code != http.StatusOK {
+			t.Fatalf("突发容量内的请求失败: %d %s", w.Code, w.Body)
+		}
+	}
+	w := post(r, "/api/blasting", strings.NewReader(body), -1)
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "2" {
+		t.Fatalf("超出预算应返回 429 与 Retry-After: 2，实际 %d %q", w.Code, w.Header().Get("Retry-After"))
+	}
+	if !strings.Contains(w.Body.String(), `"code":"RATE_LIMITED"`) || !strings.Contains(w.Body.String(), `"retry_after":2`) {
+		t.Errorf("429 响应缺少错误码: %s", w.Body)
+	}
+	// 旧路径与对应的 v1 路径共用同一个桶
+	if w := post(r, "/api/v1/crack", strings.NewReader(body), -1); w.Code != http.StatusTooManyRequests {
+		t.Errorf("/api/v1/crack 应与 /api/blasting 共用预算，实际 %d", w.Code)
+	}
+	// 其他路由使用独立的预算
+	if w := post(r, "/api/encrypt", strings.NewReader(`{"plaintext":"10101010","key":"1010000010"}`), -1); w.Code != http.StatusOK {
+		t.Errorf("其他路由不应受 /api/blasting 预算影响: %d", w.Code)
+	}
+}
+
+// TestRateLimitIgnoresForwardedFor 默认不信任任何代理，轮换 X-Forwarded-For 不能绕过按 IP 的限流；
+// 请求来自可信代理时按 X-Forwarded-For 中的客户端 IP 分别计数
+func TestRateLimitIgnoresForwardedFor(t *testing.T) {
+	body := `{"plaintext":"10101010","key":"1010000010"}`
+	send := func(r http.Handler, i int) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/encrypt", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("203.0.113.%d", i))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	for _, proxies := range [][]string{nil, {"192.0.2.0/24"}} {
+		cfg := config.Default()
+		cfg.RateLimits = map[string]ratelimit.Budget{ratelimit.DefaultRoute: {Rate: 0.1, Burst: 2}}
+		cfg.TrustedProxies = proxies
+		r := newTestRouter(cfg)
+		limited := 0
+		for i := 0; i < 5; i++ {
+			if send(r, i) == http.StatusTooManyRequests {
+				limited++
+			}
+		}
+		// httptest 请求的对端地址为 192.0.2.1
+		if want := map[bool]int{true: 3, false: 0}[proxies == nil]; limited != want {
+			t.Errorf("trusted_proxies=%v: %d 个请求被限流，应为 %d", proxies, limited, want)
+		}
+	}
+}
+
+func TestSizeLimits(t *testing.T) {
+	cfg := config.Default()
+	cfg.MaxBodyBytes = 1024
+	r := newTestRouter(cfg)
+
+	big := `{"plaintext_ascii":"` + strings.Repeat("a", 2000) + `","key":"1010000010"}`
+	// 声明了长度与分块传输（长度未知）两种情况
+	for _, length := range []int64{int64(len(big)), -1} {
+		w := post(r, "/api/encrypt", strings.NewReader(big), length)
+		if w.Code != http.StatusRequestEntityTooLarge || !strings.Contains(w.Body.String(), `"code":"BODY_TOO_LARGE"`) {
+			t.Errorf("Content-Length %d: 应返回 413，实际 %d %s", length, w.Code, w.Body)
+		}
+	}
+
+	cfg.MaxBodyBytes = 1 << 20
+	r = newTestRouter(cfg)
+	long := `{"plaintext_ascii":"` + strings.Repeat("a", cfg.MaxPlaintextBytes+1) + `","key":"1010000010"}`
+	w := post(r, "/api/encrypt", strings.NewReader(long), -1)
+	if w.Code != http.StatusRequestEntityTooLarge || !strings.Contains(w.Body.String(), `"code":"PLAINTEXT_TOO_LONG"`) {
+		t.Errorf("明文过长应返回 413，实际 %d %s", w.Code, w.Body)
+	}
+}
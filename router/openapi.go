@@ -0,0 +1,63 @@
+package router
+
+import (
+	"SDES/apierror"
+	"SDES/openapi"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// specHandler 返回 /api/openapi.json 的处理函数，文档在启动时序列化一次
+func specHandler(doc *openapi.Document) gin.HandlerFunc {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		panic("生成 OpenAPI 文档失败: " + err.Error())
+	}
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+	}
+}
+
+// validateRequest 按 OpenAPI 文档校验 JSON 请求体，不符合时返回 400 与统一错误响应
+// 须放在 bodyLimit 之后，请求体已读入内存；空请求体交由处理函数判断
+func validateRequest(doc *openapi.Document) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		op := doc.Operation(c.Request.Method, openapi.ConvertPath(c.FullPath()))
+		if op == nil || op.RequestBody == nil || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			apierror.Abort(c, apierror.New(http.StatusBadRequest, apierror.InvalidBody, ""))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		if len(bytes.TrimSpace(body)) == 0 {
+			c.Next()
+			return
+		}
+		if verr := doc.ValidateBody(op, body); verr != nil {
+			apierror.Abort(c, validationError(verr))
+			return
+		}
+		c.Next()
+	}
+}
+
+// validationError 将文档校验错误转换为统一错误码
+func validationError(e *openapi.ValidationError) *apierror.Error {
+	switch e.Rule {
+	case "syntax":
+		return apierror.New(http.StatusBadRequest, apierror.InvalidRequest, "")
+	case "required":
+		return apierror.New(http.StatusBadRequest, apierror.MissingField, e.Field, e.Field)
+	case "type":
+		return apierror.New(http.StatusBadRequest, apierror.InvalidType, e.Field, e.Field, e.Expected)
+	}
+	return apierror.New(http.StatusBadRequest, apierror.InvalidValue, e.Field, e.Field, e.Rule, e.Expected)
+}
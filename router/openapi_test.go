@@ -0,0 +1,201 @@
+package router
+
+import (
+	"SDES/config"
+	"SDES/openapi"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// specOf 读取 /api/openapi.json
+func specOf(t *testing.T, r http.Handler) *openapi.Document {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("/api/openapi.json 返回 %d", w.Code)
+	}
+	var doc openapi.Document
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.OpenAPI != openapi.Version {
+		t.Fatalf("openapi = %q", doc.OpenAPI)
+	}
+	return &doc
+}
+
+// TestSpecCoversRoutes 注册的每个 API 路由都必须出现在文档中，文档中也不能有不存在的接口
+func TestSpecCoversRoutes(t *testing.T) {
+	cfg := config.Default()
+	cfg.Features = config.Features{Blasting: true, Analysis: true, Oracle: true, Exercises: true, Classical: true}
+	cfg.History.Public = true
+	r := newTestRouter(cfg)
+	doc := specOf(t, r)
+
+	var registered, documented []string
+	for _, rt := range r.Routes() {
+		if strings.HasPrefix(rt.Path, "/api/") && rt.Path != "/api/openapi.json" && rt.Path != "/api/docs" {
+			registered = append(registered, rt.Method+" "+openapi.ConvertPath(rt.Path))
+		}
+	}
+	for path, item := range doc.Paths {
+		for method := range *item {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(registered)
+	sort.Strings(documented)
+	if strings.Join(registered, "\n") != strings.Join(documented, "\n") {
+		t.Errorf("路由与文档不一致\n路由:\n%s\n文档:\n%s", strings.Join(registered, "\n"), strings.Join(documented, "\n"))
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/docs", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "/api/openapi.json") {
+		t.Errorf("/api/docs 返回 %d", w.Code)
+	}
+}
+
+// TestResponsesMatchSpec 处理函数实际返回的字段与类型必须在文档中声明
+func TestResponsesMatchSpec(t *testing.T) {
+	cfg := config.Default()
+	cfg.History.Public = true
+	r := newTestRouter(cfg)
+	doc := specOf(t, r)
+
+	cases := []struct{ method, path, body string }{
+		{"POST", "/api/encrypt", `{"plaintext":"10101010","key":"1010000010"}`},
+		{"POST", "/api/encrypt", `{"plaintext_ascii":"hi","auto_key":true,"authenticated":true,"mac_key":"1111100000"}`},
+		{"POST", "/api/encrypt", `{"plaintext":"10101010","key":"1"}`},
+		{"POST", "/api/decrypt", `{"ciphertext":"10101010","key":"1010000010","tag":"00000000","mac_key":"1111100000"}`},
+		{"POST", "/api/decrypt", `{"ciphertext_base64":"AAE=","key":"1010000010101010","algorithm":"saes"}`},
+		{"POST", "/api/blasting", `{"plaintext":"10101010","ciphertext":"00001001"}`},
+		{"POST", "/api/blasting", `{"plaintext":"10101010","ciphertext":"00000000","rounds":1}`},
+		{"POST", "/api/v1/encrypt", `{"key":"1010000010","data":"hi","mac_key":"1111100000"}`},
+		{"POST", "/api/v1/encrypt", `{"key":"1010000010","data":"hi","input_encoding":"hex"}`},
+		{"POST", "/api/v1/decrypt", `{"key":"1010000010","data":"AAE=","tag":"00000000","mac_key":"1111100000"}`},
+		{"POST", "/api/v1/decrypt", `{"algorithm":"saes","mode":"cbc","key":"1010000010101010","iv":"0000000100000010","data":"AAECAw==","input_encoding":"base64"}`},
+		{"POST", "/api/v1/crack", `{"plaintext":"10101010","ciphertext":"00001001"}`},
+		{"POST", "/api/v1/crack", `{"plaintext":"10101010","ciphertext":"0101"}`},
+		{"GET", "/api/v1/algorithms", ""},
+		{"GET", "/api/algorithms", ""},
+		{"GET", "/api/keys/random", ""},
+		{"GET", "/api/history", ""},
+		{"POST", "/api/mac", `{"message_ascii":"abc","key":"1010000010"}`},
+		{"POST", "/api/hash", `{"message_ascii":"abc"}`},
+		{"POST", "/api/keyschedule", `{"key":"1010000010"}`},
+		{"POST", "/api/exercises", `{"seed":42,"count":2}`},
+		{"POST", "/api/oracle/session", ""},
+		{"POST", "/api/classical/encrypt", `{"algorithm":"caesar","text":"hello","key":"3"}`},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		op := doc.Operation(tc.method, tc.path)
+		if op == nil {
+			t.Errorf("%s %s 不在文档中", tc.method, tc.path)
+			continue
+		}
+		resp := op.Responses[fmt.Sprint(w.Code)]
+		if resp == nil {
+			resp = op.Responses[fmt.Sprintf("%dXX", w.Code/100)]
+		}
+		if resp == nil {
+			t.Errorf("%s %s: 文档未声明状态码 %d", tc.method, tc.path, w.Code)
+			continue
+		}
+		var body any
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s %s: %v", tc.method, tc.path, err)
+		}
+		for _, problem := range conform(doc, resp.Content["application/json"].Schema, body, "") {
+			t.Errorf("%s %s %s (%d): %s", tc.method, tc.path, tc.body, w.Code, problem)
+		}
+	}
+}
+
+// conform 检查 JSON 值与结构是否一致，包括未声明的字段
+func conform(doc *openapi.Document, s *openapi.Schema, v any, path string) []string {
+	s = doc.Resolve(s)
+	if v == nil {
+		return nil
+	}
+	if len(s.OneOf) > 0 {
+		for _, alt := range s.OneOf {
+			if conform(doc, alt, v, path) == nil {
+				return nil
+			}
+		}
+		return []string{path + ": 不符合任何一个 oneOf 结构"}
+	}
+	var problems []string
+	switch x := v.(type) {
+	case map[string]any:
+		if s.Type != "object" {
+			return []string{path + ": 应为 " + s.Type}
+		}
+		for k, item := range x {
+			fs, ok := s.Properties[k]
+			if !ok {
+				fs = s.AdditionalProperties
+			}
+			if fs == nil {
+				problems = append(problems, path+"."+k+": 文档未声明该字段")
+				continue
+			}
+			problems = append(problems, conform(doc, fs, item, path+"."+k)...)
+		}
+	case []any:
+		if s.Type != "array" {
+			return []string{path + ": 应为 " + s.Type}
+		}
+		for i, item := range x {
+			problems = append(problems, conform(doc, s.Items, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case string:
+		if s.Type != "string" {
+			problems = append(problems, path+": 应为 "+s.Type)
+		}
+	case float64:
+		if s.Type != "integer" && s.Type != "number" {
+			problems = append(problems, path+": 应为 "+s.Type)
+		}
+	case bool:
+		if s.Type != "boolean" {
+			problems = append(problems, path+": 应为 "+s.Type)
+		}
+	}
+	return problems
+}
+
+func TestRequestValidation(t *testing.T) {
+	r := newTestRouter(config.Default())
+	cases := []struct {
+		path, body, code, field string
+	}{
+		{"/api/encrypt", `{"plaintext":10101010,"key":"1010000010"}`, "INVALID_TYPE", "plaintext"},
+		{"/api/decrypt", `{"ciphertext":"10101010"}`, "MISSING_FIELD", "key"},
+		{"/api/exercises/grade", `{"answers":[{"id":1}]}`, "INVALID_TYPE", "answers[0].id"},
+		{"/api/oracle/submit", `{"session_id":"x"}`, "MISSING_FIELD", "key"},
+		{"/api/blasting", `{"plaintext":`, "INVALID_REQUEST", ""},
+	}
+	for _, tc := range cases {
+		w := post(r, tc.path, strings.NewReader(tc.body), int64(len(tc.body)))
+		want := fmt.Sprintf(`"code":%q`, tc.code)
+		if tc.field != "" {
+			want += fmt.Sprintf(`,"field":%q`, tc.field)
+		}
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), want) {
+			t.Errorf("%s %s: status %d，响应 %s", tc.path, tc.body, w.Code, w.Body.String())
+		}
+	}
+}
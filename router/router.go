@@ -1,20 +1,90 @@
 package router
 
 import (
-	"SDES/controller"
+	"SDES/auth"
+	"SDES/config"
+	"SDES/logging"
+	"SDES/metrics"
+	"slices"
 
 	"github.com/gin-gonic/gin"
 )
 
-func InitRouter(r *gin.Engine) {
+// InitRouter 按配置注册中间件与路由，cfg 为 nil 时使用默认配置
+func InitRouter(r *gin.Engine, cfg *config.Config) {
+	if cfg == nil {
+		cfg = config.Default()
+	}
+	// 只信任配置的反向代理转发的 X-Forwarded-For，默认不信任任何代理，ClientIP 即连接的对端地址
+	// 地址格式已由 config.Validate 校验
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		panic(err)
+	}
+	assets := EmbeddedAssets()
+	if cfg.StaticDir != "" {
+		assets = DiskAssets(cfg.StaticDir)
+	}
+	// 请求 ID 与访问日志，放在最前面以便后续中间件与处理函数使用带 request_id 的日志记录器
+	r.Use(logging.Middleware(), logging.Recovery(), metrics.Middleware())
+
 	// 静态文件服务
-	r.Static("/static", "./static")
+	r.GET("/static/*filepath", assets.File)
+	r.HEAD("/static/*filepath", assets.File)
 
 	// 启用CORS中间件
-	r.Use(func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
+	r.Use(cors(cfg.CORSOrigins))
+	// 可选的认证，API 密钥与令牌密钥来自配置文件
+	var authenticator *auth.Authenticator
+	if cfg.Auth.Enabled {
+		authenticator = auth.New(cfg.Auth.APIKeys, cfg.Auth.TokenSecret, cfg.Auth.Public)
+	}
+	// 文档与路由由同一份接口列表生成
+	routes := apiRoutes(cfg, authenticator)
+	doc := buildSpec(routes, authenticator)
+	// 旧 /api 接口的弃用响应头
+	legacy := successors(routes)
+	r.Use(deprecation(legacy))
+	if authenticator != nil {
+		r.Use(authenticate(authenticator, routeScopes(routes)))
+	}
+	// 限流与请求体大小限制
+	if len(cfg.RateLimits) > 0 {
+		r.Use(rateLimit(cfg.RateLimits, legacy))
+	}
+	r.Use(bodyLimit(cfg.MaxBodyBytes))
+	// 请求体按 OpenAPI 文档校验
+	r.Use(validateRequest(doc))
+	// Prometheus 指标
+	if cfg.Features.Metrics {
+		r.GET("/metrics", metrics.Handler)
+	}
+	// 主页路由
+	r.GET("/", assets.Index)
+	r.HEAD("/", assets.Index)
+
+	// API路由
+	for _, rt := range routes {
+		r.Handle(rt.Method, rt.Path, rt.handler)
+	}
+	// 接口文档
+	r.GET("/api/openapi.json", specHandler(doc))
+	r.GET("/api/docs", assets.Docs)
+}
+
+// cors 跨域中间件：允许列表包含 "*" 时允许任意来源，否则只回显列表中的来源
+func cors(origins []string) gin.HandlerFunc {
+	allowAll := slices.Contains(origins, "*")
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		switch {
+		case allowAll:
+			c.Header("Access-Control-Allow-Origin", "*")
+		case origin != "" && slices.Contains(origins, origin):
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Vary", "Origin")
+		}
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -22,17 +92,5 @@ func InitRouter(r *gin.Engine) {
 		}
 
 		c.Next()
-	})
-	// 主页路由
-	r.GET("/", func(c *gin.Context) {
-		c.File("./static/index.html")
-	})
-
-	// API路由
-	baseApi := r.Group("/api")
-	{
-		baseApi.POST("/encrypt", controller.EncryptHandler)
-		baseApi.POST("/decrypt", controller.DecryptHandler)
-		baseApi.POST("/blasting", controller.BlastingHandler)
 	}
 }
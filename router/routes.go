@@ -0,0 +1,218 @@
+package router
+
+import (
+	"SDES/auth"
+	"SDES/config"
+	"SDES/controller"
+	"SDES/dto/request"
+	"SDES/dto/response"
+	"SDES/openapi"
+	"SDES/utils/history"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// route 一个 API 接口，同一份定义既用于注册 gin 路由，也用于生成 OpenAPI 文档
+type route struct {
+	openapi.Operation
+	handler gin.HandlerFunc
+}
+
+// apiV1 当前版本接口的路径前缀
+const apiV1 = "/api/v1"
+
+// errorResponse 使用统一错误模型的接口在出错时返回的响应体
+var errorResponse = response.ErrorResponse{}
+
+// historyQuery 历史记录的过滤参数
+var historyQuery = []openapi.Param{
+	{Name: "operation", Description: "操作类型，如 encrypt、decrypt、blasting"},
+	{Name: "algorithm", Description: "算法名称"},
+	{Name: "success", Type: "boolean"},
+	{Name: "since", Description: "起始时间（RFC3339）"},
+	{Name: "until", Description: "截止时间（RFC3339）"},
+	{Name: "limit", Type: "integer"},
+	{Name: "offset", Type: "integer"},
+}
+
+// apiRoutes 按配置返回启用的 API 接口
+// 所有接口挂载在 /api/v1 下；旧的 /api 路径作为已弃用的兼容层保留，
+// 其中加解密与暴力破解的旧接口将请求转换为 v1 的结构后执行
+// authenticator 不为 nil 且配置了令牌密钥时注册签发令牌的接口
+func apiRoutes(cfg *config.Config, authenticator *auth.Authenticator) []route {
+	routes := []route{
+		{openapi.Operation{Method: "POST", Path: apiV1 + "/encrypt", Scope: auth.ScopeEncrypt, Summary: "加密", Tag: "cipher",
+			Request: request.CipherRequest{}, Response: response.CipherResponse{}, Error: errorResponse}, controller.CipherEncryptHandler},
+		// 标签不匹配时返回带 tag_valid 的 CipherResponse，错误响应与成功响应同构
+		{openapi.Operation{Method: "POST", Path: apiV1 + "/decrypt", Scope: auth.ScopeEncrypt, Summary: "解密", Tag: "cipher",
+			Request: request.CipherRequest{}, Response: response.CipherResponse{}}, controller.CipherDecryptHandler},
+	}
+	legacy := []route{
+		deprecated(route{openapi.Operation{Method: "POST", Path: "/api/encrypt", Scope: auth.ScopeEncrypt, Summary: "加密", Tag: "cipher",
+			Request: request.EncryptRequest{}, Response: response.EncryptResponse{}, Error: errorResponse}, controller.EncryptHandler}, apiV1+"/encrypt"),
+		deprecated(route{openapi.Operation{Method: "POST", Path: "/api/decrypt", Scope: auth.ScopeEncrypt, Summary: "解密", Tag: "cipher",
+			Request: request.DecryptRequest{}, Response: response.DecryptResponse{}}, controller.DecryptHandler}, apiV1+"/decrypt"),
+	}
+	if cfg.Features.Blasting {
+		routes = append(routes,
+			route{openapi.Operation{Method: "POST", Path: apiV1 + "/crack", Scope: auth.ScopeCrack, Summary: "暴力破解", Tag: "attack",
+				Request: request.CrackRequest{}, Response: response.CrackResponse{}, Error: errorResponse}, controller.CrackHandler},
+			route{openapi.Operation{Method: "POST", Path: apiV1 + "/crack/tmto", Scope: auth.ScopeCrack, Summary: "时间–存储折中演示", Tag: "attack",
+				Request: request.TMTORequest{}, Response: response.TMTOResponse{}}, controller.TMTOHandler},
+		)
+		legacy = append(legacy,
+			deprecated(route{openapi.Operation{Method: "POST", Path: "/api/blasting", Scope: auth.ScopeCrack, Summary: "暴力破解", Tag: "attack",
+				Request: request.BlastingRequest{}, Response: response.BlastingResponse{}, Error: errorResponse}, controller.BlastingHandler}, apiV1+"/crack"),
+			deprecated(route{openapi.Operation{Method: "POST", Path: "/api/blasting/tmto", Scope: auth.ScopeCrack, Summary: "时间–存储折中演示", Tag: "attack",
+				Request: request.TMTORequest{}, Response: response.TMTOResponse{}}, controller.TMTOHandler}, apiV1+"/crack/tmto"),
+		)
+	}
+
+	// 以下接口在 v1 中没有变化，同时挂载在 /api/v1 与 /api 下
+	shared := []route{
+		{openapi.Operation{Method: "GET", Path: "/algorithms", Summary: "支持的算法", Tag: "cipher",
+			Response: response.AlgorithmsResponse{}}, controller.AlgorithmsHandler},
+		{openapi.Operation{Method: "GET", Path: "/keys/random", Summary: "随机密钥与 IV", Tag: "cipher",
+			Query:    []openapi.Param{{Name: "exclude_weak", Type: "boolean", Description: "排除弱密钥与等价冗余密钥"}},
+			Response: response.RandomKeyResponse{}}, controller.RandomKeyHandler},
+		{openapi.Operation{Method: "POST", Path: "/mac", Scope: auth.ScopeEncrypt, Summary: "计算 CMAC", Tag: "mac",
+			Request: request.MACRequest{}, Response: response.MACResponse{}, Error: errorResponse}, controller.MACHandler},
+		{openapi.Operation{Method: "POST", Path: "/mac/forge", Scope: auth.ScopeCrack, Summary: "短标签伪造演示", Tag: "mac",
+			Request: request.ForgeryRequest{}, Response: response.ForgeryResponse{}, Error: errorResponse}, controller.ForgeryHandler},
+		{openapi.Operation{Method: "POST", Path: "/hash", Scope: auth.ScopeEncrypt, Summary: "玩具哈希", Tag: "hash",
+			Request: request.HashRequest{}, Response: response.HashResponse{}, Error: errorResponse}, controller.HashHandler},
+		{openapi.Operation{Method: "POST", Path: "/hash/collision", Scope: auth.ScopeCrack, Summary: "生日攻击碰撞搜索", Tag: "hash",
+			Request: request.CollisionRequest{}, Response: response.CollisionResponse{}, Error: errorResponse}, controller.CollisionHandler},
+		{openapi.Operation{Method: "POST", Path: "/prng", Scope: auth.ScopeEncrypt, Summary: "伪随机数生成与统计检验", Tag: "prng",
+			Request: request.PRNGRequest{}, Response: response.PRNGResponse{}, Error: errorResponse}, controller.PRNGHandler},
+	}
+	// 操作历史含有明密文，只在启用认证（需要 admin 作用域）或显式设置 history.public 时注册
+	if cfg.Auth.Enabled || cfg.History.Public {
+		shared = append(shared,
+			route{openapi.Operation{Method: "GET", Path: "/history", Scope: auth.ScopeAdmin, Summary: "操作历史", Tag: "history",
+				Query: historyQuery, Response: response.HistoryResponse{}, Error: errorResponse}, controller.HistoryHandler},
+			route{openapi.Operation{Method: "GET", Path: "/history/export", Scope: auth.ScopeAdmin, Summary: "导出操作历史", Tag: "history",
+				Query:    append([]openapi.Param{{Name: "format", Enum: []string{"json", "csv"}}}, historyQuery...),
+				Response: []history.Record{}, Error: errorResponse}, controller.HistoryExportHandler},
+			route{openapi.Operation{Method: "DELETE", Path: "/history", Scope: auth.ScopeAdmin, Summary: "清空操作历史", Tag: "history",
+				Response: response.HistoryResponse{}, Error: errorResponse}, controller.HistoryClearHandler},
+		)
+	}
+	if cfg.Features.Analysis {
+		shared = append(shared,
+			route{openapi.Operation{Method: "POST", Path: "/keyschedule", Scope: auth.ScopeEncrypt, Summary: "密钥扩展检查", Tag: "analysis",
+				Request: request.KeyScheduleRequest{}, Response: response.KeyScheduleResponse{}}, controller.KeyScheduleHandler},
+			route{openapi.Operation{Method: "POST", Path: "/analysis/rounds", Scope: auth.ScopeCrack, Summary: "轮数安全性比较", Tag: "analysis",
+				Request: request.RoundsAnalysisRequest{}, Response: response.RoundsAnalysisResponse{}, Error: errorResponse}, controller.RoundsAnalysisHandler},
+			route{openapi.Operation{Method: "POST", Path: "/analysis/related-keys", Scope: auth.ScopeCrack, Summary: "相关密钥与滑动攻击", Tag: "analysis",
+				Request: request.RelatedKeysRequest{}, Response: response.RelatedKeysResponse{}, Error: errorResponse}, controller.RelatedKeysHandler},
+		)
+	}
+	if cfg.Features.Oracle {
+		shared = append(shared,
+			route{openapi.Operation{Method: "POST", Path: "/oracle/session", Scope: auth.ScopeEncrypt, Summary: "创建预言机挑战", Tag: "oracle",
+				Request: request.OracleSessionRequest{}, Response: response.OracleResponse{}}, controller.OracleSessionHandler},
+			route{openapi.Operation{Method: "GET", Path: "/oracle/session/:id", Scope: auth.ScopeEncrypt, Summary: "挑战状态", Tag: "oracle",
+				Response: response.OracleResponse{}}, controller.OracleStatusHandler},
+			route{openapi.Operation{Method: "POST", Path: "/oracle/encrypt", Scope: auth.ScopeEncrypt, Summary: "加密预言机查询", Tag: "oracle",
+				Request: request.OracleQueryRequest{}, Response: response.OracleResponse{}}, controller.OracleEncryptHandler},
+			route{openapi.Operation{Method: "POST", Path: "/oracle/decrypt", Scope: auth.ScopeEncrypt, Summary: "解密预言机查询", Tag: "oracle",
+				Request: request.OracleQueryRequest{}, Response: response.OracleResponse{}}, controller.OracleDecryptHandler},
+			route{openapi.Operation{Method: "POST", Path: "/oracle/submit", Scope: auth.ScopeEncrypt, Summary: "提交猜测的密钥", Tag: "oracle",
+				Request: request.OracleSubmitRequest{}, Response: response.OracleResponse{}}, controller.OracleSubmitHandler},
+		)
+	}
+	if cfg.Features.Exercises {
+		shared = append(shared,
+			route{openapi.Operation{Method: "POST", Path: "/exercises", Scope: auth.ScopeEncrypt, Summary: "生成练习题", Tag: "exercises",
+				Request: request.ExerciseRequest{}, Response: response.ExerciseResponse{}, Error: errorResponse}, controller.ExercisesHandler},
+			route{openapi.Operation{Method: "GET", Path: "/exercises/export", Scope: auth.ScopeAdmin, Summary: "导出含答案的练习题", Tag: "exercises",
+				Query: []openapi.Param{
+					{Name: "seed", Type: "integer"},
+					{Name: "count", Type: "integer"},
+					{Name: "kinds", Description: "逗号分隔的题型"},
+				},
+				Response: response.ExerciseResponse{}, Error: errorResponse}, controller.ExerciseExportHandler},
+			route{openapi.Operation{Method: "POST", Path: "/exercises/grade", Scope: auth.ScopeEncrypt, Summary: "练习题评分", Tag: "exercises",
+				Request: request.GradeRequest{}, Response: response.GradeResponse{}, Error: errorResponse}, controller.GradeHandler},
+		)
+	}
+	if cfg.Features.Classical {
+		shared = append(shared,
+			route{openapi.Operation{Method: "POST", Path: "/classical/encrypt", Scope: auth.ScopeEncrypt, Summary: "古典密码加密", Tag: "classical",
+				Request: request.ClassicalRequest{}, Response: response.ClassicalResponse{}, Error: errorResponse}, controller.ClassicalEncryptHandler},
+			route{openapi.Operation{Method: "POST", Path: "/classical/decrypt", Scope: auth.ScopeEncrypt, Summary: "古典密码解密", Tag: "classical",
+				Request: request.ClassicalRequest{}, Response: response.ClassicalResponse{}, Error: errorResponse}, controller.ClassicalDecryptHandler},
+			route{openapi.Operation{Method: "POST", Path: "/classical/crack", Scope: auth.ScopeCrack, Summary: "古典密码唯密文分析", Tag: "classical",
+				Request: request.ClassicalCrackRequest{}, Response: response.ClassicalCrackResponse{}}, controller.ClassicalCrackHandler},
+		)
+	}
+	if authenticator != nil && authenticator.Tokens() {
+		routes = append(routes,
+			route{openapi.Operation{Method: "POST", Path: apiV1 + "/auth/token", Scope: auth.ScopeAdmin, Summary: "签发令牌", Tag: "auth",
+				Request: request.TokenRequest{}, Response: response.TokenResponse{}, Error: errorResponse},
+				controller.TokenHandler(authenticator, time.Duration(cfg.Auth.TokenTTL))},
+		)
+	}
+
+	for _, rt := range shared {
+		path := rt.Path
+		rt.Path = apiV1 + path
+		routes = append(routes, rt)
+		rt.Path = "/api" + path
+		legacy = append(legacy, deprecated(rt, apiV1+path))
+	}
+	return append(routes, legacy...)
+}
+
+// deprecated 将接口标记为已弃用，successor 为替代接口的路径
+func deprecated(rt route, successor string) route {
+	rt.Deprecated = true
+	rt.Successor = successor
+	return rt
+}
+
+// deprecatedSince 旧 /api 接口的弃用时间（2026-10-19T00:00:00Z），用于 Deprecation 响应头
+var deprecatedSince = time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+// deprecation 为已弃用的接口添加 Deprecation（RFC 9745）与指向替代接口的 Link 响应头
+// 放在限流与请求体校验之前，被拒绝的请求同样带有这两个响应头
+func deprecation(successors map[string]string) gin.HandlerFunc {
+	value := "@" + strconv.FormatInt(deprecatedSince.Unix(), 10)
+	return func(c *gin.Context) {
+		if successor, ok := successors[c.FullPath()]; ok {
+			c.Header("Deprecation", value)
+			c.Header("Link", "<"+successor+`>; rel="successor-version"`)
+		}
+		c.Next()
+	}
+}
+
+// successors 已弃用接口的路径到替代接口路径的映射
+func successors(routes []route) map[string]string {
+	m := make(map[string]string)
+	for _, rt := range routes {
+		if rt.Deprecated {
+			m[rt.Path] = rt.Successor
+		}
+	}
+	return m
+}
+
+// buildSpec 由接口列表生成 OpenAPI 文档；authenticator 不为 nil 时为需要认证的接口声明认证方式
+func buildSpec(routes []route, authenticator *auth.Authenticator) *openapi.Document {
+	doc := openapi.New(openapi.Info{
+		Title:       "S-DES Web API",
+		Version:     "1.0.0",
+		Description: "S-DES / S-AES 加解密、暴力破解与密码分析教学接口。错误提示按 Accept-Language 返回简体中文或英文。",
+	})
+	for _, rt := range routes {
+		doc.Add(rt.Operation, errorResponse)
+		if authenticator != nil && !authenticator.Allowed(nil, rt.Scope) {
+			doc.Secure(rt.Method, rt.Path, rt.Scope, errorResponse)
+		}
+	}
+	return doc
+}
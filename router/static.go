@@ -0,0 +1,91 @@
+package router
+
+import (
+	"SDES/static"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 前端静态资源
+// 默认使用编译进二进制的 static.FS，并为每个文件计算内容哈希作为 ETag；
+// 指定磁盘目录时直接读取磁盘文件且禁用缓存，修改前端后刷新即可生效。
+
+// Assets 静态资源来源
+type Assets struct {
+	fsys  fs.FS
+	etags map[string]string // 为 nil 表示从磁盘读取，不做缓存
+}
+
+// EmbeddedAssets 返回内嵌的静态资源
+func EmbeddedAssets() *Assets {
+	a := &Assets{fsys: static.FS, etags: make(map[string]string)}
+	// 内嵌文件在编译后不会变化，启动时一次性计算 ETag
+	fs.WalkDir(static.FS, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(static.FS, name)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		a.etags[name] = `"` + hex.EncodeToString(sum[:8]) + `"`
+		return nil
+	})
+	return a
+}
+
+// DiskAssets 返回从磁盘目录读取的静态资源，用于前端开发
+func DiskAssets(dir string) *Assets {
+	return &Assets{fsys: os.DirFS(dir)}
+}
+
+// serve 返回指定文件，目录或不存在的文件返回 404
+func (a *Assets) serve(c *gin.Context, name string) {
+	info, err := fs.Stat(a.fsys, name)
+	if err != nil || info.IsDir() {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	if a.etags == nil {
+		c.Header("Cache-Control", "no-store")
+	} else {
+		c.Header("ETag", a.etags[name])
+		if name == "index.html" || name == "docs.html" {
+			// 页面每次都向服务端确认，保证升级后立即引用新的资源
+			c.Header("Cache-Control", "no-cache")
+		} else {
+			c.Header("Cache-Control", "public, max-age=3600")
+		}
+	}
+	// ServeFileFS 根据 ETag 处理 If-None-Match，未变化时返回 304
+	http.ServeFileFS(c.Writer, c.Request, a.fsys, name)
+}
+
+// Index 主页
+func (a *Assets) Index(c *gin.Context) {
+	a.serve(c, "index.html")
+}
+
+// Docs 接口文档页面，由内嵌脚本读取 /api/openapi.json 渲染
+func (a *Assets) Docs(c *gin.Context) {
+	a.serve(c, "docs.html")
+}
+
+// File 处理 /static/*filepath
+func (a *Assets) File(c *gin.Context) {
+	name := path.Clean(strings.TrimPrefix(c.Param("filepath"), "/"))
+	if !fs.ValidPath(name) || name == "." {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	a.serve(c, name)
+}
@@ -0,0 +1,73 @@
+package router
+
+import (
+	"SDES/config"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestEmbeddedAssets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	InitRouter(r, config.Default())
+
+	for _, path := range []string{"/", "/static/css/style.css", "/static/js/app.js"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		etag := w.Header().Get("ETag")
+		if w.Code != http.StatusOK || w.Body.Len() == 0 || etag == "" {
+			t.Fatalf("%s: status %d, %d bytes, etag %q", path, w.Code, w.Body.Len(), etag)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("If-None-Match", etag)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusNotModified {
+			t.Errorf("%s: 携带 ETag 再次请求应返回 304，实际 %d", path, w.Code)
+		}
+	}
+
+	for _, path := range []string{"/static/css", "/static/missing.js", "/static/../main.go"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s: 应返回 404，实际 %d", path, w.Code)
+		}
+	}
+}
+
+// 接口文档页面只引用内嵌资源，离线环境也能打开
+func TestDocsPageSelfContained(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	InitRouter(r, config.Default())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/docs", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("/api/docs: status %d", w.Code)
+	}
+	page := w.Body.String()
+	refs := regexp.MustCompile(`(?:src|href)="([^"]+)"`).FindAllStringSubmatch(page, -1)
+	if len(refs) == 0 {
+		t.Fatal("文档页面没有引用脚本或样式")
+	}
+	for _, m := range refs {
+		ref := m[1]
+		if !strings.HasPrefix(ref, "/") || strings.HasPrefix(ref, "//") {
+			t.Errorf("文档页面引用了外部资源 %s", ref)
+			continue
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, ref, nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: status %d", ref, w.Code)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Package server 带超时设置与优雅关闭的 HTTP 服务器
+package server
+
+import (
+	"SDES/config"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Server 包装 http.Server
+// ctx 取消后停止接受新连接，等待处理中的请求在 ShutdownTimeout 内完成；
+// 通过 OnShutdown 注册的函数在开始关闭时立即调用，用于取消暴力破解等耗时任务。
+type Server struct {
+	cfg  *config.Config
+	http *http.Server
+}
+
+// New 按配置创建服务器
+func New(cfg *config.Config, handler http.Handler) *Server {
+	return &Server{
+		cfg: cfg,
+		http: &http.Server{
+			Addr:              cfg.Addr,
+			Handler:           handler,
+			ReadTimeout:       time.Duration(cfg.ReadTimeout),
+			ReadHeaderTimeout: time.Duration(cfg.ReadTimeout),
+			WriteTimeout:      time.Duration(cfg.WriteTimeout),
+			IdleTimeout:       time.Duration(cfg.IdleTimeout),
+			ErrorLog:          slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn),
+		},
+	}
+}
+
+// OnShutdown 注册开始关闭时调用的函数
+func (s *Server) OnShutdown(f func()) {
+	s.http.RegisterOnShutdown(f)
+}
+
+// Run 监听配置中的地址并提供服务，直到 ctx 取消或出错
+func (s *Server) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("监听 %s 失败: %w", s.cfg.Addr, err)
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve 在 ln 上提供服务，直到 ctx 取消或出错；正常关闭时返回 nil
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	errCh := make(chan error, 1)
+	go func() {
+		var err error
+		if s.cfg.TLS() {
+			err = s.http.ServeTLS(ln, s.cfg.TLSCert, s.cfg.TLSKey)
+		} else {
+			err = s.http.Serve(ln)
+		}
+		errCh <- err
+	}()
+
+	select {
+	case err := <-errCh:
+		// 未经 Shutdown 就退出，例如证书无法加载
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("正在关闭服务器，等待处理中的请求完成", "timeout", s.cfg.ShutdownTimeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(s.cfg.ShutdownTimeout))
+	defer cancel()
+	if err := s.http.Shutdown(shutdownCtx); err != nil {
+		s.http.Close()
+		return fmt.Errorf("关闭服务器超时: %w", err)
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	slog.Info("服务器已关闭")
+	return nil
+}
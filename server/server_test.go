@@ -0,0 +1,103 @@
+package server
+
+import (
+	"SDES/config"
+	"SDES/router"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestServeAndShutdown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := config.Default()
+	cfg.History.Enabled = false
+	cfg.ShutdownTimeout = config.Duration(5 * time.Second)
+
+	r := gin.New()
+	router.InitRouter(r, cfg)
+	// 模拟耗时请求，用于验证关闭时会等待其完成
+	started := make(chan struct{})
+	r.GET("/slow", func(c *gin.Context) {
+		close(started)
+		time.Sleep(300 * time.Millisecond)
+		c.String(http.StatusOK, "done")
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := "http://" + ln.Addr().String()
+
+	srv := New(cfg, r)
+	var hooked atomic.Bool
+	srv.OnShutdown(func() { hooked.Store(true) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ctx, ln) }()
+
+	resp, err := http.Post(base+"/api/encrypt", "application/json", strings.NewReader(`{"plaintext":"10101010","key":"1010000010"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), `"success":true`) {
+		t.Fatalf("加密请求失败: %d %s", resp.StatusCode, body)
+	}
+
+	slow := make(chan string, 1)
+	go func() {
+		resp, err := http.Get(base + "/slow")
+		if err != nil {
+			slow <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		slow <- string(b)
+	}()
+	<-started
+	cancel()
+
+	if got := <-slow; got != "done" {
+		t.Errorf("关闭时处理中的请求应正常完成，实际 %q", got)
+	}
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("正常关闭应返回 nil，实际 %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("服务器未在超时内关闭")
+	}
+	if !hooked.Load() {
+		t.Error("关闭时应调用 OnShutdown 注册的函数")
+	}
+	if _, err := http.Get(base + "/api/algorithms"); err == nil {
+		t.Error("关闭后不应再接受连接")
+	}
+}
+
+func TestRunListenError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	cfg := config.Default()
+	cfg.Addr = ln.Addr().String()
+	if err := New(cfg, http.NotFoundHandler()).Run(context.Background()); err == nil {
+		t.Fatal("端口被占用时应返回错误")
+	}
+}
@@ -1,3 +1,37 @@
+// 支持的算法参数
+const ALGORITHMS = {
+    sdes: { name: 'S-DES', blockBits: 8, keyBits: 10 },
+    saes: { name: 'S-AES', blockBits: 16, keyBits: 16 },
+};
+
+function currentAlgorithm() {
+    const checked = document.querySelector('input[name="algorithm"]:checked');
+    const id = checked ? checked.value : 'sdes';
+    return { id, ...ALGORITHMS[id] };
+}
+
+// 切换算法时更新输入框长度与标签
+function applyAlgorithm() {
+    const algo = currentAlgorithm();
+    const update = (id, bits, text) => {
+        const input = document.getElementById(id);
+        if (!input) return;
+        input.maxLength = bits;
+        input.pattern = `[01]{${bits}}`;
+        input.value = '';
+        const label = document.querySelector(`label[for="${id}"]`);
+        if (label) label.textContent = `${text} (${bits}位二进制):`;
+    };
+    update('plaintext', algo.blockBits, '明文');
+    update('ciphertext', algo.blockBits, '密文');
+    update('brutePlaintext', algo.blockBits, '明文');
+    update('bruteCiphertext', algo.blockBits, '密文');
+    update('encryptKey', algo.keyBits, '密钥');
+    update('decryptKey', algo.keyBits, '密钥');
+    const subtitle = document.querySelector('.header p');
+    if (subtitle) subtitle.textContent = `${algo.name} - ${algo.blockBits}位分组，${algo.keyBits}位密钥`;
+}
+
 // 验证二进制输入
 function validateBinaryInput(value, length) {
     if (value.length !== length) {
@@ -28,7 +62,8 @@ function toggleInputMode(formId, mode) {
         binaryInput.style.display = 'block';
         binaryInput.value = '';
         binaryInput.focus();
-        label.textContent = isEncrypt ? '明文 (8位二进制):' : '密文 (8位二进制):';
+        const bits = currentAlgorithm().blockBits;
+        label.textContent = isEncrypt ? `明文 (${bits}位二进制):` : `密文 (${bits}位二进制):`;
     }
 }
 
@@ -198,14 +233,15 @@ function bindEncryptForm() {
         const key = document.getElementById('encryptKey');
         const mode = document.querySelector('input[name="encryptMode"]:checked').value;
 
+        const algo = currentAlgorithm();
         const keyValue = key.value.trim();
-        const keyError = validateBinaryInput(keyValue, 10);
+        const keyError = validateBinaryInput(keyValue, algo.keyBits);
         if (keyError) {
             showResult('encryptResult', `密钥错误: ${keyError}`, false);
             return;
         }
 
-        const payload = { key: keyValue };
+        const payload = { key: keyValue, algorithm: algo.id };
 
         if (mode === 'ascii') {
             const asciiValue = plaintextASCII.value;
@@ -216,7 +252,7 @@ function bindEncryptForm() {
             payload.plaintext_ascii = asciiValue;
         } else {
             const binaryValue = plaintext.value.trim();
-            const plaintextError = validateBinaryInput(binaryValue, 8);
+            const plaintextError = validateBinaryInput(binaryValue, algo.blockBits);
             if (plaintextError) {
                 showResult('encryptResult', `明文错误: ${plaintextError}`, false);
                 return;
@@ -265,14 +301,15 @@ function bindDecryptForm() {
         const key = document.getElementById('decryptKey');
         const mode = document.querySelector('input[name="decryptMode"]:checked').value;
 
+        const algo = currentAlgorithm();
         const keyValue = key.value.trim();
-        const keyError = validateBinaryInput(keyValue, 10);
+        const keyError = validateBinaryInput(keyValue, algo.keyBits);
         if (keyError) {
             showResult('decryptResult', `密钥错误: ${keyError}`, false);
             return;
         }
 
-        const payload = { key: keyValue };
+        const payload = { key: keyValue, algorithm: algo.id };
 
         if (mode === 'ascii') {
             const base64Value = ciphertextBase64.value.trim();
@@ -283,7 +320,7 @@ function bindDecryptForm() {
             payload.ciphertext_base64 = base64Value;
         } else {
             const binaryValue = ciphertext.value.trim();
-            const ciphertextError = validateBinaryInput(binaryValue, 8);
+            const ciphertextError = validateBinaryInput(binaryValue, algo.blockBits);
             if (ciphertextError) {
                 showResult('decryptResult', `密文错误: ${ciphertextError}`, false);
                 return;
@@ -333,13 +370,14 @@ function bindBruteForceForm() {
         const ciphertextValue = ciphertext.value.trim();
 
         // 验证输入
-        const plaintextError = validateBinaryInput(plaintextValue, 8);
+        const algo = currentAlgorithm();
+        const plaintextError = validateBinaryInput(plaintextValue, algo.blockBits);
         if (plaintextError) {
             showResult('bruteForceResult', `明文错误: ${plaintextError}`, false);
             return;
         }
 
-        const ciphertextError = validateBinaryInput(ciphertextValue, 8);
+        const ciphertextError = validateBinaryInput(ciphertextValue, algo.blockBits);
         if (ciphertextError) {
             showResult('bruteForceResult', `密文错误: ${ciphertextError}`, false);
             return;
@@ -347,7 +385,8 @@ function bindBruteForceForm() {
 
         const payload = {
             plaintext: plaintextValue,
-            ciphertext: ciphertextValue
+            ciphertext: ciphertextValue,
+            algorithm: algo.id
         };
 
         showLoading('bruteForceResult');
@@ -396,6 +435,11 @@ document.addEventListener('DOMContentLoaded', () => {
     bindBruteForceForm();
     bindBinaryInputSanitizer();
     
+    // 绑定算法切换事件
+    document.querySelectorAll('input[name="algorithm"]').forEach(radio => {
+        radio.addEventListener('change', applyAlgorithm);
+    });
+
     // 绑定模式切换事件
     document.querySelectorAll('input[name="encryptMode"]').forEach(radio => {
         radio.addEventListener('change', (event) => {
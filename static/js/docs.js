@@ -0,0 +1,234 @@
+// 接口文档页面：读取 /api/openapi.json 并在本地渲染，不依赖外部脚本
+
+const SPEC_URL = '/api/openapi.json';
+const METHOD_ORDER = ['get', 'post', 'put', 'patch', 'delete'];
+
+// el 创建元素，children 为字符串时作为文本插入，避免拼接 HTML
+function el(tag, attrs, ...children) {
+    const node = document.createElement(tag);
+    for (const [name, value] of Object.entries(attrs || {})) {
+        if (name === 'class') node.className = value;
+        else if (name.startsWith('on')) node.addEventListener(name.slice(2), value);
+        else node.setAttribute(name, value);
+    }
+    for (const child of children) {
+        if (child === null || child === undefined || child === false) continue;
+        node.append(typeof child === 'string' ? document.createTextNode(child) : child);
+    }
+    return node;
+}
+
+// resolve 展开 #/components/schemas/ 引用
+function resolve(spec, schema) {
+    if (schema && schema.$ref) {
+        const name = schema.$ref.replace('#/components/schemas/', '');
+        return { name, ...spec.components.schemas[name] };
+    }
+    return schema || {};
+}
+
+// typeName 类型的简短描述，如 string、integer[]、MACResponse
+function typeName(spec, schema) {
+    if (schema.$ref) return schema.$ref.replace('#/components/schemas/', '');
+    if (schema.oneOf) return schema.oneOf.map(s => typeName(spec, s)).join(' | ');
+    if (schema.type === 'array') return typeName(spec, schema.items || {}) + '[]';
+    if (schema.type === 'object' && schema.additionalProperties) {
+        return 'map<string, ' + typeName(spec, schema.additionalProperties) + '>';
+    }
+    return (schema.type || 'any') + (schema.format ? ` (${schema.format})` : '');
+}
+
+// constraints 取值范围等约束的文字描述
+function constraints(schema) {
+    const parts = [];
+    if (schema.enum) parts.push('可选值：' + schema.enum.join(', '));
+    if (schema.minimum !== undefined) parts.push('最小 ' + schema.minimum);
+    if (schema.maximum !== undefined) parts.push('最大 ' + schema.maximum);
+    if (schema.minLength !== undefined) parts.push('最短 ' + schema.minLength);
+    if (schema.maxLength !== undefined) parts.push('最长 ' + schema.maxLength);
+    if (schema.minItems !== undefined) parts.push('至少 ' + schema.minItems + ' 项');
+    if (schema.maxItems !== undefined) parts.push('至多 ' + schema.maxItems + ' 项');
+    if (schema.nullable) parts.push('可为 null');
+    return parts.join('；');
+}
+
+// schemaTable 以表格列出对象的字段，嵌套对象缩进展开，seen 防止循环引用
+function schemaTable(spec, schema, seen = new Set()) {
+    const rows = [];
+    const walk = (s, depth) => {
+        s = resolve(spec, s);
+        if (s.type === 'array') s = resolve(spec, s.items);
+        if (!s.properties || (s.name && seen.has(s.name) && depth > 0)) return;
+        if (s.name) seen.add(s.name);
+        const required = new Set(s.required || []);
+        for (const [name, prop] of Object.entries(s.properties)) {
+            rows.push(el('tr', {},
+                el('td', { class: 'field', style: `padding-left:${8 + depth * 16}px` }, name),
+                el('td', {}, typeName(spec, prop)),
+                el('td', {}, required.has(name) ? '是' : ''),
+                el('td', {}, [prop.description, constraints(prop)].filter(Boolean).join('；'))));
+            walk(prop, depth + 1);
+        }
+        if (s.name) seen.delete(s.name);
+    };
+    walk(schema, 0);
+    if (rows.length === 0) return el('p', { class: 'muted' }, typeName(spec, schema));
+    return el('table', {},
+        el('thead', {}, el('tr', {}, el('th', {}, '字段'), el('th', {}, '类型'), el('th', {}, '必填'), el('th', {}, '说明'))),
+        el('tbody', {}, ...rows));
+}
+
+// example 按结构生成示例请求体
+function example(spec, schema, depth = 0) {
+    schema = resolve(spec, schema);
+    if (depth > 4) return null;
+    if (schema.enum) return schema.enum[0];
+    switch (schema.type) {
+        case 'object': {
+            const obj = {};
+            for (const [name, prop] of Object.entries(schema.properties || {})) {
+                obj[name] = example(spec, prop, depth + 1);
+            }
+            return obj;
+        }
+        case 'array': return [];
+        case 'integer':
+        case 'number': return schema.minimum !== undefined ? schema.minimum : 0;
+        case 'boolean': return false;
+        default: return '';
+    }
+}
+
+function jsonSchema(content) {
+    const media = content && content['application/json'];
+    return media && media.schema;
+}
+
+// tryPanel 直接在页面上发送请求
+function tryPanel(spec, method, path, op) {
+    const inputs = {};
+    const fields = [];
+    for (const p of op.parameters || []) {
+        inputs[p.name] = el('input', { type: 'text', placeholder: p.in === 'path' ? '必填' : '' });
+        fields.push(el('label', {}, `${p.name}（${p.in === 'path' ? '路径' : '查询'}参数）`, inputs[p.name]));
+    }
+    const schema = op.requestBody && jsonSchema(op.requestBody.content);
+    const body = schema ? el('textarea', { rows: 6, spellcheck: 'false' }) : null;
+    if (body) body.value = JSON.stringify(example(spec, schema), null, 2);
+    const output = el('pre', { class: 'output' });
+
+    const send = async () => {
+        let url = path;
+        const query = new URLSearchParams();
+        for (const p of op.parameters || []) {
+            const value = inputs[p.name].value;
+            if (p.in === 'path') url = url.replace(`{${p.name}}`, encodeURIComponent(value));
+            else if (value !== '') query.set(p.name, value);
+        }
+        if (query.toString()) url += '?' + query;
+        const headers = {};
+        const token = document.getElementById('docs-token');
+        if (token && token.value) {
+            if (document.getElementById('docs-scheme').value === 'bearer') headers.Authorization = 'Bearer ' + token.value;
+            else headers['X-API-Key'] = token.value;
+        }
+        if (body) headers['Content-Type'] = 'application/json';
+        output.textContent = '请求中……';
+        try {
+            const res = await fetch(url, { method: method.toUpperCase(), headers, body: body ? body.value : undefined });
+            const text = await res.text();
+            let pretty = text;
+            try { pretty = JSON.stringify(JSON.parse(text), null, 2); } catch (e) { /* 非 JSON 响应原样显示 */ }
+            output.textContent = `${res.status} ${res.statusText}\n\n${pretty}`;
+        } catch (e) {
+            output.textContent = '请求失败：' + e.message;
+        }
+    };
+
+    return el('details', { class: 'try' },
+        el('summary', {}, '试一试'),
+        ...fields,
+        body && el('label', {}, '请求体', body),
+        el('button', { type: 'button', onclick: send }, '发送'),
+        output);
+}
+
+function renderOperation(spec, method, path, op) {
+    const body = [];
+    if (op.description) body.push(el('p', {}, op.description));
+    if (op.security) body.push(el('p', { class: 'muted' }, '需要认证：X-API-Key 请求头或 Bearer 令牌'));
+    if (op.parameters && op.parameters.length) {
+        body.push(el('h4', {}, '参数'), el('table', {},
+            el('thead', {}, el('tr', {}, el('th', {}, '名称'), el('th', {}, '位置'), el('th', {}, '类型'), el('th', {}, '说明'))),
+            el('tbody', {}, ...op.parameters.map(p => el('tr', {},
+                el('td', { class: 'field' }, p.name),
+                el('td', {}, p.in),
+                el('td', {}, typeName(spec, p.schema || {})),
+                el('td', {}, [p.description, constraints(p.schema || {})].filter(Boolean).join('；')))))));
+    }
+    const request = op.requestBody && jsonSchema(op.requestBody.content);
+    if (request) body.push(el('h4', {}, '请求体'), schemaTable(spec, request));
+    body.push(el('h4', {}, '响应'));
+    for (const status of Object.keys(op.responses).sort()) {
+        const res = op.responses[status];
+        const schema = jsonSchema(res.content);
+        body.push(el('details', { class: 'response' },
+            el('summary', {}, el('code', {}, status), ' ', res.description, schema ? '：' + typeName(spec, schema) : ''),
+            schema ? schemaTable(spec, schema) : null));
+    }
+    body.push(tryPanel(spec, method, path, op));
+
+    return el('details', { class: 'operation' + (op.deprecated ? ' deprecated' : '') },
+        el('summary', {},
+            el('span', { class: 'method method-' + method }, method.toUpperCase()),
+            el('code', { class: 'path' }, path),
+            el('span', { class: 'summary' }, op.summary || ''),
+            op.deprecated ? el('span', { class: 'badge' }, '已弃用') : null),
+        ...body);
+}
+
+function render(spec) {
+    document.title = spec.info.title;
+    document.getElementById('docs-title').textContent = `${spec.info.title} ${spec.info.version}`;
+    document.getElementById('docs-description').textContent = spec.info.description || '';
+
+    // 按标签分组，未弃用的接口排在前面
+    const groups = new Map();
+    for (const path of Object.keys(spec.paths).sort()) {
+        const item = spec.paths[path];
+        for (const method of METHOD_ORDER) {
+            const op = item[method];
+            if (!op) continue;
+            const tag = (op.tags && op.tags[0]) || '其他';
+            if (!groups.has(tag)) groups.set(tag, []);
+            groups.get(tag).push({ method, path, op });
+        }
+    }
+
+    const content = document.getElementById('docs-content');
+    content.replaceChildren();
+    if (spec.components.securitySchemes) {
+        content.append(el('div', { class: 'token' },
+            el('select', { id: 'docs-scheme' },
+                el('option', { value: 'apiKey' }, 'X-API-Key'),
+                el('option', { value: 'bearer' }, 'Bearer 令牌')),
+            el('input', { id: 'docs-token', type: 'password', autocomplete: 'off', placeholder: '凭据' })));
+    }
+    for (const [tag, ops] of groups) {
+        ops.sort((a, b) => Number(a.op.deprecated || false) - Number(b.op.deprecated || false));
+        content.append(el('section', {}, el('h2', {}, tag),
+            ...ops.map(({ method, path, op }) => renderOperation(spec, method, path, op))));
+    }
+}
+
+window.addEventListener('load', async () => {
+    try {
+        const res = await fetch(SPEC_URL);
+        if (!res.ok) throw new Error(`${res.status} ${res.statusText}`);
+        render(await res.json());
+    } catch (e) {
+        const content = document.getElementById('docs-content');
+        content.replaceChildren(el('p', {}, '接口文档加载失败：' + e.message + '。可直接查看 ',
+            el('a', { href: SPEC_URL }, SPEC_URL), '。'));
+    }
+});
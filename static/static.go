@@ -0,0 +1,9 @@
+// Package static 内嵌前端页面与资源，使服务端不依赖工作目录中的 static 文件夹
+package static
+
+import "embed"
+
+// FS 内嵌的 index.html、docs.html 及其引用的 css 与 js 文件
+//
+//go:embed index.html docs.html css js
+var FS embed.FS
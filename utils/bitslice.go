@@ -0,0 +1,204 @@
+package utils
+
+import "math/bits"
+
+// 位切片（bitslice）S-DES
+// 将 64 个独立实例的同一位打包进一个 uint64，第 j 个通道（lane）即第 j 位，
+// 置换只是重排下标，S 盒写成布尔电路，一次按位运算同时完成 64 个实例的计算。
+// 64 个实例可以是 64 个密钥（暴力破解）或 64 个明文（密码本计算）。
+
+// BitsliceLanes 每批并行计算的实例数
+const BitsliceLanes = 64
+
+// SlicedBlock 位切片形式的 8 位分组，下标与位数组一致（0 为最高位）
+type SlicedBlock [8]uint64
+
+// SlicedKey 位切片形式的 10 位密钥
+type SlicedKey [10]uint64
+
+// Bitsliced 位切片 S-DES，轮数与密钥扩展与 NewSDESFeistel 的变体一致
+type Bitsliced struct {
+	Rounds int
+	// subkeys 第 i 轮轮密钥各位在主密钥中的下标
+	subkeys [][8]int
+}
+
+// NewBitsliced 构造指定轮数的位切片 S-DES
+func NewBitsliced(rounds int) *Bitsliced {
+	// 密钥扩展只移动比特而不做运算，以下标作为"密钥"运行一遍即可得到每个轮密钥位的来源
+	labels := make([]int, 10)
+	for i := range labels {
+		labels[i] = i
+	}
+	b := &Bitsliced{Rounds: rounds}
+	for _, k := range SDESKeySchedule(labels, rounds, nil) {
+		b.subkeys = append(b.subkeys, [8]int(k))
+	}
+	return b
+}
+
+// sliceValues 将 64 个整数转置为位切片形式，width 为位宽
+func sliceValues(width int, value func(lane int) int, out []uint64) {
+	for lane := 0; lane < BitsliceLanes; lane++ {
+		v := value(lane)
+		for i := 0; i < width; i++ {
+			out[i] |= uint64(v>>(width-1-i)&1) << lane
+		}
+	}
+}
+
+// SliceKeys 第 j 个通道为密钥 base+j
+func SliceKeys(base int) SlicedKey {
+	var k SlicedKey
+	sliceValues(10, func(lane int) int { return base + lane }, k[:])
+	return k
+}
+
+// BroadcastKey 所有通道使用同一密钥
+func BroadcastKey(key int) SlicedKey {
+	var k SlicedKey
+	for i := range k {
+		k[i] = -uint64(key >> (9 - i) & 1)
+	}
+	return k
+}
+
+// SliceBlocks 第 j 个通道为分组 (base+j) mod 256
+func SliceBlocks(base int) SlicedBlock {
+	var s SlicedBlock
+	sliceValues(8, func(lane int) int { return (base + lane) & 0xFF }, s[:])
+	return s
+}
+
+// BroadcastBlock 所有通道使用同一分组
+func BroadcastBlock(b byte) SlicedBlock {
+	var s SlicedBlock
+	for i := range s {
+		s[i] = -uint64(b >> (7 - i) & 1)
+	}
+	return s
+}
+
+// Lane 取出第 j 个通道的分组
+func (s *SlicedBlock) Lane(j int) byte {
+	var b byte
+	for i := range s {
+		b = b<<1 | byte(s[i]>>j&1)
+	}
+	return b
+}
+
+// Match 返回分组等于 b 的通道掩码
+func (s *SlicedBlock) Match(b byte) uint64 {
+	mask := ^uint64(0)
+	for i := range s {
+		mask &^= s[i] ^ -uint64(b>>(7-i)&1)
+	}
+	return mask
+}
+
+// sbox0 S 盒 S0 的布尔电路，a、b、c、d 为输入的 4 位（行 = ad，列 = bc）
+func sbox0(a, b, c, d uint64) (hi, lo uint64) {
+	hi = b ^ d ^ (a & (c ^ (b &^ d)))
+	lo = ^(c ^ (a &^ (b ^ c ^ d)))
+	return
+}
+
+// sbox1 S 盒 S1 的布尔电路
+func sbox1(a, b, c, d uint64) (hi, lo uint64) {
+	hi = b ^ d ^ (a &^ (c ^ d))
+	lo = c ^ (b & d) ^ (a &^ (d &^ b))
+	return
+}
+
+// fSliced 位切片形式的 F 函数：EP、异或轮密钥、S 盒、P4
+func fSliced(right [4]uint64, key *SlicedKey, subkey *[8]int) [4]uint64 {
+	var x [8]uint64
+	for i, pos := range EP {
+		x[i] = right[pos-1] ^ key[subkey[i]]
+	}
+	var s [4]uint64
+	s[0], s[1] = sbox0(x[0], x[1], x[2], x[3])
+	s[2], s[3] = sbox1(x[4], x[5], x[6], x[7])
+
+	var p4 [4]uint64
+	for i, pos := range SPBox {
+		p4[i] = s[pos-1]
+	}
+	return p4
+}
+
+// process 首置换、各轮运算（最后一轮不交换）与尾置换，reverse 为 true 时轮密钥逆序
+func (b *Bitsliced) process(in SlicedBlock, key *SlicedKey, reverse bool) SlicedBlock {
+	var left, right [4]uint64
+	for i, pos := range IP {
+		if i < 4 {
+			left[i] = in[pos-1]
+		} else {
+			right[i-4] = in[pos-1]
+		}
+	}
+
+	for r := 0; r < b.Rounds; r++ {
+		idx := r
+		if reverse {
+			idx = b.Rounds - 1 - r
+		}
+		f := fSliced(right, key, &b.subkeys[idx])
+		var newLeft [4]uint64
+		for i := range newLeft {
+			newLeft[i] = left[i] ^ f[i]
+		}
+		if r == b.Rounds-1 {
+			left = newLeft
+			break
+		}
+		left, right = right, newLeft
+	}
+
+	var out SlicedBlock
+	for i, pos := range IPInverse {
+		if pos <= 4 {
+			out[i] = left[pos-1]
+		} else {
+			out[i] = right[pos-5]
+		}
+	}
+	return out
+}
+
+// Encrypt 并行加密 64 个实例
+func (b *Bitsliced) Encrypt(plaintext SlicedBlock, key SlicedKey) SlicedBlock {
+	return b.process(plaintext, &key, false)
+}
+
+// Decrypt 并行解密 64 个实例
+func (b *Bitsliced) Decrypt(ciphertext SlicedBlock, key SlicedKey) SlicedBlock {
+	return b.process(ciphertext, &key, true)
+}
+
+// BruteForce 每批 64 个密钥穷举密钥空间，返回所有满足 E_k(plaintext) = ciphertext 的密钥
+func (b *Bitsliced) BruteForce(plaintext, ciphertext byte) []int {
+	var keys []int
+	pt := BroadcastBlock(plaintext)
+	for base := 0; base < KeySpace; base += BitsliceLanes {
+		ct := b.Encrypt(pt, SliceKeys(base))
+		for mask := ct.Match(ciphertext); mask != 0; mask &= mask - 1 {
+			keys = append(keys, base+bits.TrailingZeros64(mask))
+		}
+	}
+	return keys
+}
+
+// Codebook 每批 64 个明文计算单个密钥下的完整密码本
+func (b *Bitsliced) Codebook(key int) [BlockSpace]byte {
+	var table [BlockSpace]byte
+	k := BroadcastKey(key)
+	for base := 0; base < BlockSpace; base += BitsliceLanes {
+		ct := b.Encrypt(SliceBlocks(base), k)
+		for j := 0; j < BitsliceLanes; j++ {
+			table[base+j] = ct.Lane(j)
+		}
+	}
+	return table
+}
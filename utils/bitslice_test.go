@@ -0,0 +1,77 @@
+package utils
+
+import "testing"
+
+// 位切片实现必须与参考实现 Encrypt/Decrypt 在整个密钥与明文空间上一致
+func TestBitslicedMatchesReference(t *testing.T) {
+	b := NewBitsliced(2)
+	for base := 0; base < KeySpace; base += BitsliceLanes {
+		keys := SliceKeys(base)
+		for p := 0; p < BlockSpace; p++ {
+			ct := b.Encrypt(BroadcastBlock(byte(p)), keys)
+			back := b.Decrypt(ct, keys)
+			for j := 0; j < BitsliceLanes; j++ {
+				key := IntTo10BitKey(base + j)
+				want := BitsToByte(Encrypt(ByteToBits(byte(p)), key))
+				if got := ct.Lane(j); got != want {
+					t.Fatalf("key=%d plaintext=%d: got %08b, want %08b", base+j, p, got, want)
+				}
+				if got := back.Lane(j); got != byte(p) {
+					t.Fatalf("key=%d plaintext=%d: decrypt got %08b", base+j, p, got)
+				}
+			}
+		}
+	}
+}
+
+func TestBitslicedVariants(t *testing.T) {
+	for _, rounds := range []int{1, 4, 16} {
+		f, err := NewSDESFeistel(rounds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		const key = 0b1010000010
+		table := f.bitsliced.Codebook(key)
+		for p := 0; p < BlockSpace; p++ {
+			if want := BitsToByte(f.Encrypt(ByteToBits(byte(p)), IntTo10BitKey(key))); table[p] != want {
+				t.Fatalf("rounds=%d plaintext=%d: got %08b, want %08b", rounds, p, table[p], want)
+			}
+		}
+	}
+}
+
+func TestBitslicedBruteForce(t *testing.T) {
+	const plaintext = 0b01110010
+	ciphertext := SDES.EncryptByte(plaintext, IntTo10BitKey(0b1010000010))
+	var want []int
+	for k := 0; k < KeySpace; k++ {
+		if BitsToByte(Encrypt(ByteToBits(plaintext), IntTo10BitKey(k))) == ciphertext {
+			want = append(want, k)
+		}
+	}
+	got := SDES.BruteForce(plaintext, ciphertext)
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func BenchmarkBruteForceReference(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		pt := ByteToBits(byte(i))
+		for k := 0; k < KeySpace; k++ {
+			Encrypt(pt, IntTo10BitKey(k))
+		}
+	}
+}
+
+func BenchmarkBruteForceBitsliced(b *testing.B) {
+	bs := NewBitsliced(2)
+	for i := 0; i < b.N; i++ {
+		bs.BruteForce(byte(i), 0)
+	}
+}
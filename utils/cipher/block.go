@@ -0,0 +1,72 @@
+package cipher
+
+import (
+	"SDES/utils"
+	"fmt"
+)
+
+// Spec 以函数组合描述一个分组密码，由 New 包装为 Cipher
+type Spec struct {
+	Name         string
+	DisplayName  string
+	BlockBits    int
+	KeyBits      int
+	Encrypt      func(plaintext []int, key []int) []int
+	Decrypt      func(ciphertext []int, key []int) []int
+	EncryptTrace func(plaintext []int, key []int) ([]int, []utils.TraceStep)
+	DecryptTrace func(ciphertext []int, key []int) ([]int, []utils.TraceStep)
+	EncryptBytes func(plaintext []byte, key []int) []byte
+	DecryptBytes func(ciphertext []byte, key []int) ([]byte, error)
+}
+
+type blockCipher struct {
+	spec Spec
+}
+
+// New 由 Spec 构造 Cipher
+func New(spec Spec) Cipher {
+	return &blockCipher{spec: spec}
+}
+
+func (b *blockCipher) Name() string        { return b.spec.Name }
+func (b *blockCipher) DisplayName() string { return b.spec.DisplayName }
+func (b *blockCipher) BlockBits() int      { return b.spec.BlockBits }
+func (b *blockCipher) KeyBits() int        { return b.spec.KeyBits }
+
+func (b *blockCipher) Encrypt(plaintext []int, key []int) []int {
+	return b.spec.Encrypt(plaintext, key)
+}
+
+func (b *blockCipher) Decrypt(ciphertext []int, key []int) []int {
+	return b.spec.Decrypt(ciphertext, key)
+}
+
+func (b *blockCipher) EncryptTrace(plaintext []int, key []int) ([]int, []utils.TraceStep) {
+	return b.spec.EncryptTrace(plaintext, key)
+}
+
+func (b *blockCipher) DecryptTrace(ciphertext []int, key []int) ([]int, []utils.TraceStep) {
+	return b.spec.DecryptTrace(ciphertext, key)
+}
+
+func (b *blockCipher) EncryptBytes(plaintext []byte, key []int) []byte {
+	return b.spec.EncryptBytes(plaintext, key)
+}
+
+func (b *blockCipher) DecryptBytes(ciphertext []byte, key []int) ([]byte, error) {
+	return b.spec.DecryptBytes(ciphertext, key)
+}
+
+func (b *blockCipher) ParseKey(key string) ([]int, error) {
+	if !utils.IsValidBinary(key, b.spec.KeyBits) {
+		return nil, fmt.Errorf("密钥必须是%d位二进制字符串（只包含0和1）", b.spec.KeyBits)
+	}
+	return utils.StringToBits(key, b.spec.KeyBits), nil
+}
+
+func (b *blockCipher) ParseBlock(block string) ([]int, error) {
+	if !utils.IsValidBinary(block, b.spec.BlockBits) {
+		return nil, fmt.Errorf("分组必须是%d位二进制字符串（只包含0和1）", b.spec.BlockBits)
+	}
+	return utils.StringToBits(block, b.spec.BlockBits), nil
+}
@@ -0,0 +1,76 @@
+package cipher
+
+import (
+	"SDES/utils"
+	"SDES/utils/saes"
+)
+
+// 内置算法
+func init() {
+	Register(sdesCipher{New(Spec{
+		Name:         "sdes",
+		DisplayName:  "S-DES",
+		BlockBits:    8,
+		KeyBits:      10,
+		Encrypt:      utils.Encrypt,
+		Decrypt:      utils.Decrypt,
+		EncryptTrace: utils.EncryptTrace,
+		DecryptTrace: utils.DecryptTrace,
+		EncryptBytes: utils.EncryptBytes,
+		DecryptBytes: func(ciphertext []byte, key []int) ([]byte, error) {
+			return utils.DecryptBytes(ciphertext, key), nil
+		},
+	})})
+
+	Register(New(Spec{
+		Name:         "saes",
+		DisplayName:  "S-AES",
+		BlockBits:    saes.BlockBits,
+		KeyBits:      saes.KeyBits,
+		Encrypt:      saes.Encrypt,
+		Decrypt:      saes.Decrypt,
+		EncryptTrace: saes.EncryptTrace,
+		DecryptTrace: saes.DecryptTrace,
+		EncryptBytes: saes.EncryptBytes,
+		DecryptBytes: saes.DecryptBytes,
+	}))
+}
+
+// sdesCipher S-DES：密码本与位切片穷举、CMAC 以及弱密钥分析
+type sdesCipher struct {
+	Cipher
+}
+
+var (
+	_ KeySearcher   = sdesCipher{}
+	_ MACCipher     = sdesCipher{}
+	_ WeakKeyFilter = sdesCipher{}
+)
+
+// SearchKeys 标准 2 轮直接查预计算的完整密码本，多轮变体使用位切片实现，每批并行测试 64 个密钥
+func (sdesCipher) SearchKeys(plaintext, ciphertext []int, rounds int) ([]int, string, error) {
+	p, c := utils.BitsToByte(plaintext), utils.BitsToByte(ciphertext)
+	if rounds == 0 || rounds == utils.SDES.Rounds {
+		return utils.DefaultCodebook().Lookup(p, c), SearchCodebook, nil
+	}
+	variant, err := utils.NewSDESFeistel(rounds)
+	if err != nil {
+		return nil, "", ErrRounds
+	}
+	return variant.BruteForce(p, c), SearchBitslice, nil
+}
+
+func (sdesCipher) MACKeyBits() int { return 10 }
+func (sdesCipher) TagBits() int    { return utils.MACTagBits }
+
+func (sdesCipher) Tag(data []byte, key []int) []int {
+	return utils.ByteToBits(utils.CMAC(data, key))
+}
+
+func (sdesCipher) VerifyTag(data []byte, tag []int, key []int) bool {
+	return utils.VerifyTag(data, utils.BitsToByte(tag), utils.MACTagBits, key) == nil
+}
+
+func (sdesCipher) RandomKey(excludeWeak bool) (int, error) {
+	return utils.RandomKey(excludeWeak)
+}
@@ -0,0 +1,39 @@
+package cipher
+
+import "errors"
+
+// 可选能力：算法可以额外实现以下接口，调用方通过类型断言检查
+
+// ErrRounds 算法不支持请求的轮数
+var ErrRounds = errors.New("不支持的轮数")
+
+// SearchKeys 返回的方法名：codebook 查预计算密码本，不逐个测试密钥；bitslice 用位切片穷举整个密钥空间
+const (
+	SearchCodebook = "codebook"
+	SearchBitslice = "bitslice"
+)
+
+// KeySearcher 无需逐个密钥调用 Encrypt 即可求出全部候选密钥的算法（预计算密码本、位切片等）
+type KeySearcher interface {
+	// SearchKeys 返回把 plaintext 加密为 ciphertext 的全部密钥（十进制）
+	// rounds 为 0 时使用标准轮数，其余值使用对应轮数的变体，不支持时返回 ErrRounds；method 为所用方法
+	SearchKeys(plaintext, ciphertext []int, rounds int) (keys []int, method string, err error)
+}
+
+// MACCipher 支持以该算法计算 CMAC 标签（先加密后认证）的算法
+type MACCipher interface {
+	// MACKeyBits MAC 密钥位数
+	MACKeyBits() int
+	// TagBits 标签位数
+	TagBits() int
+	// Tag 计算 data 的标签
+	Tag(data []byte, key []int) []int
+	// VerifyTag 校验 data 的标签
+	VerifyTag(data []byte, tag []int, key []int) bool
+}
+
+// WeakKeyFilter 已知弱密钥与等价冗余密钥的算法
+type WeakKeyFilter interface {
+	// RandomKey 生成随机密钥（十进制），excludeWeak 为 true 时排除弱密钥与等价组中的冗余密钥
+	RandomKey(excludeWeak bool) (int, error)
+}
@@ -0,0 +1,81 @@
+package cipher
+
+import (
+	"SDES/utils"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Cipher 可由 HTTP 接口调度的分组密码
+type Cipher interface {
+	// Name 注册名，用于请求中的 algorithm 字段
+	Name() string
+	// DisplayName 展示名称
+	DisplayName() string
+	BlockBits() int
+	KeyBits() int
+
+	Encrypt(plaintext []int, key []int) []int
+	Decrypt(ciphertext []int, key []int) []int
+	// EncryptTrace 加密并返回各步中间值，步骤命名由算法决定
+	EncryptTrace(plaintext []int, key []int) ([]int, []utils.TraceStep)
+	// DecryptTrace 解密并返回各步中间值
+	DecryptTrace(ciphertext []int, key []int) ([]int, []utils.TraceStep)
+
+	// EncryptBytes 按分组长度逐组加密字节序列
+	EncryptBytes(plaintext []byte, key []int) []byte
+	// DecryptBytes 按分组长度逐组解密字节序列，长度不合法时返回错误
+	DecryptBytes(ciphertext []byte, key []int) ([]byte, error)
+
+	// ParseKey 解析二进制字符串形式的密钥
+	ParseKey(key string) ([]int, error)
+	// ParseBlock 解析二进制字符串形式的分组
+	ParseBlock(block string) ([]int, error)
+}
+
+// DefaultName 未指定算法时使用的默认算法
+const DefaultName = "sdes"
+
+var (
+	mu       sync.RWMutex
+	registry = make(map[string]Cipher)
+)
+
+// Register 注册算法，重名时 panic
+func Register(c Cipher) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := registry[c.Name()]; ok {
+		panic(fmt.Sprintf("cipher: 算法 %s 重复注册", c.Name()))
+	}
+	registry[c.Name()] = c
+}
+
+// Lookup 按名称查找算法，名称为空时返回默认算法
+func Lookup(name string) (Cipher, error) {
+	if name == "" {
+		name = DefaultName
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	c, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("不支持的算法: %s", name)
+	}
+	return c, nil
+}
+
+// List 按名称排序返回所有已注册算法
+func List() []Cipher {
+	mu.RLock()
+	defer mu.RUnlock()
+	list := make([]Cipher, 0, len(registry))
+	for _, c := range registry {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name() < list[j].Name()
+	})
+	return list
+}
@@ -0,0 +1,166 @@
+package cipher
+
+import (
+	"SDES/utils"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// 每个已注册算法的分组加解密、字节加解密、跟踪与解析结果必须一致
+func TestRegisteredCiphersRoundTrip(t *testing.T) {
+	for _, c := range List() {
+		key := utils.IntToBits(0x2B5, c.KeyBits())
+		block := utils.IntToBits(0x41, c.BlockBits())
+
+		ciphertext := c.Encrypt(block, key)
+		traced, steps := c.EncryptTrace(block, key)
+		if !reflect.DeepEqual(traced, ciphertext) || len(steps) == 0 {
+			t.Errorf("%s: EncryptTrace mismatch", c.Name())
+		}
+		if got, steps := c.DecryptTrace(ciphertext, key); !reflect.DeepEqual(got, block) || len(steps) == 0 {
+			t.Errorf("%s: DecryptTrace got %v, want %v", c.Name(), got, block)
+		}
+		if parsed, err := c.ParseKey(utils.BitsToString(key)); err != nil || !reflect.DeepEqual(parsed, key) {
+			t.Errorf("%s: ParseKey got %v, %v", c.Name(), parsed, err)
+		}
+		if parsed, err := c.ParseBlock(utils.BitsToString(block)); err != nil || !reflect.DeepEqual(parsed, block) {
+			t.Errorf("%s: ParseBlock got %v, %v", c.Name(), parsed, err)
+		}
+		if _, err := c.ParseKey("10102"); err == nil {
+			t.Errorf("%s: ParseKey 应拒绝非法密钥", c.Name())
+		}
+		if got := c.Decrypt(ciphertext, key); !reflect.DeepEqual(got, block) {
+			t.Errorf("%s: Decrypt got %v, want %v", c.Name(), got, block)
+		}
+
+		message := []byte("Iloveyou")
+		plaintext, err := c.DecryptBytes(c.EncryptBytes(message, key), key)
+		if err != nil || string(plaintext) != string(message) {
+			t.Errorf("%s: DecryptBytes got %q, %v", c.Name(), plaintext, err)
+		}
+	}
+}
+
+// S-DES 通过可选接口提供快速穷举、CMAC 与弱密钥过滤，S-AES 只走通用路径
+func TestCapabilities(t *testing.T) {
+	sdes, _ := Lookup("sdes")
+	saes, _ := Lookup("saes")
+	searcher, ok := sdes.(KeySearcher)
+	if !ok {
+		t.Fatal("sdes 应实现 KeySearcher")
+	}
+	key := utils.StringToBits("1010000010", 10)
+	plaintext := utils.StringToBits("10110000", 8)
+	ciphertext := sdes.Encrypt(plaintext, key)
+	for _, rounds := range []int{0, 2, 4} {
+		keys, method, err := searcher.SearchKeys(plaintext, ciphertext, rounds)
+		if err != nil || method == "" {
+			t.Fatalf("rounds=%d: %v", rounds, err)
+		}
+		if rounds != 4 && !slices.Contains(keys, utils.BitsToInt(key)) {
+			t.Errorf("rounds=%d: %v 中缺少密钥", rounds, keys)
+		}
+	}
+	if _, _, err := searcher.SearchKeys(plaintext, ciphertext, 65); err != ErrRounds {
+		t.Errorf("超出范围的轮数应返回 ErrRounds，实际 %v", err)
+	}
+
+	mac := sdes.(MACCipher)
+	macKey := utils.StringToBits("1111100000", mac.MACKeyBits())
+	tag := mac.Tag([]byte("hi"), macKey)
+	if len(tag) != mac.TagBits() || !mac.VerifyTag([]byte("hi"), tag, macKey) || mac.VerifyTag([]byte("ho"), tag, macKey) {
+		t.Errorf("MAC 标签 %v 校验错误", tag)
+	}
+
+	if _, ok := saes.(KeySearcher); ok {
+		t.Error("saes 不应实现 KeySearcher")
+	}
+	if _, ok := saes.(MACCipher); ok {
+		t.Error("saes 不应实现 MACCipher")
+	}
+}
+
+func TestLookup(t *testing.T) {
+	c, err := Lookup("")
+	if err != nil || c.Name() != DefaultName {
+		t.Errorf("Lookup(\"\"): got %v, %v", c, err)
+	}
+	if _, err := Lookup("des"); err == nil {
+		t.Error("Lookup(\"des\"): expected error")
+	}
+}
+
+// 工作模式：ECB 补齐后与 EncryptBytes 一致，CBC、CTR 可往返且相同明文分组产生不同密文
+func TestModes(t *testing.T) {
+	for _, c := range List() {
+		key := utils.IntToBits(0x2B5, c.KeyBits())
+		iv := make([]byte, BlockBytes(c))
+		iv[len(iv)-1] = 0x5A
+		message := []byte("aaaaaaaaa")
+
+		ecb, err := EncryptMode(c, ModeECB, message, key, nil, PaddingZero)
+		if err != nil || string(ecb) != string(c.EncryptBytes(message, key)) {
+			t.Errorf("%s: ECB 应与 EncryptBytes 一致: %x, %v", c.Name(), ecb, err)
+		}
+		if _, err := EncryptMode(c, ModeECB, message, key, nil, PaddingNone); BlockBytes(c) > 1 && err != ErrDataLength {
+			t.Errorf("%s: 不补齐时长度不合法应返回 ErrDataLength，实际 %v", c.Name(), err)
+		}
+
+		for _, mode := range []string{ModeCBC, ModeCTR} {
+			ciphertext, err := EncryptMode(c, mode, message, key, iv, PaddingZero)
+			if err != nil {
+				t.Fatalf("%s/%s: %v", c.Name(), mode, err)
+			}
+			n := BlockBytes(c)
+			if string(ciphertext[:n]) == string(ciphertext[n:2*n]) {
+				t.Errorf("%s/%s: 相同的明文分组不应产生相同的密文", c.Name(), mode)
+			}
+			plaintext, err := DecryptMode(c, mode, ciphertext, key, iv, PaddingZero)
+			if err != nil || string(plaintext[:len(message)]) != string(message) {
+				t.Errorf("%s/%s: 往返失败 %q, %v", c.Name(), mode, plaintext, err)
+			}
+			if mode == ModeCTR && len(ciphertext) != len(message) {
+				t.Errorf("%s/ctr: 流模式不应补齐，长度 %d", c.Name(), len(ciphertext))
+			}
+		}
+		if _, err := EncryptMode(c, ModeCBC, message, key, []byte{1, 2, 3}, PaddingZero); err != ErrIVLength {
+			t.Errorf("%s: IV 长度错误应返回 ErrIVLength，实际 %v", c.Name(), err)
+		}
+		if _, err := EncryptMode(c, "ofb", message, key, iv, PaddingZero); err != ErrUnknownMode {
+			t.Errorf("%s: 未知模式应返回 ErrUnknownMode，实际 %v", c.Name(), err)
+		}
+	}
+}
+
+// PKCS#7 补齐可还原以 0x00 结尾的明文，zero 补齐会丢失这些字节
+func TestPKCS7Padding(t *testing.T) {
+	c, _ := Lookup("saes")
+	key := utils.IntToBits(0x2B5, c.KeyBits())
+	iv := []byte{0x12, 0x34}
+	message := []byte("ab\x00\x00")
+
+	for _, mode := range []string{ModeECB, ModeCBC} {
+		ciphertext, err := EncryptMode(c, mode, message, key, iv, PaddingPKCS7)
+		if err != nil || len(ciphertext) != len(message)+BlockBytes(c) {
+			t.Fatalf("%s: 整分组明文应补齐一个完整分组，长度 %d, %v", mode, len(ciphertext), err)
+		}
+		plaintext, err := DecryptMode(c, mode, ciphertext, key, iv, PaddingPKCS7)
+		if err != nil || string(plaintext) != string(message) {
+			t.Errorf("%s: PKCS#7 往返失败 %q, %v", mode, plaintext, err)
+		}
+	}
+
+	ciphertext, _ := EncryptMode(c, ModeECB, message, key, nil, PaddingZero)
+	if plaintext, _ := DecryptMode(c, ModeECB, ciphertext, key, nil, PaddingZero); string(plaintext) != "ab" {
+		t.Errorf("zero 补齐应去除末尾 0x00，实际 %q", plaintext)
+	}
+
+	// 最后一个字节 0x00、0x03 与不一致的补齐均无效
+	for _, last := range [][]byte{{0x00, 0x00}, {0x00, 0x03}, {0x01, 0x02}} {
+		block := c.EncryptBytes(last, key)
+		if _, err := DecryptMode(c, ModeECB, block, key, nil, PaddingPKCS7); err != ErrPadding {
+			t.Errorf("补齐 %x 应返回 ErrPadding，实际 %v", last, err)
+		}
+	}
+}
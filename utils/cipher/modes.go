@@ -0,0 +1,200 @@
+package cipher
+
+import (
+	"SDES/utils"
+	"bytes"
+	"errors"
+	"slices"
+)
+
+// 工作模式，用于 /api/v1 的 mode 字段
+const (
+	ModeECB = "ecb"
+	ModeCBC = "cbc"
+	ModeCTR = "ctr"
+)
+
+// Modes 支持的工作模式
+var Modes = []string{ModeECB, ModeCBC, ModeCTR}
+
+// 补齐方式，用于 /api/v1 的 padding 字段，只作用于 ECB、CBC
+// PKCS#7 总是补 1~n 个值为补齐长度的字节，解密时校验并去除，可无损还原任意数据；
+// zero 以 0x00 补齐、解密后去除多字节分组末尾的全部 0x00，明文本身以 0x00 结尾时会丢失这些字节；
+// none 不补齐，数据必须是整数个分组
+const (
+	PaddingPKCS7 = "pkcs7"
+	PaddingZero  = "zero"
+	PaddingNone  = "none"
+)
+
+var (
+	// ErrUnknownMode 不支持的工作模式
+	ErrUnknownMode = errors.New("不支持的工作模式")
+	// ErrDataLength 未补齐时数据长度不是分组长度的整数倍
+	ErrDataLength = errors.New("数据长度必须是分组长度的整数倍")
+	// ErrIVLength IV 长度与分组长度不一致
+	ErrIVLength = errors.New("IV 长度必须等于分组长度")
+	// ErrPadding 解密结果的 PKCS#7 补齐无效，通常是密钥、IV 或补齐方式不对
+	ErrPadding = errors.New("PKCS#7 补齐无效")
+)
+
+// NeedsIV 工作模式是否需要 IV
+func NeedsIV(mode string) bool {
+	return mode == ModeCBC || mode == ModeCTR
+}
+
+// BlockBytes 分组的字节数
+func BlockBytes(c Cipher) int {
+	return c.BlockBits() / 8
+}
+
+// EncryptMode 按工作模式加密字节序列
+// ECB、CBC 按 padding 补齐（zero 与 EncryptBytes 一致），为 none 时长度必须是分组的整数倍；
+// CTR 为流模式，不补齐。iv 在 ECB 下忽略
+func EncryptMode(c Cipher, mode string, data []byte, key []int, iv []byte, padding string) ([]byte, error) {
+	if err := checkMode(c, mode, iv); err != nil {
+		return nil, err
+	}
+	if mode == ModeCTR {
+		return ctr(c, data, key, iv), nil
+	}
+	n := BlockBytes(c)
+	switch rem := len(data) % n; {
+	case padding == PaddingPKCS7:
+		data = append(slices.Clone(data), bytes.Repeat([]byte{byte(n - rem)}, n-rem)...)
+	case rem == 0:
+	case padding == PaddingZero:
+		data = append(slices.Clone(data), make([]byte, n-rem)...)
+	default:
+		return nil, ErrDataLength
+	}
+	out := make([]byte, len(data))
+	prev := slices.Clone(iv)
+	for i := 0; i < len(data); i += n {
+		block := slices.Clone(data[i : i+n])
+		if mode == ModeCBC {
+			xorInto(block, prev)
+		}
+		copy(out[i:], encryptBlock(c, block, key))
+		prev = out[i : i+n]
+	}
+	return out, nil
+}
+
+// DecryptMode 按工作模式解密字节序列，ECB、CBC 下长度必须是分组的整数倍，并按 padding 去除补齐
+// PKCS#7 补齐无效时返回 ErrPadding
+func DecryptMode(c Cipher, mode string, data []byte, key []int, iv []byte, padding string) ([]byte, error) {
+	if err := checkMode(c, mode, iv); err != nil {
+		return nil, err
+	}
+	if mode == ModeCTR {
+		return ctr(c, data, key, iv), nil
+	}
+	n := BlockBytes(c)
+	if len(data)%n != 0 {
+		return nil, ErrDataLength
+	}
+	out := make([]byte, len(data))
+	prev := slices.Clone(iv)
+	for i := 0; i < len(data); i += n {
+		block := decryptBlock(c, data[i:i+n], key)
+		if mode == ModeCBC {
+			xorInto(block, prev)
+		}
+		copy(out[i:], block)
+		prev = data[i : i+n]
+	}
+	return unpad(out, n, padding)
+}
+
+// unpad 去除 ECB、CBC 解密结果的补齐
+func unpad(data []byte, n int, padding string) ([]byte, error) {
+	switch padding {
+	case PaddingPKCS7:
+		if len(data) == 0 {
+			return nil, ErrPadding
+		}
+		p := int(data[len(data)-1])
+		if p < 1 || p > n || p > len(data) {
+			return nil, ErrPadding
+		}
+		for _, b := range data[len(data)-p:] {
+			if int(b) != p {
+				return nil, ErrPadding
+			}
+		}
+		return data[:len(data)-p], nil
+	case PaddingZero:
+		// 单字节分组不会补齐
+		if n > 1 {
+			return bytes.TrimRight(data, "\x00"), nil
+		}
+	}
+	return data, nil
+}
+
+func checkMode(c Cipher, mode string, iv []byte) error {
+	if !slices.Contains(Modes, mode) {
+		return ErrUnknownMode
+	}
+	if NeedsIV(mode) && len(iv) != BlockBytes(c) {
+		return ErrIVLength
+	}
+	return nil
+}
+
+// ctr 计数器模式：计数器初值为 iv（大端），每组加一，密钥流与数据异或；加解密相同
+func ctr(c Cipher, data []byte, key []int, iv []byte) []byte {
+	n := BlockBytes(c)
+	counter := slices.Clone(iv)
+	out := make([]byte, len(data))
+	for i := 0; i < len(data); i += n {
+		stream := encryptBlock(c, counter, key)
+		for j := 0; j < n && i+j < len(data); j++ {
+			out[i+j] = data[i+j] ^ stream[j]
+		}
+		increment(counter)
+	}
+	return out
+}
+
+// increment 大端计数器加一，溢出时回绕
+func increment(counter []byte) {
+	for i := len(counter) - 1; i >= 0; i-- {
+		counter[i]++
+		if counter[i] != 0 {
+			return
+		}
+	}
+}
+
+func xorInto(dst, src []byte) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
+
+func encryptBlock(c Cipher, block []byte, key []int) []byte {
+	return bitsToBytes(c.Encrypt(bytesToBits(block), key))
+}
+
+func decryptBlock(c Cipher, block []byte, key []int) []byte {
+	return bitsToBytes(c.Decrypt(bytesToBits(block), key))
+}
+
+// bytesToBits 按大端顺序展开为位数组，与 S-AES 的 2 字节分组约定一致
+func bytesToBits(b []byte) []int {
+	bits := make([]int, 0, len(b)*8)
+	for _, x := range b {
+		bits = append(bits, utils.ByteToBits(x)...)
+	}
+	return bits
+}
+
+func bitsToBytes(bits []int) []byte {
+	b := make([]byte, len(bits)/8)
+	for i := range b {
+		b[i] = utils.BitsToByte(bits[i*8 : i*8+8])
+	}
+	return b
+}
@@ -0,0 +1,127 @@
+package classical
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	register(caesar{})
+	register(affine{})
+}
+
+// caesar 凯撒密码 E(x) = x + k mod 26
+type caesar struct{}
+
+func (caesar) Name() string        { return "caesar" }
+func (caesar) DisplayName() string { return "凯撒密码" }
+func (caesar) KeyFormat() string   { return "位移量 0-25，例如 3" }
+
+func parseShift(key string) (int, error) {
+	k, err := strconv.Atoi(strings.TrimSpace(key))
+	if err != nil {
+		return 0, newError(ErrInvalidKey, "凯撒密钥必须是整数")
+	}
+	return mod(k, 26), nil
+}
+
+func (caesar) Encrypt(text, key string) (string, error) {
+	k, err := parseShift(key)
+	if err != nil {
+		return "", err
+	}
+	return mapLetters(text, func(x, _ int) int { return x + k }), nil
+}
+
+func (caesar) Decrypt(text, key string) (string, error) {
+	k, err := parseShift(key)
+	if err != nil {
+		return "", err
+	}
+	return mapLetters(text, func(x, _ int) int { return x - k }), nil
+}
+
+// Crack 穷举 26 个位移量，选择最接近英文频率的结果
+func (caesar) Crack(ciphertext string) (CrackResult, error) {
+	if lettersOnly(ciphertext) == "" {
+		return CrackResult{}, newError(ErrTooShort, "密文中没有字母")
+	}
+	candidates := make([]scored, 26)
+	for k := 0; k < 26; k++ {
+		p := mapLetters(ciphertext, func(x, _ int) int { return x - k })
+		candidates[k] = scored{key: strconv.Itoa(k), plaintext: p, score: ChiSquared(p)}
+	}
+	best := bestOf(candidates)
+	return CrackResult{
+		Key:       best.key,
+		Plaintext: best.plaintext,
+		Score:     best.score,
+		Details:   []string{"穷举 26 个位移量，按字母频率卡方值选择"},
+	}, nil
+}
+
+// affine 仿射密码 E(x) = a·x + b mod 26，要求 gcd(a, 26) = 1
+type affine struct{}
+
+func (affine) Name() string        { return "affine" }
+func (affine) DisplayName() string { return "仿射密码" }
+func (affine) KeyFormat() string   { return "a,b，其中 a 与 26 互素，例如 5,8" }
+
+func parseAffineKey(key string) (int, int, error) {
+	parts := strings.Split(key, ",")
+	if len(parts) != 2 {
+		return 0, 0, newError(ErrInvalidKey, "仿射密钥格式为 a,b")
+	}
+	a, errA := strconv.Atoi(strings.TrimSpace(parts[0]))
+	b, errB := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if errA != nil || errB != nil {
+		return 0, 0, newError(ErrInvalidKey, "仿射密钥 a、b 必须是整数")
+	}
+	if _, ok := modInverse(a, 26); !ok {
+		return 0, 0, newError(ErrInvalidKey, "a=%d 与 26 不互素，无法解密", a)
+	}
+	return mod(a, 26), mod(b, 26), nil
+}
+
+func (affine) Encrypt(text, key string) (string, error) {
+	a, b, err := parseAffineKey(key)
+	if err != nil {
+		return "", err
+	}
+	return mapLetters(text, func(x, _ int) int { return a*x + b }), nil
+}
+
+func (affine) Decrypt(text, key string) (string, error) {
+	a, b, err := parseAffineKey(key)
+	if err != nil {
+		return "", err
+	}
+	aInv, _ := modInverse(a, 26)
+	return mapLetters(text, func(x, _ int) int { return aInv * (x - b) }), nil
+}
+
+// Crack 穷举 12×26 个密钥
+func (affine) Crack(ciphertext string) (CrackResult, error) {
+	if lettersOnly(ciphertext) == "" {
+		return CrackResult{}, newError(ErrTooShort, "密文中没有字母")
+	}
+	var candidates []scored
+	for a := 1; a < 26; a++ {
+		aInv, ok := modInverse(a, 26)
+		if !ok {
+			continue
+		}
+		for b := 0; b < 26; b++ {
+			p := mapLetters(ciphertext, func(x, _ int) int { return aInv * (x - b) })
+			candidates = append(candidates, scored{key: fmt.Sprintf("%d,%d", a, b), plaintext: p, score: ChiSquared(p)})
+		}
+	}
+	best := bestOf(candidates)
+	return CrackResult{
+		Key:       best.key,
+		Plaintext: best.plaintext,
+		Score:     best.score,
+		Details:   []string{fmt.Sprintf("穷举 %d 个密钥，按字母频率卡方值选择", len(candidates))},
+	}, nil
+}
@@ -0,0 +1,125 @@
+package classical
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 古典密码：凯撒、仿射、维吉尼亚、Playfair、2×2 Hill
+// 只对英文字母 A-Z 进行变换，统一输出大写
+
+// 错误类别：各算法返回的错误带有具体说明，可用 errors.Is 判断所属类别
+var (
+	// ErrInvalidKey 密钥格式无效
+	ErrInvalidKey = errors.New("密钥格式无效")
+	// ErrOddLetters 该算法要求文本的字母数为偶数
+	ErrOddLetters = errors.New("字母数必须为偶数")
+	// ErrTooShort 密文字母太少，无法进行唯密文分析
+	ErrTooShort = errors.New("密文太短，无法分析")
+	// ErrNoSolution 唯密文分析未找到可行的密钥
+	ErrNoSolution = errors.New("未找到可行的密钥")
+)
+
+// detailError 属于某一错误类别、带有具体说明的错误
+type detailError struct {
+	kind error
+	msg  string
+}
+
+func (e *detailError) Error() string { return e.msg }
+func (e *detailError) Unwrap() error { return e.kind }
+
+// newError 创建属于 kind 类别的错误，错误信息为格式化后的说明
+func newError(kind error, format string, args ...any) error {
+	return &detailError{kind: kind, msg: fmt.Sprintf(format, args...)}
+}
+
+// CrackResult 唯密文分析结果
+type CrackResult struct {
+	Key       string
+	Plaintext string
+	Score     float64  // 卡方值，越小越接近英文
+	Details   []string // 分析过程说明
+}
+
+// Cipher 古典密码
+type Cipher interface {
+	Name() string
+	DisplayName() string
+	// KeyFormat 密钥格式说明
+	KeyFormat() string
+	Encrypt(text, key string) (string, error)
+	Decrypt(text, key string) (string, error)
+	Crack(ciphertext string) (CrackResult, error)
+}
+
+var registry = map[string]Cipher{}
+
+func register(c Cipher) {
+	registry[c.Name()] = c
+}
+
+// Lookup 按名称查找古典密码
+func Lookup(name string) (Cipher, error) {
+	c, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("不支持的古典密码: %s", name)
+	}
+	return c, nil
+}
+
+// List 按名称排序返回所有古典密码
+func List() []Cipher {
+	list := make([]Cipher, 0, len(registry))
+	for _, c := range registry {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name() < list[j].Name()
+	})
+	return list
+}
+
+// lettersOnly 提取字母并转为大写
+func lettersOnly(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(text) {
+		if r >= 'A' && r <= 'Z' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// mapLetters 对每个字母应用 f（参数为字母序号 0-25 与当前是第几个字母），非字母原样保留
+func mapLetters(text string, f func(x, i int) int) string {
+	var b strings.Builder
+	i := 0
+	for _, r := range strings.ToUpper(text) {
+		if r >= 'A' && r <= 'Z' {
+			b.WriteRune(rune('A' + mod(f(int(r-'A'), i), 26)))
+			i++
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// mod 非负取模
+func mod(a, m int) int {
+	return (a%m + m) % m
+}
+
+// modInverse 求 a 在模 m 下的乘法逆元，不存在时返回 false
+func modInverse(a, m int) (int, bool) {
+	a = mod(a, m)
+	for x := 1; x < m; x++ {
+		if a*x%m == 1 {
+			return x, true
+		}
+	}
+	return 0, false
+}
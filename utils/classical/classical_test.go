@@ -0,0 +1,114 @@
+package classical
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const sampleText = `It was the best of times, it was the worst of times, it was the age of wisdom,
+it was the age of foolishness, it was the epoch of belief, it was the epoch of incredulity,
+it was the season of Light, it was the season of Darkness, it was the spring of hope,
+it was the winter of despair, we had everything before us, we had nothing before us.`
+
+func TestRoundTrip(t *testing.T) {
+	keys := map[string]string{
+		"caesar":   "3",
+		"affine":   "5,8",
+		"vigenere": "LEMON",
+		"playfair": "MONARCHY",
+		"hill":     "3,3,2,5",
+	}
+	for name, key := range keys {
+		c, err := Lookup(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ciphertext, err := c.Encrypt("attackatdawn", key)
+		if err != nil {
+			t.Fatalf("%s: Encrypt: %v", name, err)
+		}
+		plaintext, err := c.Decrypt(ciphertext, key)
+		if err != nil {
+			t.Fatalf("%s: Decrypt: %v", name, err)
+		}
+		if !strings.HasPrefix(plaintext, "ATTACKATDAWN") {
+			t.Errorf("%s: got %s", name, plaintext)
+		}
+	}
+}
+
+func TestPlayfairTextbook(t *testing.T) {
+	got, _ := playfair{}.Encrypt("instruments", "MONARCHY")
+	if got != "GATLMZCLRQXA" {
+		t.Errorf("Playfair: got %s, want GATLMZCLRQXA", got)
+	}
+}
+
+func TestCrack(t *testing.T) {
+	keys := map[string]string{
+		"caesar":   "7",
+		"affine":   "7,3",
+		"vigenere": "CIPHER",
+		"hill":     "3,3,2,5",
+	}
+	want := lettersOnly(sampleText)
+	for name, key := range keys {
+		c, _ := Lookup(name)
+		ciphertext, _ := c.Encrypt(sampleText, key)
+		result, err := c.Crack(ciphertext)
+		if err != nil {
+			t.Fatalf("%s: Crack: %v", name, err)
+		}
+		if !strings.HasPrefix(lettersOnly(result.Plaintext), want) {
+			t.Errorf("%s: recovered key %s, plaintext %.40s...", name, result.Key, result.Plaintext)
+		}
+	}
+}
+
+// playfairText 不在评分语料中的一段英文，供 Playfair 唯密文分析测试
+const playfairText = `Every spring the old ferry carried farmers, traders and travellers across the wide brown river that divided the two halves of the county. The ferryman had worked the crossing for almost forty years, and he liked to say that he had seen every kind of weather and every kind of passenger. Some were cheerful and talked the whole way over, some sat quietly with their baskets on their knees, and some watched the water with frightened eyes because they could not swim. He treated them all with the same calm patience, checking the ropes before each journey and keeping the deck swept clean.`
+
+func TestPlayfairCrack(t *testing.T) {
+	ciphertext, _ := playfair{}.Encrypt(playfairText, "MONARCHY")
+	// 等价方阵（行、列循环移位）给出相同明文，因此比较明文而非密钥
+	want, _ := playfair{}.Decrypt(ciphertext, "MONARCHY")
+	result, err := playfair{}.Crack(ciphertext)
+	if err != nil {
+		t.Fatalf("Crack: %v", err)
+	}
+	if result.Plaintext != want {
+		t.Errorf("recovered key %s, plaintext %.40s...", result.Key, result.Plaintext)
+	}
+	if got, _ := (playfair{}).Decrypt(ciphertext, result.Key); got != want {
+		t.Errorf("返回的方阵 %s 不能解密密文", result.Key)
+	}
+
+	if _, err := (playfair{}).Crack(ciphertext[:100]); err == nil {
+		t.Error("过短的密文应返回错误")
+	}
+}
+
+// 错误按类别包装哨兵错误，调用方可以用 errors.Is 区分
+func TestErrorKinds(t *testing.T) {
+	hill, _ := Lookup("hill")
+	caesar, _ := Lookup("caesar")
+	playfair, _ := Lookup("playfair")
+	_, errKey := caesar.Encrypt("hello", "x")
+	_, errOdd := playfair.Decrypt("abc", "MONARCHY")
+	_, errHillOdd := hill.Crack("abc")
+	_, errShort := hill.Crack("ab")
+	cases := []struct {
+		err, kind error
+	}{
+		{errKey, ErrInvalidKey},
+		{errOdd, ErrOddLetters},
+		{errHillOdd, ErrOddLetters},
+		{errShort, ErrTooShort},
+	}
+	for i, tc := range cases {
+		if !errors.Is(tc.err, tc.kind) {
+			t.Errorf("#%d: %v 不是 %v", i, tc.err, tc.kind)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package classical
+
+import (
+	_ "embed"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// 唯密文评分：与英文字母频率比较的卡方统计量，以及重合指数与 Kasiski 测试
+
+// EnglishFrequencies 英文字母出现频率 A-Z
+var EnglishFrequencies = [26]float64{
+	0.08167, 0.01492, 0.02782, 0.04253, 0.12702, 0.02228, 0.02015,
+	0.06094, 0.06966, 0.00153, 0.00772, 0.04025, 0.02406, 0.06749,
+	0.07507, 0.01929, 0.00095, 0.05987, 0.06327, 0.09056, 0.02758,
+	0.00978, 0.02360, 0.00150, 0.01974, 0.00074,
+}
+
+// EnglishIC 英文文本的重合指数
+const EnglishIC = 0.0667
+
+// LetterCounts 统计字母出现次数
+func LetterCounts(text string) ([26]int, int) {
+	var counts [26]int
+	total := 0
+	for _, r := range text {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			counts[r-'A']++
+			total++
+		case r >= 'a' && r <= 'z':
+			counts[r-'a']++
+			total++
+		}
+	}
+	return counts, total
+}
+
+// ChiSquared 文本字母分布相对英文的卡方值，没有字母时返回 +Inf 近似值
+func ChiSquared(text string) float64 {
+	counts, total := LetterCounts(text)
+	if total == 0 {
+		return 1e18
+	}
+	score := 0.0
+	for i, c := range counts {
+		expected := EnglishFrequencies[i] * float64(total)
+		diff := float64(c) - expected
+		score += diff * diff / expected
+	}
+	return score
+}
+
+// IndexOfCoincidence 重合指数
+func IndexOfCoincidence(text string) float64 {
+	counts, total := LetterCounts(text)
+	if total < 2 {
+		return 0
+	}
+	sum := 0
+	for _, c := range counts {
+		sum += c * (c - 1)
+	}
+	return float64(sum) / float64(total*(total-1))
+}
+
+// KasiskiDistances 统计重复三字母组之间的距离因子，返回按出现次数排序的候选密钥长度
+func KasiskiDistances(ciphertext string, maxLen int) []int {
+	text := lettersOnly(ciphertext)
+	positions := make(map[string][]int)
+	for i := 0; i+3 <= len(text); i++ {
+		positions[text[i:i+3]] = append(positions[text[i:i+3]], i)
+	}
+
+	factors := make(map[int]int)
+	for _, pos := range positions {
+		for i := 1; i < len(pos); i++ {
+			d := pos[i] - pos[i-1]
+			for f := 2; f <= maxLen; f++ {
+				if d%f == 0 {
+					factors[f]++
+				}
+			}
+		}
+	}
+
+	candidates := make([]int, 0, len(factors))
+	for f := range factors {
+		candidates = append(candidates, f)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if factors[candidates[i]] != factors[candidates[j]] {
+			return factors[candidates[i]] > factors[candidates[j]]
+		}
+		return candidates[i] < candidates[j]
+	})
+	return candidates
+}
+
+// FrequencyReport 字母频率分析结果（百分比）
+func FrequencyReport(text string) map[string]float64 {
+	counts, total := LetterCounts(text)
+	report := make(map[string]float64, 26)
+	if total == 0 {
+		return report
+	}
+	for i, c := range counts {
+		if c > 0 {
+			report[string(rune('A'+i))] = float64(c) * 100 / float64(total)
+		}
+	}
+	return report
+}
+
+// commonBigrams 英文中最常见的双字母组合
+var commonBigrams = []string{
+	"TH", "HE", "IN", "ER", "AN", "RE", "ND", "ON", "EN", "AT",
+	"OU", "ED", "HA", "TO", "OR", "IT", "IS", "HI", "ES", "NG",
+}
+
+// CommonBigramRate 常见双字母组合在文本中的占比，用于区分字母频率相同的候选
+func CommonBigramRate(text string) float64 {
+	letters := lettersOnly(text)
+	if len(letters) < 2 {
+		return 0
+	}
+	hits := 0
+	for i := 0; i+2 <= len(letters); i++ {
+		for _, bg := range commonBigrams {
+			if letters[i:i+2] == bg {
+				hits++
+				break
+			}
+		}
+	}
+	return float64(hits) / float64(len(letters)-1)
+}
+
+// scored 候选明文与评分
+type scored struct {
+	key       string
+	plaintext string
+	score     float64
+}
+
+// bestOf 从候选中选择卡方值最小者
+func bestOf(candidates []scored) scored {
+	best := candidates[0]
+	for _, c := range candidates[1:] {
+		if c.score < best.score {
+			best = c
+		}
+	}
+	return best
+}
+
+// englishCorpus 统计三字母组合频率所用的英文语料
+//
+//go:embed english.txt
+var englishCorpus string
+
+var (
+	trigramOnce sync.Once
+	// trigramLog 三字母组合的 log10 频率，未出现的组合取语料中出现一次的一半
+	trigramLog []float64
+)
+
+func loadTrigrams() {
+	letters := lettersOnly(englishCorpus)
+	counts := make([]float64, 26*26*26)
+	total := 0.0
+	for i := 0; i+3 <= len(letters); i++ {
+		counts[trigramIndex(letters[i], letters[i+1], letters[i+2])]++
+		total++
+	}
+	trigramLog = make([]float64, len(counts))
+	floor := math.Log10(0.5 / total)
+	for i, c := range counts {
+		trigramLog[i] = floor
+		if c > 0 {
+			trigramLog[i] = math.Log10(c / total)
+		}
+	}
+}
+
+func trigramIndex(a, b, c byte) int {
+	return (int(a-'A')*26+int(b-'A'))*26 + int(c-'A')
+}
+
+// TrigramScore 大写字母序列按英文三字母组合频率计算的对数似然，越大越接近英文
+// 与卡方值不同，它能反映字母顺序，适用于 Playfair 等替换双字母的密码
+func TrigramScore(letters []byte) float64 {
+	trigramOnce.Do(loadTrigrams)
+	score := 0.0
+	for i := 0; i+3 <= len(letters); i++ {
+		score += trigramLog[trigramIndex(letters[i], letters[i+1], letters[i+2])]
+	}
+	return score
+}
+
+func formatIC(ic float64) string {
+	return fmt.Sprintf("%.4f", ic)
+}
@@ -0,0 +1,115 @@
+package classical
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Vigenère 密钥长度搜索上限
+const maxVigenereKeyLen = 16
+
+func init() {
+	register(vigenere{})
+}
+
+// vigenere 维吉尼亚密码，密钥只在字母上推进
+type vigenere struct{}
+
+func (vigenere) Name() string        { return "vigenere" }
+func (vigenere) DisplayName() string { return "维吉尼亚密码" }
+func (vigenere) KeyFormat() string   { return "英文字母关键词，例如 LEMON" }
+
+func parseVigenereKey(key string) ([]int, error) {
+	letters := lettersOnly(key)
+	if letters == "" {
+		return nil, newError(ErrInvalidKey, "维吉尼亚密钥必须包含字母")
+	}
+	shifts := make([]int, len(letters))
+	for i, r := range letters {
+		shifts[i] = int(r - 'A')
+	}
+	return shifts, nil
+}
+
+func (vigenere) Encrypt(text, key string) (string, error) {
+	shifts, err := parseVigenereKey(key)
+	if err != nil {
+		return "", err
+	}
+	return mapLetters(text, func(x, i int) int { return x + shifts[i%len(shifts)] }), nil
+}
+
+func (vigenere) Decrypt(text, key string) (string, error) {
+	shifts, err := parseVigenereKey(key)
+	if err != nil {
+		return "", err
+	}
+	return mapLetters(text, func(x, i int) int { return x - shifts[i%len(shifts)] }), nil
+}
+
+// columnsIC 按密钥长度分列后的平均重合指数
+func columnsIC(text string, n int) float64 {
+	total := 0.0
+	for col := 0; col < n; col++ {
+		var b strings.Builder
+		for i := col; i < len(text); i += n {
+			b.WriteByte(text[i])
+		}
+		total += IndexOfCoincidence(b.String())
+	}
+	return total / float64(n)
+}
+
+// Crack 先用 Kasiski 测试与重合指数估计密钥长度，再对每一列做凯撒频率分析
+func (vigenere) Crack(ciphertext string) (CrackResult, error) {
+	text := lettersOnly(ciphertext)
+	if len(text) < 2 {
+		return CrackResult{}, newError(ErrTooShort, "密文太短，无法分析")
+	}
+
+	details := []string{fmt.Sprintf("整体重合指数 %s（英文约 %.4f，随机约 0.0385）", formatIC(IndexOfCoincidence(text)), EnglishIC)}
+	kasiski := KasiskiDistances(text, maxVigenereKeyLen)
+	if len(kasiski) > 0 {
+		top := kasiski
+		if len(top) > 5 {
+			top = top[:5]
+		}
+		details = append(details, fmt.Sprintf("Kasiski 候选长度 %v", top))
+	}
+
+	// 选择列平均重合指数最接近英文的最短长度；较长的倍数也会接近英文，因此容许少量误差
+	maxLen := maxVigenereKeyLen
+	if len(text)/2 < maxLen {
+		maxLen = len(text) / 2
+	}
+	bestLen, bestDiff := 1, math.Inf(1)
+	for n := 1; n <= maxLen; n++ {
+		diff := math.Abs(columnsIC(text, n) - EnglishIC)
+		if diff < bestDiff-0.005 {
+			bestLen, bestDiff = n, diff
+		}
+	}
+	details = append(details, fmt.Sprintf("按列重合指数估计密钥长度 %d（列平均 %s）", bestLen, formatIC(columnsIC(text, bestLen))))
+
+	// 每列独立做凯撒分析
+	key := make([]byte, bestLen)
+	for col := 0; col < bestLen; col++ {
+		var b strings.Builder
+		for i := col; i < len(text); i += bestLen {
+			b.WriteByte(text[i])
+		}
+		result, _ := caesar{}.Crack(b.String())
+		shift, _ := strconv.Atoi(result.Key)
+		key[col] = byte('A' + shift)
+	}
+
+	plaintext, _ := vigenere{}.Decrypt(ciphertext, string(key))
+	return CrackResult{
+		Key:       string(key),
+		Plaintext: plaintext,
+		Score:     ChiSquared(plaintext),
+		Details:   details,
+	}, nil
+}
@@ -0,0 +1,174 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// 完整密码本索引
+// 密钥空间 1024、分组 8 位，全部 E_k(p) 只有 1024 × 256 字节（256 KiB），
+// 预先计算后可按 (明文, 密文) 直接查出所有候选密钥，无需在线穷举。
+
+// CodebookSize 完整密码本的字节数
+const CodebookSize = KeySpace * BlockSpace
+
+// codebookMagic 持久化文件头，格式变化时需修改版本号
+var codebookMagic = []byte("SDESCB1\n")
+
+// ErrCodebookCorrupt 持久化的密码本与当前实现不一致
+var ErrCodebookCorrupt = errors.New("密码本文件已损坏或与当前实现不一致")
+
+// CodebookIndex 标准 2 轮 S-DES 的完整密码本及 (明文, 密文) → 密钥 倒排索引
+type CodebookIndex struct {
+	// table[k][p] = E_k(p)
+	table [KeySpace][BlockSpace]byte
+	// offsets 以 p<<8|c 为下标，keys[offsets[i]:offsets[i+1]] 为对应的密钥（升序）
+	offsets [BlockSpace*BlockSpace + 1]uint32
+	keys    [CodebookSize]uint16
+}
+
+// BuildCodebookIndex 使用位切片实现计算完整密码本并建立索引
+func BuildCodebookIndex() *CodebookIndex {
+	ci := &CodebookIndex{}
+	for k := 0; k < KeySpace; k++ {
+		ci.table[k] = SDES.bitsliced.Codebook(k)
+	}
+	ci.buildIndex()
+	return ci
+}
+
+// buildIndex 按 (p, c) 计数排序生成倒排索引
+func (ci *CodebookIndex) buildIndex() {
+	var counts [BlockSpace * BlockSpace]uint32
+	for k := range ci.table {
+		for p, c := range ci.table[k] {
+			counts[p<<8|int(c)]++
+		}
+	}
+	for i, n := range counts {
+		ci.offsets[i+1] = ci.offsets[i] + n
+	}
+	next := ci.offsets
+	for k := range ci.table {
+		for p, c := range ci.table[k] {
+			i := p<<8 | int(c)
+			ci.keys[next[i]] = uint16(k)
+			next[i]++
+		}
+	}
+}
+
+// Encrypt 查表加密
+func (ci *CodebookIndex) Encrypt(plaintext byte, key int) byte {
+	return ci.table[key][plaintext]
+}
+
+// Lookup 返回所有满足 E_k(plaintext) = ciphertext 的密钥（升序）
+func (ci *CodebookIndex) Lookup(plaintext, ciphertext byte) []int {
+	i := int(plaintext)<<8 | int(ciphertext)
+	keys := make([]int, 0, ci.offsets[i+1]-ci.offsets[i])
+	for _, k := range ci.keys[ci.offsets[i]:ci.offsets[i+1]] {
+		keys = append(keys, int(k))
+	}
+	return keys
+}
+
+// WriteTo 写出文件头与原始密码本，索引在读取时重建
+func (ci *CodebookIndex) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(codebookMagic)
+	if err != nil {
+		return int64(n), err
+	}
+	for k := range ci.table {
+		m, err := w.Write(ci.table[k][:])
+		n += m
+		if err != nil {
+			return int64(n), err
+		}
+	}
+	return int64(n), nil
+}
+
+// ReadCodebookIndex 读取 WriteTo 写出的密码本，抽查若干密钥后重建索引
+func ReadCodebookIndex(r io.Reader) (*CodebookIndex, error) {
+	header := make([]byte, len(codebookMagic))
+	if _, err := io.ReadFull(r, header); err != nil || !bytes.Equal(header, codebookMagic) {
+		return nil, ErrCodebookCorrupt
+	}
+	ci := &CodebookIndex{}
+	for k := range ci.table {
+		if _, err := io.ReadFull(r, ci.table[k][:]); err != nil {
+			return nil, ErrCodebookCorrupt
+		}
+	}
+	// 每隔 64 个密钥抽查一行
+	for k := 0; k < KeySpace; k += BitsliceLanes {
+		if ci.table[k] != SDES.bitsliced.Codebook(k) {
+			return nil, ErrCodebookCorrupt
+		}
+	}
+	ci.buildIndex()
+	return ci, nil
+}
+
+// LoadCodebookIndex 从 path 读取密码本；文件不存在或损坏时重新计算并写回
+func LoadCodebookIndex(path string) (*CodebookIndex, error) {
+	if f, err := os.Open(path); err == nil {
+		ci, err := ReadCodebookIndex(bufio.NewReader(f))
+		f.Close()
+		if err == nil {
+			return ci, nil
+		}
+	}
+
+	ci := BuildCodebookIndex()
+	f, err := os.Create(path)
+	if err != nil {
+		return ci, fmt.Errorf("写入密码本失败: %w", err)
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	if _, err := ci.WriteTo(w); err != nil {
+		return ci, fmt.Errorf("写入密码本失败: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		return ci, fmt.Errorf("写入密码本失败: %w", err)
+	}
+	return ci, nil
+}
+
+var (
+	codebookOnce  sync.Once
+	codebookPath  string
+	codebookIndex *CodebookIndex
+	codebookErr   error
+)
+
+// SetCodebookPath 设置密码本持久化路径，须在首次调用 DefaultCodebook 之前设置；为空时只保存在内存
+func SetCodebookPath(path string) {
+	codebookPath = path
+}
+
+// DefaultCodebook 首次调用时构建（或从磁盘加载）全局密码本索引
+// 持久化失败不影响使用，错误可通过 DefaultCodebookErr 查看
+func DefaultCodebook() *CodebookIndex {
+	codebookOnce.Do(func() {
+		if codebookPath == "" {
+			codebookIndex = BuildCodebookIndex()
+			return
+		}
+		codebookIndex, codebookErr = LoadCodebookIndex(codebookPath)
+	})
+	return codebookIndex
+}
+
+// DefaultCodebookErr 返回加载或持久化全局密码本时的错误
+func DefaultCodebookErr() error {
+	DefaultCodebook()
+	return codebookErr
+}
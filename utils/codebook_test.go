@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestCodebookIndex(t *testing.T) {
+	ci := BuildCodebookIndex()
+	for _, p := range []byte{0x00, 0x41, 0x72, 0xFF} {
+		for c := 0; c < BlockSpace; c++ {
+			got := ci.Lookup(p, byte(c))
+			want := SDES.bitsliced.BruteForce(p, byte(c))
+			if len(got) != len(want) || (len(want) > 0 && !reflect.DeepEqual(got, want)) {
+				t.Fatalf("plaintext=%d ciphertext=%d: got %v, want %v", p, c, got, want)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := ci.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := ReadCodebookIndex(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Lookup(0x41, 0x20), ci.Lookup(0x41, 0x20)) {
+		t.Fatal("loaded codebook differs")
+	}
+
+	corrupt := buf.Bytes()
+	corrupt[len(codebookMagic)] ^= 1
+	if _, err := ReadCodebookIndex(bytes.NewReader(corrupt)); err != ErrCodebookCorrupt {
+		t.Fatalf("corrupt codebook: got %v", err)
+	}
+}
+
+func TestTMTO(t *testing.T) {
+	for _, method := range []TMTOMethod{Hellman, Rainbow} {
+		tm, err := BuildTMTO(TMTOParams{Method: method, ChainLength: 32, Chains: 64, Tables: 4, Seed: 1}, BuildCodebookIndex())
+		if err != nil {
+			t.Fatal(err)
+		}
+		report := tm.Evaluate()
+		if report.SuccessRate < 0.5 {
+			t.Errorf("%s: success rate %.2f", method, report.SuccessRate)
+		}
+		t.Logf("%s: %+v", method, report)
+	}
+}
@@ -0,0 +1,328 @@
+package exercise
+
+import (
+	"SDES/utils"
+	"SDES/utils/cipher"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// 练习题生成与自动评分
+// 每道题由题型与种子完全确定，ID 形如 encrypt-12345，评分时按 ID 重新生成标准答案，服务端无需保存题目。
+// 对外下发的 ID 由 Signer 附加签名，评分接口只接受本服务签发的 ID。
+// 题目经由算法注册表调用 S-DES，中间步骤使用其 EncryptTrace 的命名（k1、IP、R1.EP、SW、IP^-1 等），答对部分步骤可获得部分分数。
+
+// Kind 题型
+type Kind string
+
+const (
+	// Encrypt 用给定密钥加密一个字节
+	Encrypt Kind = "encrypt"
+	// Subkeys 求给定密钥的子密钥 k1、k2
+	Subkeys Kind = "subkeys"
+	// Decrypt 用给定密钥解密 Base64 密文
+	Decrypt Kind = "decrypt"
+	// Recover 由若干明密文对恢复密钥
+	Recover Kind = "recover"
+)
+
+// Kinds 全部题型
+var Kinds = []Kind{Encrypt, Subkeys, Decrypt, Recover}
+
+// 每题分值
+var points = map[Kind]int{
+	Encrypt: 10,
+	Subkeys: 10,
+	Decrypt: 10,
+	Recover: 20,
+}
+
+// stageWeight 最终答案错误时，中间步骤最多可获得的分值比例
+const stageWeight = 0.8
+
+// MaxExercises 一次生成的最大题目数
+const MaxExercises = 100
+
+// 解密题使用的单词表
+var words = []string{"CRYPTO", "FEISTEL", "SBOX", "KEY", "CIPHER", "BLOCK", "ROUND", "SECRET", "ORACLE", "SWAP"}
+
+// Pair 明密文对
+type Pair struct {
+	Plaintext  string
+	Ciphertext string
+}
+
+// Exercise 题目（不含答案）
+type Exercise struct {
+	ID               string
+	Kind             Kind
+	Seed             uint64
+	Prompt           string
+	Points           int
+	Key              string
+	Plaintext        string
+	CiphertextBase64 string
+	Pairs            []Pair
+}
+
+// Solution 标准答案，Stages 为可获得部分分数的中间步骤（按顺序）
+type Solution struct {
+	Answer string
+	// Accepted 额外接受的答案，例如恢复密钥题中的等价密钥
+	Accepted []string
+	Stages   []utils.TraceStep
+}
+
+// Answer 学生提交的答案
+type Answer struct {
+	ID     string
+	Answer string
+	Stages map[string]string
+}
+
+// StageResult 单个中间步骤的评分
+type StageResult struct {
+	Name    string
+	Correct bool
+	Given   bool
+}
+
+// Result 单题评分结果
+type Result struct {
+	ID       string
+	Kind     Kind
+	Points   int
+	Score    float64
+	Correct  bool
+	Stages   []StageResult
+	Feedback string
+}
+
+// ParseKind 解析题型
+func ParseKind(s string) (Kind, error) {
+	for _, k := range Kinds {
+		if string(k) == s {
+			return k, nil
+		}
+	}
+	return "", fmt.Errorf("不支持的题型: %s", s)
+}
+
+// ParseID 解析题目 ID 中的题型与种子
+func ParseID(id string) (Kind, uint64, error) {
+	name, seedText, ok := strings.Cut(id, "-")
+	if !ok {
+		return "", 0, fmt.Errorf("无效的题目 ID: %s", id)
+	}
+	kind, err := ParseKind(name)
+	if err != nil {
+		return "", 0, err
+	}
+	seed, err := strconv.ParseUint(seedText, 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("无效的题目 ID: %s", id)
+	}
+	return kind, seed, nil
+}
+
+// newRand 由题型与种子构造确定性随机数生成器
+func newRand(kind Kind, seed uint64) *rand.Rand {
+	var salt uint64
+	for _, c := range kind {
+		salt = salt*31 + uint64(c)
+	}
+	return rand.New(rand.NewPCG(seed, salt))
+}
+
+// sdes 出题与求解使用的算法，经由注册表取得
+var sdes = func() cipher.Cipher {
+	alg, err := cipher.Lookup("sdes")
+	if err != nil {
+		panic(err)
+	}
+	return alg
+}()
+
+func keyString(k int) string {
+	return utils.BitsToString(utils.IntToBits(k, sdes.KeyBits()))
+}
+
+func byteString(b byte) string {
+	return utils.BitsToString(utils.ByteToBits(b))
+}
+
+// Generate 按题型与种子生成题目及标准答案
+func Generate(kind Kind, seed uint64) (Exercise, Solution) {
+	rng := newRand(kind, seed)
+	key := rng.IntN(utils.KeySpace)
+	keyBits := utils.IntToBits(key, sdes.KeyBits())
+	ex := Exercise{
+		ID:     fmt.Sprintf("%s-%d", kind, seed),
+		Kind:   kind,
+		Seed:   seed,
+		Points: points[kind],
+	}
+	var sol Solution
+
+	switch kind {
+	case Encrypt:
+		p := byte(rng.IntN(utils.BlockSpace))
+		ex.Key = keyString(key)
+		ex.Plaintext = byteString(p)
+		ex.Prompt = fmt.Sprintf("使用密钥 %s 加密明文 %s，给出 8 位密文", ex.Key, ex.Plaintext)
+		ciphertext, steps := sdes.EncryptTrace(utils.ByteToBits(p), keyBits)
+		sol.Answer = utils.BitsToString(ciphertext)
+		sol.Stages = steps
+
+	case Subkeys:
+		ex.Key = keyString(key)
+		ex.Prompt = fmt.Sprintf("求密钥 %s 的子密钥，答案格式为 k1,k2", ex.Key)
+		trace := utils.KeyScheduleTrace(keyBits)
+		sol.Answer = utils.BitsToString(trace.K1) + "," + utils.BitsToString(trace.K2)
+		sol.Stages = append(sol.Stages, utils.TraceStep{Name: "P10", Value: utils.BitsToString(trace.P10)})
+		for _, s := range trace.Shifts {
+			sol.Stages = append(sol.Stages, utils.TraceStep{Name: s.Name, Value: utils.BitsToString(s.Left) + utils.BitsToString(s.Right)})
+		}
+		sol.Stages = append(sol.Stages,
+			utils.TraceStep{Name: "k1", Value: utils.BitsToString(trace.K1)},
+			utils.TraceStep{Name: "k2", Value: utils.BitsToString(trace.K2)})
+
+	case Decrypt:
+		word := words[rng.IntN(len(words))]
+		ex.Key = keyString(key)
+		ex.CiphertextBase64 = base64.StdEncoding.EncodeToString(sdes.EncryptBytes([]byte(word), keyBits))
+		ex.Prompt = fmt.Sprintf("使用密钥 %s 解密 Base64 密文 %s，给出 ASCII 明文", ex.Key, ex.CiphertextBase64)
+		sol.Answer = word
+		// 每个字节的二进制明文作为中间步骤
+		for i := 0; i < len(word); i++ {
+			sol.Stages = append(sol.Stages, utils.TraceStep{Name: fmt.Sprintf("byte%d", i+1), Value: byteString(word[i])})
+		}
+
+	case Recover:
+		// 选取能唯一确定子密钥的明文对（最多 4 对）
+		for _, p := range rng.Perm(utils.BlockSpace) {
+			c := sdes.Encrypt(utils.ByteToBits(byte(p)), keyBits)
+			ex.Pairs = append(ex.Pairs, Pair{Plaintext: byteString(byte(p)), Ciphertext: utils.BitsToString(c)})
+			if len(ex.Pairs) >= 2 && len(consistentKeys(ex.Pairs)) <= 2 || len(ex.Pairs) == 4 {
+				break
+			}
+		}
+		ex.Prompt = "由以下明密文对恢复 10 位密钥"
+		candidates := consistentKeys(ex.Pairs)
+		sol.Answer = keyString(key)
+		for _, k := range candidates {
+			if k != key {
+				sol.Accepted = append(sol.Accepted, keyString(k))
+			}
+		}
+		k1, k2 := utils.KeyExpansion(keyBits)
+		sol.Stages = []utils.TraceStep{
+			{Name: "k1", Value: utils.BitsToString(k1)},
+			{Name: "k2", Value: utils.BitsToString(k2)},
+		}
+	}
+	return ex, sol
+}
+
+// consistentKeys 返回与所有明密文对一致的密钥；明密文对由 Generate 生成，格式总是有效
+func consistentKeys(pairs []Pair) []int {
+	searcher := sdes.(cipher.KeySearcher)
+	var keys []int
+	for i, pair := range pairs {
+		p, _ := sdes.ParseBlock(pair.Plaintext)
+		c, _ := sdes.ParseBlock(pair.Ciphertext)
+		found, _, _ := searcher.SearchKeys(p, c, 0)
+		if i == 0 {
+			keys = found
+			continue
+		}
+		keys = intersect(keys, found)
+	}
+	return keys
+}
+
+// intersect 求两个升序切片的交集
+func intersect(a, b []int) []int {
+	var result []int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// GenerateSet 由一个种子生成 count 道题，kinds 为空时使用全部题型
+func GenerateSet(seed uint64, count int, kinds []Kind) ([]Exercise, []Solution, error) {
+	if count < 1 || count > MaxExercises {
+		return nil, nil, fmt.Errorf("题目数量必须在 1~%d 之间", MaxExercises)
+	}
+	if len(kinds) == 0 {
+		kinds = Kinds
+	}
+	rng := rand.New(rand.NewPCG(seed, seed))
+	exercises := make([]Exercise, 0, count)
+	solutions := make([]Solution, 0, count)
+	for i := 0; i < count; i++ {
+		ex, sol := Generate(kinds[i%len(kinds)], uint64(rng.Uint32()))
+		exercises = append(exercises, ex)
+		solutions = append(solutions, sol)
+	}
+	return exercises, solutions, nil
+}
+
+// normalize 去掉空白并统一大小写，便于比较
+func normalize(s string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(s), ""))
+}
+
+// Grade 评分：最终答案正确得满分，否则按答对的中间步骤比例给部分分数
+func Grade(a Answer) (Result, error) {
+	kind, seed, err := ParseID(a.ID)
+	if err != nil {
+		return Result{}, err
+	}
+	ex, sol := Generate(kind, seed)
+	result := Result{ID: ex.ID, Kind: kind, Points: ex.Points}
+
+	given := normalize(a.Answer)
+	for _, accepted := range append([]string{sol.Answer}, sol.Accepted...) {
+		if given != "" && given == normalize(accepted) {
+			result.Correct = true
+		}
+	}
+
+	correctStages := 0
+	for _, stage := range sol.Stages {
+		value, ok := a.Stages[stage.Name]
+		r := StageResult{Name: stage.Name, Given: ok}
+		if ok && normalize(value) == normalize(stage.Value) {
+			r.Correct = true
+			correctStages++
+		}
+		result.Stages = append(result.Stages, r)
+	}
+
+	switch {
+	case result.Correct:
+		result.Score = float64(ex.Points)
+		result.Feedback = "答案正确"
+	case len(sol.Stages) > 0 && correctStages > 0:
+		result.Score = math.Round(float64(ex.Points)*stageWeight*float64(correctStages)/float64(len(sol.Stages))*100) / 100
+		result.Feedback = fmt.Sprintf("最终答案错误，%d/%d 个中间步骤正确", correctStages, len(sol.Stages))
+	default:
+		result.Feedback = "答案错误"
+	}
+	return result, nil
+}
@@ -0,0 +1,69 @@
+package exercise
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerateIsReproducible(t *testing.T) {
+	a, _, err := GenerateSet(42, 8, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _, _ := GenerateSet(42, 8, nil)
+	if !reflect.DeepEqual(a, b) {
+		t.Fatal("same seed produced different exercises")
+	}
+}
+
+func TestGrade(t *testing.T) {
+	exercises, solutions, err := GenerateSet(7, 4, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, ex := range exercises {
+		sol := solutions[i]
+		result, err := Grade(Answer{ID: ex.ID, Answer: sol.Answer})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Correct || result.Score != float64(ex.Points) {
+			t.Errorf("%s: correct answer graded %+v", ex.ID, result)
+		}
+		for _, accepted := range sol.Accepted {
+			if r, _ := Grade(Answer{ID: ex.ID, Answer: accepted}); !r.Correct {
+				t.Errorf("%s: accepted answer %s rejected", ex.ID, accepted)
+			}
+		}
+
+		// 最终答案错误但全部中间步骤正确时得到部分分数
+		stages := make(map[string]string)
+		for _, s := range sol.Stages {
+			stages[s.Name] = s.Value
+		}
+		result, _ = Grade(Answer{ID: ex.ID, Answer: "wrong", Stages: stages})
+		if result.Correct || result.Score != float64(ex.Points)*stageWeight {
+			t.Errorf("%s: partial credit %+v", ex.ID, result)
+		}
+	}
+}
+
+func TestSigner(t *testing.T) {
+	s, err := NewSigner([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := s.Sign("encrypt-42")
+	if id, err := s.Verify(signed); err != nil || id != "encrypt-42" {
+		t.Fatalf("Verify(%s) = %q, %v", signed, id, err)
+	}
+	// 未签名、篡改种子或使用其他密钥签名的 ID 均被拒绝
+	other, _ := NewSigner(nil)
+	_, tag, _ := strings.Cut(signed, ".")
+	for _, id := range []string{"encrypt-42", "encrypt-43." + tag, other.Sign("encrypt-42")} {
+		if _, err := s.Verify(id); err != ErrInvalidID {
+			t.Errorf("Verify(%s): got %v", id, err)
+		}
+	}
+}
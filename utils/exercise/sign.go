@@ -0,0 +1,60 @@
+package exercise
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// 题目 ID 签名
+// 题目由 ID 中的题型与种子完全确定，为防止评分接口接受自行构造的 ID，
+// 下发的 ID 附带 HMAC-SHA256 标签，形如 encrypt-12345.<标签>，评分前先校验。
+
+// ErrInvalidID 题目 ID 缺少签名或签名不匹配
+var ErrInvalidID = errors.New("题目 ID 无效或不是本服务签发的")
+
+// tagBytes 截断后的标签长度
+const tagBytes = 16
+
+// Signer 为题目 ID 签名并校验
+type Signer struct {
+	secret []byte
+}
+
+// NewSigner 创建签名器；secret 为空时生成随机密钥，签发的 ID 只在本进程内有效
+func NewSigner(secret []byte) (*Signer, error) {
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+	}
+	return &Signer{secret: secret}, nil
+}
+
+// Sign 返回带签名的题目 ID
+func (s *Signer) Sign(id string) string {
+	return id + "." + base64.RawURLEncoding.EncodeToString(s.tag(id))
+}
+
+// Verify 校验带签名的题目 ID，返回不含签名的原始 ID
+func (s *Signer) Verify(signed string) (string, error) {
+	id, tagText, ok := strings.Cut(signed, ".")
+	if !ok {
+		return "", ErrInvalidID
+	}
+	tag, err := base64.RawURLEncoding.DecodeString(tagText)
+	if err != nil || !hmac.Equal(tag, s.tag(id)) {
+		return "", ErrInvalidID
+	}
+	return id, nil
+}
+
+func (s *Signer) tag(id string) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte("exercise:" + id))
+	return h.Sum(nil)[:tagBytes]
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Encrypt、Decrypt 与 KeyExpansion 由 2 轮实例实现，结果须与教材例题一致，且在整个空间上可逆
+func TestSDESFeistelKnownAnswer(t *testing.T) {
+	key := StringToBits("1010000010", 10)
+	k1, k2 := KeyExpansion(key)
+	assertEqual(t, "k1", k1, StringToBits("10100100", 8))
+	assertEqual(t, "k2", k2, StringToBits("10010010", 8))
+	assertEqual(t, "Encrypt", Encrypt(StringToBits("10110000", 8), key), StringToBits("10111101", 8))
+	assertEqual(t, "Decrypt", Decrypt(StringToBits("10111101", 8), key), StringToBits("10110000", 8))
+
+	for k := 0; k < KeySpace; k++ {
+		key := IntTo10BitKey(k)
+		for p := 0; p < BlockSpace; p++ {
+			block := IntToBits(p, 8)
+			if back := Decrypt(Encrypt(block, key), key); !reflect.DeepEqual(back, block) {
+				t.Fatalf("key=%d plaintext=%d: decrypt got %v", k, p, back)
+			}
+		}
+	}
+}
+
+func TestSDESFeistelRoundTrip(t *testing.T) {
+	for _, rounds := range []int{1, 4, 8, 16} {
+		f, err := NewSDESFeistel(rounds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key := StringToBits("1010000010", 10)
+		for p := 0; p < BlockSpace; p++ {
+			block := IntToBits(p, 8)
+			if back := f.Decrypt(f.Encrypt(block, key), key); !reflect.DeepEqual(back, block) {
+				t.Fatalf("rounds=%d plaintext=%d: decrypt got %v", rounds, p, back)
+			}
+		}
+	}
+}
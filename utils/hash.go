@@ -0,0 +1,163 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+)
+
+// 基于 S-DES 的玩具哈希函数
+// 压缩函数由分组密码构造，再按 Merkle–Damgård 结构迭代
+// 分组 8 位、密钥 10 位：作为密钥使用的 8 位值需扩展为 10 位，见 hashKey
+
+// Construction 压缩函数构造方式
+type Construction string
+
+const (
+	// DaviesMeyer H_i = E_{m_i}(H_{i-1}) ⊕ H_{i-1}
+	DaviesMeyer Construction = "davies-meyer"
+	// MatyasMeyerOseas H_i = E_{H_{i-1}}(m_i) ⊕ m_i
+	MatyasMeyerOseas Construction = "matyas-meyer-oseas"
+	// MiyaguchiPreneel H_i = E_{H_{i-1}}(m_i) ⊕ m_i ⊕ H_{i-1}
+	MiyaguchiPreneel Construction = "miyaguchi-preneel"
+)
+
+// Constructions 所有支持的构造方式
+var Constructions = []Construction{DaviesMeyer, MatyasMeyerOseas, MiyaguchiPreneel}
+
+// 两条哈希链的初始值与密钥最低位，16 位输出由两条链拼接而成
+var (
+	hashIV    = [2]byte{0x5A, 0xC3}
+	hashTweak = [2]int{0, 1}
+)
+
+// ParseConstruction 解析构造方式名称，为空时默认 Davies–Meyer
+func ParseConstruction(name string) (Construction, error) {
+	if name == "" {
+		return DaviesMeyer, nil
+	}
+	for _, c := range Constructions {
+		if string(c) == name {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("不支持的压缩函数构造: %s", name)
+}
+
+// hashKey 将 8 位值扩展为 10 位密钥，最低位填充 tweak
+// 密钥第 2 位（十进制权重 256）不参与子密钥生成（见 EquivalentKeyGroups），
+// 因此跳过该位，否则消息中对应的一位将完全不影响哈希值
+func hashKey(v byte, tweak int) []int {
+	k := int(v&0x80)<<2 | int(v&0x7F)<<1 | tweak
+	return IntTo10BitKey(k)
+}
+
+// Compress 单次压缩函数
+func Compress(c Construction, h, m byte, tweak int) byte {
+	switch c {
+	case MatyasMeyerOseas:
+		return EncryptBlock(m, hashKey(h, tweak)) ^ m
+	case MiyaguchiPreneel:
+		return EncryptBlock(m, hashKey(h, tweak)) ^ m ^ h
+	default:
+		return EncryptBlock(h, hashKey(m, tweak)) ^ h
+	}
+}
+
+// MaxHashMessage 哈希消息的最大字节数（长度需用 1 字节编码）
+const MaxHashMessage = 255
+
+// ErrHashMessageTooLong 消息长度超过 MaxHashMessage，无法用 1 字节编码
+var ErrHashMessageTooLong = fmt.Errorf("消息长度不能超过 %d 字节", MaxHashMessage)
+
+// MDPad Merkle–Damgård 强化填充：仅追加 1 字节消息长度
+// 分组只有 1 字节，不需要 0x80 与零填充；m || len(m) 已是无后缀编码。
+// 链值只有 8 位，每个固定的填充分组都是一次非双射迭代，会明显缩小输出空间，
+// 因此不采用 SHA 式的 64 位长度字段；超过 MaxHashMessage 的消息返回 ErrHashMessageTooLong
+func MDPad(message []byte) ([]byte, error) {
+	if len(message) > MaxHashMessage {
+		return nil, ErrHashMessageTooLong
+	}
+	padded := make([]byte, 0, len(message)+1)
+	padded = append(padded, message...)
+	padded = append(padded, byte(len(message)))
+	return padded, nil
+}
+
+// HashChain 计算单条哈希链，返回每个分组处理后的链值
+func HashChain(c Construction, message []byte, lane int) ([]byte, error) {
+	padded, err := MDPad(message)
+	if err != nil {
+		return nil, err
+	}
+	chain := make([]byte, 0, len(padded))
+	h := hashIV[lane]
+	for _, m := range padded {
+		h = Compress(c, h, m, hashTweak[lane])
+		chain = append(chain, h)
+	}
+	return chain, nil
+}
+
+// Hash 计算 8 位或 16 位哈希值
+func Hash(c Construction, message []byte, bits int) (uint16, error) {
+	if bits != 8 && bits != 16 {
+		return 0, errors.New("哈希长度只能是 8 或 16 位")
+	}
+	hi, err := HashChain(c, message, 0)
+	if err != nil {
+		return 0, err
+	}
+	if bits == 8 {
+		return uint16(hi[len(hi)-1]), nil
+	}
+	lo, _ := HashChain(c, message, 1)
+	return uint16(hi[len(hi)-1])<<8 | uint16(lo[len(lo)-1]), nil
+}
+
+// CollisionResult 生日攻击结果
+type CollisionResult struct {
+	Message1 []byte
+	Message2 []byte
+	Digest   uint16
+	Trials   int
+	Expected float64 // 期望尝试次数 √(π/2 · 2^n)
+	Found    bool
+}
+
+// BirthdayBound n 位哈希找到首个碰撞的期望尝试次数
+func BirthdayBound(bits int) float64 {
+	return math.Sqrt(math.Pi / 2 * math.Exp2(float64(bits)))
+}
+
+// FindCollision 使用随机 8 字节消息进行生日攻击，seed 相同时结果可复现
+// Davies–Meyer 的最后一步以长度字节为密钥，是固定的非双射映射，实测次数通常低于理论值
+func FindCollision(c Construction, bits int, seed uint64, maxTrials int) (CollisionResult, error) {
+	if _, err := Hash(c, nil, bits); err != nil {
+		return CollisionResult{}, err
+	}
+
+	rng := rand.New(rand.NewPCG(seed, seed^0x9E3779B97F4A7C15))
+	result := CollisionResult{Expected: BirthdayBound(bits)}
+	seen := make(map[uint16][]byte)
+
+	for result.Trials < maxTrials {
+		msg := make([]byte, 8)
+		for i := range msg {
+			msg[i] = byte(rng.UintN(256))
+		}
+		result.Trials++
+
+		digest, _ := Hash(c, msg, bits)
+		if prev, ok := seen[digest]; ok && string(prev) != string(msg) {
+			result.Message1 = prev
+			result.Message2 = msg
+			result.Digest = digest
+			result.Found = true
+			return result, nil
+		}
+		seen[digest] = msg
+	}
+	return result, nil
+}
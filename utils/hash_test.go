@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// 已知答案，覆盖空消息、单字节与最大长度（255 字节）的填充边界
+func TestHashKnownAnswers(t *testing.T) {
+	long := bytes.Repeat([]byte{0xAB}, MaxHashMessage)
+	cases := []struct {
+		c       Construction
+		message []byte
+		h8      uint16
+		h16     uint16
+	}{
+		{DaviesMeyer, nil, 0x4a, 0x4a5a},
+		{DaviesMeyer, []byte("a"), 0x0f, 0x0ffb},
+		{DaviesMeyer, []byte("abc"), 0xfe, 0xfed1},
+		{DaviesMeyer, long, 0xe8, 0xe83d},
+		{MatyasMeyerOseas, nil, 0x10, 0x100e},
+		{MatyasMeyerOseas, []byte("a"), 0x3f, 0x3fed},
+		{MatyasMeyerOseas, []byte("abc"), 0x7c, 0x7c75},
+		{MatyasMeyerOseas, long, 0x15, 0x154e},
+		{MiyaguchiPreneel, nil, 0x4a, 0x4acd},
+		{MiyaguchiPreneel, []byte("a"), 0x1d, 0x1dd7},
+		{MiyaguchiPreneel, []byte("abc"), 0x8e, 0x8e43},
+		{MiyaguchiPreneel, long, 0xee, 0xee96},
+	}
+	for _, tc := range cases {
+		h8, err := Hash(tc.c, tc.message, 8)
+		if err != nil || h8 != tc.h8 {
+			t.Errorf("%s/%d 字节 8 位: %#02x, %v，应为 %#02x", tc.c, len(tc.message), h8, err, tc.h8)
+		}
+		h16, err := Hash(tc.c, tc.message, 16)
+		if err != nil || h16 != tc.h16 {
+			t.Errorf("%s/%d 字节 16 位: %#04x, %v，应为 %#04x", tc.c, len(tc.message), h16, err, tc.h16)
+		}
+		// 16 位输出的高 8 位即第一条链
+		if h16>>8 != h8 {
+			t.Errorf("%s: 16 位输出的高 8 位 %#02x 与 8 位输出 %#02x 不同", tc.c, h16>>8, h8)
+		}
+	}
+}
+
+// 按定义逐步计算单字节消息的压缩过程
+func TestCompressDefinitions(t *testing.T) {
+	h, m := hashIV[0], byte('a')
+	tweak := hashTweak[0]
+	want := map[Construction]byte{
+		DaviesMeyer:      EncryptBlock(h, hashKey(m, tweak)) ^ h,
+		MatyasMeyerOseas: EncryptBlock(m, hashKey(h, tweak)) ^ m,
+		MiyaguchiPreneel: EncryptBlock(m, hashKey(h, tweak)) ^ m ^ h,
+	}
+	for c, w := range want {
+		chain, err := HashChain(c, []byte{m}, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 消息分组之后是长度分组 0x01
+		if len(chain) != 2 || chain[0] != w || chain[1] != Compress(c, w, 1, tweak) {
+			t.Errorf("%s: 链值 %v，第一步应为 %#02x", c, chain, w)
+		}
+	}
+}
+
+func TestMDPad(t *testing.T) {
+	for _, n := range []int{0, 1, 254, MaxHashMessage} {
+		msg := bytes.Repeat([]byte{0x42}, n)
+		padded, err := MDPad(msg)
+		if err != nil || len(padded) != n+1 || !bytes.Equal(padded[:n], msg) || int(padded[n]) != n {
+			t.Errorf("MDPad(%d 字节) = %v, %v", n, padded, err)
+		}
+	}
+	// 超过 1 字节能编码的长度时明确拒绝，而不是截断长度
+	long := make([]byte, MaxHashMessage+1)
+	if _, err := MDPad(long); !errors.Is(err, ErrHashMessageTooLong) {
+		t.Errorf("MDPad(256 字节): %v", err)
+	}
+	if _, err := Hash(DaviesMeyer, long, 8); !errors.Is(err, ErrHashMessageTooLong) {
+		t.Errorf("Hash(256 字节): %v", err)
+	}
+	if _, err := HashChain(DaviesMeyer, long, 0); !errors.Is(err, ErrHashMessageTooLong) {
+		t.Errorf("HashChain(256 字节): %v", err)
+	}
+}
+
+func TestFindCollision(t *testing.T) {
+	for _, c := range Constructions {
+		for _, bits := range []int{8, 16} {
+			r, err := FindCollision(c, bits, 1, 1<<16)
+			if err != nil || !r.Found {
+				t.Fatalf("%s/%d: %+v, %v", c, bits, r, err)
+			}
+			if bytes.Equal(r.Message1, r.Message2) {
+				t.Errorf("%s/%d: 两条消息相同 %x", c, bits, r.Message1)
+			}
+			d1, _ := Hash(c, r.Message1, bits)
+			d2, _ := Hash(c, r.Message2, bits)
+			if d1 != d2 || d1 != r.Digest {
+				t.Errorf("%s/%d: 摘要 %#x %#x，结果给出 %#x", c, bits, d1, d2, r.Digest)
+			}
+		}
+	}
+	if _, err := FindCollision(DaviesMeyer, 12, 1, 10); err == nil {
+		t.Error("不支持的哈希长度应返回错误")
+	}
+}
@@ -0,0 +1,229 @@
+package history
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 操作历史记录
+// 以 JSON Lines 格式追加写入本地文件，每行一条记录；启动时读回内存用于查询与导出。
+
+// Redacted 脱敏后密钥的占位符
+const Redacted = "***"
+
+// DefaultMaxRecords 内存中保留的最近记录数，文件中的记录不受影响
+const DefaultMaxRecords = 10000
+
+// Record 一次加密、解密或暴力破解操作
+// 字段带 JSON 标签，因为它同时是文件中每一行的格式
+type Record struct {
+	ID         int64     `json:"id"`
+	Time       time.Time `json:"time"`
+	Operation  string    `json:"operation"`
+	Algorithm  string    `json:"algorithm"`
+	Mode       string    `json:"mode,omitempty"`
+	Key        string    `json:"key,omitempty"`
+	Input      string    `json:"input"`
+	Output     string    `json:"output"`
+	Keys       []string  `json:"keys,omitempty"`
+	DurationMs float64   `json:"duration_ms"`
+	Success    bool      `json:"success"`
+}
+
+// Options 存储配置
+type Options struct {
+	Path       string
+	Redact     bool // 为 true 时不保存密钥，破解出的密钥只保留数量（Output）
+	MaxRecords int
+}
+
+// Filter 查询条件，零值表示不限定
+type Filter struct {
+	Operation string
+	Algorithm string
+	Success   *bool
+	Since     time.Time
+	Until     time.Time
+	Limit     int
+	Offset    int
+}
+
+// Store 历史记录存储，并发安全
+type Store struct {
+	mu      sync.Mutex
+	opts    Options
+	file    *os.File
+	records []Record
+	nextID  int64
+}
+
+// Open 打开（或创建）历史文件并读入已有记录，无法解析的行会被跳过
+func Open(opts Options) (*Store, error) {
+	if opts.Path == "" {
+		return nil, errors.New("历史记录文件路径不能为空")
+	}
+	if opts.MaxRecords <= 0 {
+		opts.MaxRecords = DefaultMaxRecords
+	}
+	f, err := os.OpenFile(opts.Path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("打开历史记录文件失败: %w", err)
+	}
+
+	s := &Store{opts: opts, file: f, nextID: 1}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r Record
+		if json.Unmarshal(scanner.Bytes(), &r) != nil {
+			continue
+		}
+		s.append(r)
+		if r.ID >= s.nextID {
+			s.nextID = r.ID + 1
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("读取历史记录失败: %w", err)
+	}
+	return s, nil
+}
+
+// Close 关闭历史文件
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// append 追加到内存并裁剪到 MaxRecords；调用方须持有锁或处于初始化阶段
+func (s *Store) append(r Record) {
+	s.records = append(s.records, r)
+	if n := len(s.records) - s.opts.MaxRecords; n > 0 {
+		s.records = append(s.records[:0:0], s.records[n:]...)
+	}
+}
+
+// Add 分配 ID、按配置脱敏后写入文件
+func (s *Store) Add(r Record) error {
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	if s.opts.Redact {
+		if r.Key != "" {
+			r.Key = Redacted
+		}
+		if len(r.Keys) > 0 {
+			r.Keys = []string{Redacted}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r.ID = s.nextID
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("写入历史记录失败: %w", err)
+	}
+	s.nextID++
+	s.append(r)
+	return nil
+}
+
+// List 按条件查询，最新的记录在前；返回分页后的记录与符合条件的总数
+func (s *Store) List(f Filter) ([]Record, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var matched []Record
+	for i := len(s.records) - 1; i >= 0; i-- {
+		r := s.records[i]
+		if f.Operation != "" && r.Operation != f.Operation {
+			continue
+		}
+		if f.Algorithm != "" && r.Algorithm != f.Algorithm {
+			continue
+		}
+		if f.Success != nil && r.Success != *f.Success {
+			continue
+		}
+		if !f.Since.IsZero() && r.Time.Before(f.Since) {
+			continue
+		}
+		if !f.Until.IsZero() && r.Time.After(f.Until) {
+			continue
+		}
+		matched = append(matched, r)
+	}
+
+	total := len(matched)
+	if f.Offset > 0 {
+		matched = matched[min(f.Offset, total):]
+	}
+	if f.Limit > 0 && len(matched) > f.Limit {
+		matched = matched[:f.Limit]
+	}
+	return matched, total
+}
+
+// Clear 清空内存与文件中的全部记录
+func (s *Store) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.file.Truncate(0); err != nil {
+		return fmt.Errorf("清空历史记录失败: %w", err)
+	}
+	s.records = nil
+	return nil
+}
+
+// csvHeader CSV 导出的列
+var csvHeader = []string{"id", "time", "operation", "algorithm", "mode", "key", "input", "output", "keys", "duration_ms", "success"}
+
+// WriteCSV 以 CSV 格式导出记录
+func WriteCSV(w io.Writer, records []Record) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, r := range records {
+		row := []string{
+			strconv.FormatInt(r.ID, 10),
+			r.Time.Format(time.RFC3339),
+			r.Operation,
+			r.Algorithm,
+			r.Mode,
+			r.Key,
+			csvSafe(r.Input),
+			csvSafe(r.Output),
+			strings.Join(r.Keys, " "),
+			strconv.FormatFloat(r.DurationMs, 'f', 2, 64),
+			strconv.FormatBool(r.Success),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvSafe 以 = + - @ 开头的单元格前加单引号，避免表格软件将其作为公式执行
+func csvSafe(v string) string {
+	if v != "" && strings.ContainsRune("=+-@", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
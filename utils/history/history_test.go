@@ -0,0 +1,48 @@
+package history
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStorePersistsAndFilters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	s, err := Open(Options{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Add(Record{Operation: "encrypt", Algorithm: "sdes", Key: "1010000010", Input: "01110010", Output: "01110111", Success: true})
+	s.Add(Record{Operation: "blasting", Algorithm: "sdes", Input: "01110010", Output: "4", Keys: []string{"0000101010"}, Success: true})
+	s.Add(Record{Operation: "decrypt", Algorithm: "saes", Success: false})
+	s.Close()
+
+	// 重新打开后记录仍在，ID 继续递增
+	s, err = Open(Options{Path: path, Redact: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if records, total := s.List(Filter{}); total != 3 || records[0].Operation != "decrypt" {
+		t.Fatalf("reopened: total=%d records=%+v", total, records)
+	}
+	ok := true
+	if records, total := s.List(Filter{Algorithm: "sdes", Success: &ok, Limit: 1}); total != 2 || len(records) != 1 {
+		t.Fatalf("filter: total=%d len=%d", total, len(records))
+	}
+
+	s.Add(Record{Operation: "encrypt", Key: "1111111111", Keys: []string{"1111111111"}})
+	records, _ := s.List(Filter{Limit: 1})
+	if records[0].ID != 4 || records[0].Key != Redacted || records[0].Keys[0] != Redacted {
+		t.Fatalf("redacted record: %+v", records[0])
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, records); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 2 {
+		t.Fatalf("csv lines: %d", lines)
+	}
+}
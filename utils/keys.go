@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// 密钥空间相关常量
+const (
+	// KeySpace 10 位密钥空间大小
+	KeySpace = 1 << 10
+	// BlockSpace 8 位分组空间大小
+	BlockSpace = 1 << 8
+)
+
+// 密钥空间分析只遍历一次，弱密钥、等价密钥与相关密钥统计（relatedkey.go）共用其结果
+var (
+	keySpaceOnce     sync.Once
+	weakKeys         []int
+	equivalentGroups [][]int
+	redundantKeys    map[int]bool
+)
+
+// BitsToInt 将位数组（高位在前）转换为整数
+func BitsToInt(bits []int) int {
+	result := 0
+	for _, bit := range bits {
+		result = result<<1 | (bit & 1)
+	}
+	return result
+}
+
+// IntToBits 将整数转换为指定长度的位数组（高位在前）
+func IntToBits(num int, length int) []int {
+	bits := make([]int, length)
+	for i := length - 1; i >= 0; i-- {
+		bits[i] = num & 1
+		num >>= 1
+	}
+	return bits
+}
+
+// analyzeKeySpace 遍历整个密钥空间，找出弱密钥与等价密钥，并按子密钥分组供相关密钥分析使用
+// 弱密钥：k1 == k2，此时加密与解密相同，加密两次即还原明文
+// 等价密钥：子密钥 (k1, k2) 完全相同，因而对所有明文给出相同密文
+func analyzeKeySpace() {
+	groups := make(map[[2]int][]int)
+	order := make([][2]int, 0, KeySpace)
+	keysByK1 = make(map[int][]int)
+	keysByK2 = make(map[int][]int)
+
+	for k := 0; k < KeySpace; k++ {
+		k1, k2 := subkeyInts(k)
+		if k1 == k2 {
+			weakKeys = append(weakKeys, k)
+		}
+		keysByK1[k1] = append(keysByK1[k1], k)
+		keysByK2[k2] = append(keysByK2[k2], k)
+		sig := [2]int{k1, k2}
+		if _, ok := groups[sig]; !ok {
+			order = append(order, sig)
+		}
+		groups[sig] = append(groups[sig], k)
+	}
+
+	redundantKeys = make(map[int]bool)
+	for _, sig := range order {
+		keys := groups[sig]
+		if len(keys) < 2 {
+			continue
+		}
+		equivalentGroups = append(equivalentGroups, keys)
+		// 每组保留最小的密钥作为代表，其余视为冗余
+		for _, k := range keys[1:] {
+			redundantKeys[k] = true
+		}
+	}
+	analyzeRelatedKeys()
+}
+
+// WeakKeys 返回所有弱密钥（十进制）
+func WeakKeys() []int {
+	keySpaceOnce.Do(analyzeKeySpace)
+	return append([]int(nil), weakKeys...)
+}
+
+// EquivalentKeyGroups 返回所有等价密钥组，每组内密钥的子密钥完全相同
+func EquivalentKeyGroups() [][]int {
+	keySpaceOnce.Do(analyzeKeySpace)
+	result := make([][]int, len(equivalentGroups))
+	for i, g := range equivalentGroups {
+		result[i] = append([]int(nil), g...)
+	}
+	return result
+}
+
+// IsWeakKey 判断密钥是否为弱密钥
+func IsWeakKey(key int) bool {
+	keySpaceOnce.Do(analyzeKeySpace)
+	for _, k := range weakKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRedundantKey 判断密钥是否与更小的密钥等价
+func IsRedundantKey(key int) bool {
+	keySpaceOnce.Do(analyzeKeySpace)
+	return redundantKeys[key]
+}
+
+// randomInt 使用 crypto/rand 生成 [0, n) 范围内的随机整数
+func randomInt(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("生成随机数失败: %w", err)
+	}
+	return int(v.Int64()), nil
+}
+
+// RandomKey 生成随机 10 位密钥（十进制）
+// excludeWeak 为 true 时跳过弱密钥以及等价组中的冗余密钥
+func RandomKey(excludeWeak bool) (int, error) {
+	for {
+		k, err := randomInt(KeySpace)
+		if err != nil {
+			return 0, err
+		}
+		if excludeWeak && (IsWeakKey(k) || IsRedundantKey(k)) {
+			continue
+		}
+		return k, nil
+	}
+}
+
+// RandomBits 生成 bits 位随机整数
+func RandomBits(bits int) (int, error) {
+	return randomInt(1 << bits)
+}
+
+// RandomBlock 生成随机 8 位分组（十进制），可用作 IV
+func RandomBlock() (int, error) {
+	return randomInt(BlockSpace)
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWeakKeys(t *testing.T) {
+	weak := WeakKeys()
+	if len(weak) == 0 {
+		t.Fatal("S-DES 应存在弱密钥")
+	}
+	for _, k := range weak {
+		key := IntTo10BitKey(k)
+		k1, k2 := KeyExpansion(key)
+		if !slices.Equal(k1, k2) {
+			t.Errorf("弱密钥 %d 的子密钥不同: %v %v", k, k1, k2)
+		}
+		// 加密两次还原明文
+		for p := 0; p < BlockSpace; p++ {
+			block := IntToBits(p, 8)
+			if got := Encrypt(Encrypt(block, key), key); !slices.Equal(got, block) {
+				t.Fatalf("弱密钥 %d 加密两次未还原 %08b", k, p)
+			}
+		}
+		if !IsWeakKey(k) {
+			t.Errorf("IsWeakKey(%d) = false", k)
+		}
+	}
+	for _, k := range []int{0, KeySpace - 1} {
+		if !slices.Contains(weak, k) {
+			t.Errorf("全 0 / 全 1 密钥 %d 应为弱密钥", k)
+		}
+	}
+}
+
+func TestEquivalentKeyGroups(t *testing.T) {
+	groups := EquivalentKeyGroups()
+	seen := make(map[int]bool)
+	for _, g := range groups {
+		if len(g) < 2 || !slices.IsSorted(g) {
+			t.Fatalf("等价组 %v 应至少含两个密钥且升序", g)
+		}
+		k1, k2 := KeyExpansion(IntTo10BitKey(g[0]))
+		for i, k := range g {
+			if seen[k] {
+				t.Fatalf("密钥 %d 出现在多个等价组中", k)
+			}
+			seen[k] = true
+			o1, o2 := KeyExpansion(IntTo10BitKey(k))
+			if !slices.Equal(o1, k1) || !slices.Equal(o2, k2) {
+				t.Errorf("组 %v 中 %d 的子密钥不同", g, k)
+			}
+			if IsRedundantKey(k) != (i > 0) {
+				t.Errorf("IsRedundantKey(%d) 应只对组内非最小密钥成立", k)
+			}
+		}
+	}
+	// 等价组与相关密钥统计来自同一次遍历
+	pairs := 0
+	for _, g := range groups {
+		pairs += len(g) * (len(g) - 1) / 2
+	}
+	if got := RelatedKeys().EquivalentPairs; got != pairs {
+		t.Errorf("EquivalentPairs = %d，等价组给出 %d", got, pairs)
+	}
+}
+
+func TestRandomKey(t *testing.T) {
+	for i := 0; i < 2000; i++ {
+		k, err := RandomKey(true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if k < 0 || k >= KeySpace || IsWeakKey(k) || IsRedundantKey(k) {
+			t.Fatalf("RandomKey(true) = %d", k)
+		}
+	}
+	seen := make(map[int]bool)
+	for i := 0; i < 2000; i++ {
+		k, err := RandomKey(false)
+		if err != nil || k < 0 || k >= KeySpace {
+			t.Fatalf("RandomKey(false) = %d, %v", k, err)
+		}
+		seen[k] = true
+	}
+	// 2000 次抽样覆盖 1024 个密钥中的大部分
+	if len(seen) < KeySpace/2 {
+		t.Errorf("RandomKey 只产生了 %d 个不同的密钥", len(seen))
+	}
+}
@@ -0,0 +1,56 @@
+package utils
+
+// 密钥扩展检查器：记录 P10、每次移位后的左右 5 位，以及由子密钥反推密钥
+
+// ScheduleShift 循环左移后的左右两半，LS^n 表示累计左移 n 位
+type ScheduleShift struct {
+	Name  string
+	Left  []int
+	Right []int
+}
+
+// ScheduleTrace 密钥扩展的全部中间值
+type ScheduleTrace struct {
+	P10    []int
+	Shifts []ScheduleShift
+	K1     []int
+	K2     []int
+}
+
+// KeyScheduleTrace 记录 SDES 实例密钥扩展的中间值
+func KeyScheduleTrace(key []int) ScheduleTrace {
+	var trace ScheduleTrace
+	subkeys := SDESKeySchedule(key, SDES.Rounds, func(name string, bits []int) {
+		if name == "P10" {
+			trace.P10 = bits
+			return
+		}
+		trace.Shifts = append(trace.Shifts, ScheduleShift{Name: name, Left: bits[:5], Right: bits[5:]})
+	})
+	trace.K1, trace.K2 = subkeys[0], subkeys[1]
+	return trace
+}
+
+// KeysForSubkeys 枚举产生给定子密钥的所有 10 位密钥（升序）
+// k1、k2 为负数时表示不限定；P8 丢弃 2 位，因此单个子密钥对应 4 个密钥
+func KeysForSubkeys(k1, k2 int) []int {
+	keySpaceOnce.Do(analyzeKeySpace)
+	var candidates []int
+	switch {
+	case k1 >= 0:
+		candidates = keysByK1[k1]
+	case k2 >= 0:
+		candidates = keysByK2[k2]
+	default:
+		return nil
+	}
+
+	keys := make([]int, 0, len(candidates))
+	for _, k := range candidates {
+		c1, c2 := subkeyInts(k)
+		if (k1 < 0 || c1 == k1) && (k2 < 0 || c2 == k2) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
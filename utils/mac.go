@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"crypto/subtle"
+	"errors"
+)
+
+// 基于 S-DES 分组的消息认证码
+// 分组长度为 8 位，因此标签最长也只有 8 位，仅用于教学演示
+
+// MACTagBits 完整标签长度（位）
+const MACTagBits = 8
+
+// cmacRb GF(2^8) 上的约化常数，对应不可约多项式 x^8 + x^4 + x^3 + x + 1
+const cmacRb = 0x1B
+
+var (
+	// ErrTagMismatch 认证标签校验失败
+	ErrTagMismatch = errors.New("消息认证失败：标签不匹配")
+	// ErrInvalidTagBits 标签长度不在 1~8 位范围内
+	ErrInvalidTagBits = errors.New("标签长度必须在 1~8 位之间")
+)
+
+// EncryptBlock 使用 S-DES 加密单个字节
+func EncryptBlock(b byte, key []int) byte {
+	return BitsToByte(Encrypt(ByteToBits(b), key))
+}
+
+// DecryptBlock 使用 S-DES 解密单个字节
+func DecryptBlock(b byte, key []int) byte {
+	return BitsToByte(Decrypt(ByteToBits(b), key))
+}
+
+// CBCMAC 计算原始 CBC-MAC（IV 为 0，取最后一个密文分组）
+// 注意：原始 CBC-MAC 仅对定长消息安全，变长消息应使用 CMAC
+func CBCMAC(message []byte, key []int) byte {
+	var state byte
+	for _, b := range message {
+		state = EncryptBlock(state^b, key)
+	}
+	return state
+}
+
+// doubleBlock GF(2^8) 上乘以 x
+func doubleBlock(b byte) byte {
+	if b&0x80 != 0 {
+		return b<<1 ^ cmacRb
+	}
+	return b << 1
+}
+
+// CMACSubkeys 按 NIST SP 800-38B 生成 CMAC 子密钥 K1、K2
+func CMACSubkeys(key []int) (byte, byte) {
+	l := EncryptBlock(0, key)
+	k1 := doubleBlock(l)
+	k2 := doubleBlock(k1)
+	return k1, k2
+}
+
+// CMAC 计算 CMAC 标签
+// 分组长度为 1 字节，非空消息总是完整分组，只有空消息需要填充
+func CMAC(message []byte, key []int) byte {
+	k1, k2 := CMACSubkeys(key)
+	if len(message) == 0 {
+		// 填充 10000000 后与 K2 异或
+		return EncryptBlock(0x80^k2, key)
+	}
+
+	var state byte
+	last := len(message) - 1
+	for _, b := range message[:last] {
+		state = EncryptBlock(state^b, key)
+	}
+	return EncryptBlock(state^message[last]^k1, key)
+}
+
+// TruncateTag 截取标签的高 bits 位
+func TruncateTag(tag byte, bits int) byte {
+	return tag >> (MACTagBits - bits)
+}
+
+// Seal 先加密后认证（Encrypt-then-MAC）
+// 使用 encKey 逐字节加密，再使用独立的 macKey 对密文计算 CMAC
+func Seal(plaintext []byte, encKey, macKey []int) ([]byte, byte) {
+	ciphertext := EncryptBytes(plaintext, encKey)
+	return ciphertext, CMAC(ciphertext, macKey)
+}
+
+// VerifyTag 以常量时间比较截断后的标签，tag 为 tagBits 位的截断标签
+func VerifyTag(ciphertext []byte, tag byte, tagBits int, macKey []int) error {
+	if tagBits < 1 || tagBits > MACTagBits {
+		return ErrInvalidTagBits
+	}
+	expected := TruncateTag(CMAC(ciphertext, macKey), tagBits)
+	if subtle.ConstantTimeByteEq(expected, tag) != 1 {
+		return ErrTagMismatch
+	}
+	return nil
+}
+
+// Open 先校验标签再解密，标签不匹配时返回 ErrTagMismatch 且不输出明文
+func Open(ciphertext []byte, tag byte, encKey, macKey []int) ([]byte, error) {
+	if err := VerifyTag(ciphertext, tag, MACTagBits, macKey); err != nil {
+		return nil, err
+	}
+	return DecryptBytes(ciphertext, encKey), nil
+}
+
+// ForgeryResult 标签暴力伪造结果
+type ForgeryResult struct {
+	Ciphertext []byte // 篡改后的密文
+	Tag        byte   // 被验证方接受的标签（已截断）
+	Attempts   int    // 向验证方提交的次数
+	Expected   int    // 理论期望次数 2^(bits-1)
+}
+
+// ForgeTag 演示短标签可被暴力伪造：
+// 攻击者篡改密文后，依次尝试所有可能的 tagBits 位标签，直到验证方接受
+func ForgeTag(ciphertext []byte, tagBits int, verify func([]byte, byte) bool) (ForgeryResult, error) {
+	if tagBits < 1 || tagBits > MACTagBits {
+		return ForgeryResult{}, ErrInvalidTagBits
+	}
+
+	forged := append([]byte(nil), ciphertext...)
+	if len(forged) == 0 {
+		forged = append(forged, 0)
+	}
+	// 翻转首字节最低位，构造一条从未被认证过的消息
+	forged[0] ^= 1
+
+	result := ForgeryResult{
+		Ciphertext: forged,
+		Expected:   1 << (tagBits - 1),
+	}
+	for t := 0; t < 1<<tagBits; t++ {
+		result.Attempts++
+		if verify(forged, byte(t)) {
+			result.Tag = byte(t)
+			return result, nil
+		}
+	}
+	return result, ErrTagMismatch
+}
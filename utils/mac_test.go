@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSealOpen(t *testing.T) {
+	encKey := StringToBits("1010000010", 10)
+	macKey := StringToBits("0111111101", 10)
+	plaintext := []byte("Iloveyou")
+
+	ciphertext, tag := Seal(plaintext, encKey, macKey)
+	got, err := Open(ciphertext, tag, encKey, macKey)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if string(got) != string(plaintext) {
+		t.Errorf("Open: got %q, want %q", got, plaintext)
+	}
+
+	// 篡改密文后必须校验失败
+	tampered := append([]byte(nil), ciphertext...)
+	tampered[0] ^= 1
+	if _, err := Open(tampered, tag, encKey, macKey); !errors.Is(err, ErrTagMismatch) {
+		t.Errorf("Open(tampered): got %v, want ErrTagMismatch", err)
+	}
+}
+
+func TestForgeTag(t *testing.T) {
+	macKey := StringToBits("0111111101", 10)
+	ciphertext := []byte("abc")
+
+	for bits := 1; bits <= MACTagBits; bits++ {
+		verify := func(ct []byte, tag byte) bool {
+			return VerifyTag(ct, tag, bits, macKey) == nil
+		}
+		result, err := ForgeTag(ciphertext, bits, verify)
+		if err != nil {
+			t.Fatalf("ForgeTag(%d bits): %v", bits, err)
+		}
+		if result.Attempts > 1<<bits {
+			t.Errorf("ForgeTag(%d bits): %d attempts exceeds tag space", bits, result.Attempts)
+		}
+		if !verify(result.Ciphertext, result.Tag) {
+			t.Errorf("ForgeTag(%d bits): forged tag rejected", bits)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -70,3 +71,48 @@ func TestSDES_AssignmentVersion(t *testing.T) {
 	out := Permute(append(L3, R3...), IPInverse[:])
 	assertEqual(t, "Ciphertext", out, []int{1, 0, 1, 1, 1, 1, 0, 1}) // 10111101
 }
+
+func TestEncryptTrace(t *testing.T) {
+	K := StringToBits("1010000010", 10)
+	P := StringToBits("10110000", 8)
+
+	out, steps := EncryptTrace(P, K)
+	assertEqual(t, "Ciphertext", out, Encrypt(P, K))
+
+	want := map[string]string{
+		"k1":     "10100100",
+		"k2":     "10010010",
+		"IP":     "00111000",
+		"R1.XOR": "11100101",
+		"R1.L":   "1110",
+		"R2.XOR": "11101111",
+		"R2.L":   "0111",
+		"IP^-1":  "10111101",
+	}
+	for _, s := range steps {
+		if v, ok := want[s.Name]; ok && v != s.Value {
+			t.Errorf("%s: got %s, want %s", s.Name, s.Value, v)
+		}
+	}
+
+	// 步骤由 Feistel 引擎按执行顺序产生
+	var names []string
+	for _, s := range steps {
+		names = append(names, s.Name)
+	}
+	wantNames := "k1 k2 IP R1.EP R1.XOR R1.SBox R1.P4 R1.L SW R2.EP R2.XOR R2.SBox R2.P4 R2.L IP^-1"
+	if got := strings.Join(names, " "); got != wantNames {
+		t.Errorf("步骤: got %s, want %s", got, wantNames)
+	}
+
+	back, _ := DecryptTrace(out, K)
+	assertEqual(t, "Decrypt", back, P)
+
+	schedule := KeyScheduleTrace(K)
+	assertEqual(t, "P10", schedule.P10, StringToBits("1000001100", 10))
+	if len(schedule.Shifts) != 2 || schedule.Shifts[0].Name != "LS^1" || schedule.Shifts[1].Name != "LS^2" {
+		t.Errorf("移位步骤: %+v", schedule.Shifts)
+	}
+	assertEqual(t, "LS^1.L", schedule.Shifts[0].Left, StringToBits("00001", 5))
+	assertEqual(t, "trace k2", schedule.K2, StringToBits("10010010", 8))
+}
@@ -0,0 +1,290 @@
+package oracle
+
+import (
+	"SDES/utils"
+	"SDES/utils/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+// 选择明文 / 选择密文预言机挑战
+// 服务端为每个会话保存一个随机密钥，参与者在查询预算内调用加密、解密预言机，最后提交猜测的密钥。
+// 密钥只保存在未导出的会话结构中，对外只返回不含密钥的 Snapshot。
+
+var (
+	ErrSessionNotFound   = errors.New("会话不存在或已过期")
+	ErrTooManySessions   = errors.New("活跃会话过多，请稍后再试")
+	ErrBudgetExhausted   = errors.New("查询次数已用完")
+	ErrAttemptsExhausted = errors.New("提交次数已用完")
+	ErrAlreadySolved     = errors.New("该会话已成功破解")
+)
+
+// Config 预言机配置
+type Config struct {
+	TTL           time.Duration // 会话有效期，从创建时开始计算
+	EncryptBudget int           // 每个会话允许的加密查询次数
+	DecryptBudget int           // 每个会话允许的解密查询次数
+	MaxAttempts   int           // 允许提交密钥的次数
+	MaxSessions   int           // 同时存在的会话上限
+}
+
+// DefaultConfig 默认配置
+var DefaultConfig = Config{
+	TTL:           30 * time.Minute,
+	EncryptBudget: 16,
+	DecryptBudget: 16,
+	MaxAttempts:   3,
+	MaxSessions:   1024,
+}
+
+// 得分规则：破解成功得 100 分，每次查询扣 2 分，每次错误提交扣 10 分，最低 10 分
+const (
+	baseScore    = 100
+	queryPenalty = 2
+	wrongPenalty = 10
+	minScore     = 10
+)
+
+// Snapshot 会话的公开状态，不包含密钥
+type Snapshot struct {
+	ID            string
+	Algorithm     string
+	BlockBits     int
+	KeyBits       int
+	EncryptLeft   int
+	DecryptLeft   int
+	AttemptsLeft  int
+	Queries       int
+	WrongAttempts int
+	Solved        bool
+	Score         int
+	ExpiresAt     time.Time
+}
+
+type session struct {
+	id            string
+	alg           cipher.Cipher
+	key           []int
+	encryptUsed   int
+	decryptUsed   int
+	wrongAttempts int
+	solved        bool
+	score         int
+	expiresAt     time.Time
+}
+
+// Store 内存中的会话存储，并发安全
+type Store struct {
+	mu       sync.Mutex
+	cfg      Config
+	sessions map[string]*session
+	now      func() time.Time
+}
+
+// NewStore 创建会话存储
+func NewStore(cfg Config) *Store {
+	return &Store{
+		cfg:      cfg,
+		sessions: make(map[string]*session),
+		now:      time.Now,
+	}
+}
+
+// Config 返回存储使用的配置
+func (s *Store) Config() Config {
+	return s.cfg
+}
+
+// newSessionID 生成 128 位随机会话 ID
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Create 为指定算法创建会话并生成随机密钥
+func (s *Store) Create(alg cipher.Cipher) (Snapshot, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return Snapshot{}, err
+	}
+	k, err := utils.RandomBits(alg.KeyBits())
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep()
+	if len(s.sessions) >= s.cfg.MaxSessions {
+		return Snapshot{}, ErrTooManySessions
+	}
+	sess := &session{
+		id:        id,
+		alg:       alg,
+		key:       utils.IntToBits(k, alg.KeyBits()),
+		expiresAt: s.now().Add(s.cfg.TTL),
+	}
+	s.sessions[id] = sess
+	return s.snapshot(sess), nil
+}
+
+// Get 返回会话状态
+func (s *Store) Get(id string) (Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, err := s.lookup(id)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return s.snapshot(sess), nil
+}
+
+// Encrypt 加密预言机，消耗一次加密预算
+func (s *Store) Encrypt(id string, plaintext []int) ([]int, Snapshot, error) {
+	return s.query(id, plaintext, true)
+}
+
+// Decrypt 解密预言机，消耗一次解密预算
+func (s *Store) Decrypt(id string, ciphertext []int) ([]int, Snapshot, error) {
+	return s.query(id, ciphertext, false)
+}
+
+func (s *Store) query(id string, block []int, encrypt bool) ([]int, Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, err := s.lookup(id)
+	if err != nil {
+		return nil, Snapshot{}, err
+	}
+	if sess.solved {
+		return nil, s.snapshot(sess), ErrAlreadySolved
+	}
+
+	var result []int
+	if encrypt {
+		if sess.encryptUsed >= s.cfg.EncryptBudget {
+			return nil, s.snapshot(sess), ErrBudgetExhausted
+		}
+		sess.encryptUsed++
+		result = sess.alg.Encrypt(block, sess.key)
+	} else {
+		if sess.decryptUsed >= s.cfg.DecryptBudget {
+			return nil, s.snapshot(sess), ErrBudgetExhausted
+		}
+		sess.decryptUsed++
+		result = sess.alg.Decrypt(block, sess.key)
+	}
+	return result, s.snapshot(sess), nil
+}
+
+// Submit 提交猜测的密钥；与会话密钥等价（对所有分组加密结果相同）的密钥同样视为正确
+// 等价判断需要加密整个分组空间，在锁外进行，完成后重新检查会话状态再计分
+func (s *Store) Submit(id string, key []int) (bool, Snapshot, error) {
+	s.mu.Lock()
+	sess, err := s.checkSubmit(id)
+	if err != nil {
+		s.mu.Unlock()
+		return false, s.snapshotOf(sess), err
+	}
+	alg, sessionKey := sess.alg, sess.key
+	s.mu.Unlock()
+
+	ok := equivalent(alg, sessionKey, key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// 比较期间会话可能已过期、被其他请求破解或用完提交次数
+	if sess, err = s.checkSubmit(id); err != nil {
+		return false, s.snapshotOf(sess), err
+	}
+	if !ok {
+		sess.wrongAttempts++
+		return false, s.snapshot(sess), nil
+	}
+	sess.solved = true
+	sess.score = max(minScore, baseScore-queryPenalty*(sess.encryptUsed+sess.decryptUsed)-wrongPenalty*sess.wrongAttempts)
+	return true, s.snapshot(sess), nil
+}
+
+// checkSubmit 查找会话并检查是否还能提交；会话不存在时返回的会话为 nil。调用方须持有锁
+func (s *Store) checkSubmit(id string) (*session, error) {
+	sess, err := s.lookup(id)
+	if err != nil {
+		return nil, err
+	}
+	if sess.solved {
+		return sess, ErrAlreadySolved
+	}
+	if sess.wrongAttempts >= s.cfg.MaxAttempts {
+		return sess, ErrAttemptsExhausted
+	}
+	return sess, nil
+}
+
+// equivalent 判断两个密钥是否在整个分组空间上给出相同的加密结果
+func equivalent(alg cipher.Cipher, a, b []int) bool {
+	if utils.BitsToInt(a) == utils.BitsToInt(b) {
+		return true
+	}
+	for p := 0; p < 1<<alg.BlockBits(); p++ {
+		block := utils.IntToBits(p, alg.BlockBits())
+		if utils.BitsToInt(alg.Encrypt(block, a)) != utils.BitsToInt(alg.Encrypt(block, b)) {
+			return false
+		}
+	}
+	return true
+}
+
+// lookup 查找未过期的会话，过期会话顺便删除；调用方须持有锁
+func (s *Store) lookup(id string) (*session, error) {
+	sess, ok := s.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if s.now().After(sess.expiresAt) {
+		delete(s.sessions, id)
+		return nil, ErrSessionNotFound
+	}
+	return sess, nil
+}
+
+// sweep 删除所有过期会话；调用方须持有锁
+func (s *Store) sweep() {
+	now := s.now()
+	for id, sess := range s.sessions {
+		if now.After(sess.expiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+// snapshotOf 与 snapshot 相同，会话为 nil 时返回空 Snapshot
+func (s *Store) snapshotOf(sess *session) Snapshot {
+	if sess == nil {
+		return Snapshot{}
+	}
+	return s.snapshot(sess)
+}
+
+func (s *Store) snapshot(sess *session) Snapshot {
+	return Snapshot{
+		ID:            sess.id,
+		Algorithm:     sess.alg.Name(),
+		BlockBits:     sess.alg.BlockBits(),
+		KeyBits:       sess.alg.KeyBits(),
+		EncryptLeft:   s.cfg.EncryptBudget - sess.encryptUsed,
+		DecryptLeft:   s.cfg.DecryptBudget - sess.decryptUsed,
+		AttemptsLeft:  s.cfg.MaxAttempts - sess.wrongAttempts,
+		Queries:       sess.encryptUsed + sess.decryptUsed,
+		WrongAttempts: sess.wrongAttempts,
+		Solved:        sess.solved,
+		Score:         sess.score,
+		ExpiresAt:     sess.expiresAt,
+	}
+}
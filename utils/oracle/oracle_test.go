@@ -0,0 +1,100 @@
+package oracle
+
+import (
+	"SDES/utils"
+	"SDES/utils/cipher"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOracleSession(t *testing.T) {
+	alg, err := cipher.Lookup("sdes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{TTL: time.Minute, EncryptBudget: 2, DecryptBudget: 1, MaxAttempts: 2, MaxSessions: 4}
+	store := NewStore(cfg)
+	snap, err := store.Create(alg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := store.sessions[snap.ID].key
+
+	block := utils.IntToBits(0x41, 8)
+	for i := 0; i < cfg.EncryptBudget; i++ {
+		ct, _, err := store.Encrypt(snap.ID, block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := utils.BitsToInt(ct); got != utils.BitsToInt(utils.Encrypt(block, key)) {
+			t.Fatalf("encrypt oracle returned %08b", got)
+		}
+	}
+	if _, _, err := store.Encrypt(snap.ID, block); err != ErrBudgetExhausted {
+		t.Fatalf("over budget: got %v", err)
+	}
+
+	// 错误提交扣分，等价密钥（翻转未使用的第 2 位）视为正确
+	wrong := utils.IntToBits(utils.BitsToInt(key)^1, 10)
+	if ok, _, err := store.Submit(snap.ID, wrong); ok || err != nil {
+		t.Fatalf("wrong key: ok=%v err=%v", ok, err)
+	}
+	equivalentKey := utils.IntToBits(utils.BitsToInt(key)^0x100, 10)
+	ok, snap, err := store.Submit(snap.ID, equivalentKey)
+	if !ok || err != nil {
+		t.Fatalf("equivalent key: ok=%v err=%v", ok, err)
+	}
+	if want := baseScore - queryPenalty*2 - wrongPenalty; snap.Score != want {
+		t.Fatalf("score: got %d, want %d", snap.Score, want)
+	}
+	if _, _, err := store.Submit(snap.ID, key); err != ErrAlreadySolved {
+		t.Fatalf("after solve: got %v", err)
+	}
+}
+
+func TestOracleTTL(t *testing.T) {
+	alg, _ := cipher.Lookup("sdes")
+	store := NewStore(Config{TTL: time.Minute, EncryptBudget: 1, DecryptBudget: 1, MaxAttempts: 1, MaxSessions: 1})
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	snap, err := store.Create(alg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Create(alg); err != ErrTooManySessions {
+		t.Fatalf("session limit: got %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := store.Get(snap.ID); err != ErrSessionNotFound {
+		t.Fatalf("expired session: got %v", err)
+	}
+	if _, err := store.Create(alg); err != nil {
+		t.Fatalf("create after expiry: %v", err)
+	}
+}
+
+// 并发提交时等价判断在锁外进行，计分前重新检查，错误次数不会超过上限
+func TestOracleConcurrentSubmit(t *testing.T) {
+	alg, _ := cipher.Lookup("sdes")
+	cfg := Config{TTL: time.Minute, EncryptBudget: 1, DecryptBudget: 1, MaxAttempts: 3, MaxSessions: 1}
+	store := NewStore(cfg)
+	snap, _ := store.Create(alg)
+	wrong := utils.IntToBits(utils.BitsToInt(store.sessions[snap.ID].key)^1, 10)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store.Submit(snap.ID, wrong)
+		}()
+	}
+	wg.Wait()
+	snap, _ = store.Get(snap.ID)
+	if snap.WrongAttempts != cfg.MaxAttempts || snap.AttemptsLeft != 0 {
+		t.Fatalf("wrong attempts: got %d, left %d", snap.WrongAttempts, snap.AttemptsLeft)
+	}
+}
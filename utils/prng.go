@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+)
+
+// 基于 S-DES 的伪随机数生成器
+// 分组只有 8 位，CTR 模式周期固定为 256 字节，OFB 模式周期等于 E_k 置换中 IV 所在轮换的长度
+
+// PRNGMode 伪随机数生成器工作模式
+type PRNGMode string
+
+const (
+	// PRNGModeCTR 输出 E_k(IV + i)
+	PRNGModeCTR PRNGMode = "ctr"
+	// PRNGModeOFB 输出 O_i = E_k(O_{i-1})，O_0 = IV
+	PRNGModeOFB PRNGMode = "ofb"
+)
+
+// PRNG S-DES 伪随机数生成器，实现 io.Reader 与 rand.Source
+type PRNG struct {
+	key   []int
+	mode  PRNGMode
+	iv    byte
+	state byte
+}
+
+var (
+	_ io.Reader   = (*PRNG)(nil)
+	_ rand.Source = (*PRNG)(nil)
+)
+
+// NewPRNG 创建伪随机数生成器
+func NewPRNG(key []int, iv byte, mode PRNGMode) (*PRNG, error) {
+	if mode != PRNGModeCTR && mode != PRNGModeOFB {
+		return nil, fmt.Errorf("不支持的 PRNG 模式: %s", mode)
+	}
+	return &PRNG{key: key, mode: mode, iv: iv, state: iv}, nil
+}
+
+// nextByte 生成下一个输出字节
+func (p *PRNG) nextByte() byte {
+	if p.mode == PRNGModeCTR {
+		out := EncryptBlock(p.state, p.key)
+		p.state++
+		return out
+	}
+	p.state = EncryptBlock(p.state, p.key)
+	return p.state
+}
+
+// Read 实现 io.Reader，总是填满 buf
+func (p *PRNG) Read(buf []byte) (int, error) {
+	for i := range buf {
+		buf[i] = p.nextByte()
+	}
+	return len(buf), nil
+}
+
+// Uint64 实现 rand.Source，按大端顺序拼接 8 个输出字节
+func (p *PRNG) Uint64() uint64 {
+	var v uint64
+	for i := 0; i < 8; i++ {
+		v = v<<8 | uint64(p.nextByte())
+	}
+	return v
+}
+
+// Period 返回输出序列的理论周期（字节）
+func (p *PRNG) Period() int {
+	if p.mode == PRNGModeCTR {
+		return BlockSpace
+	}
+	// OFB：沿置换迭代直到回到 IV
+	period := 1
+	for s := EncryptBlock(p.iv, p.key); s != p.iv; s = EncryptBlock(s, p.key) {
+		period++
+	}
+	return period
+}
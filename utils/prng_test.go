@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPRNGDeterministic(t *testing.T) {
+	key := StringToBits("1010000010", 10)
+	for _, mode := range []PRNGMode{PRNGModeCTR, PRNGModeOFB} {
+		a, _ := NewPRNG(key, 0x3C, mode)
+		b, _ := NewPRNG(key, 0x3C, mode)
+		x, y := make([]byte, 1024), make([]byte, 1024)
+		a.Read(x)
+		b.Read(y)
+		if !bytes.Equal(x, y) {
+			t.Errorf("%s: 相同密钥与 IV 的输出不同", mode)
+		}
+		c, _ := NewPRNG(key, 0x3D, mode)
+		z := make([]byte, 1024)
+		c.Read(z)
+		if bytes.Equal(x, z) {
+			t.Errorf("%s: 不同 IV 的输出相同", mode)
+		}
+		// 输出以理论周期重复
+		if got := DetectPeriod(x); got != a.Period() {
+			t.Errorf("%s: DetectPeriod = %d，Period = %d", mode, got, a.Period())
+		}
+	}
+	if _, err := NewPRNG(key, 0, "cfb"); err == nil {
+		t.Error("不支持的模式应返回错误")
+	}
+}
+
+func TestPRNGModes(t *testing.T) {
+	key := StringToBits("0111111101", 10)
+	ctr, _ := NewPRNG(key, 0xFE, PRNGModeCTR)
+	out := make([]byte, 3)
+	ctr.Read(out)
+	// CTR：E_k(IV + i)，计数器按字节回绕
+	for i, want := range []byte{EncryptBlock(0xFE, key), EncryptBlock(0xFF, key), EncryptBlock(0x00, key)} {
+		if out[i] != want {
+			t.Errorf("CTR 第 %d 字节 = %#02x，应为 %#02x", i, out[i], want)
+		}
+	}
+	if ctr.Period() != BlockSpace {
+		t.Errorf("CTR 周期 = %d", ctr.Period())
+	}
+
+	ofb, _ := NewPRNG(key, 0x00, PRNGModeOFB)
+	ofb.Read(out)
+	o1 := EncryptBlock(0x00, key)
+	o2 := EncryptBlock(o1, key)
+	if out[0] != o1 || out[1] != o2 || out[2] != EncryptBlock(o2, key) {
+		t.Errorf("OFB 输出 %x", out)
+	}
+	// Uint64 按大端拼接后续 8 个字节
+	a, _ := NewPRNG(key, 0x42, PRNGModeOFB)
+	b, _ := NewPRNG(key, 0x42, PRNGModeOFB)
+	buf := make([]byte, 8)
+	b.Read(buf)
+	var want uint64
+	for _, x := range buf {
+		want = want<<8 | uint64(x)
+	}
+	if got := a.Uint64(); got != want {
+		t.Errorf("Uint64 = %#x，应为 %#x", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package utils
+
+import "testing"
+
+// 密钥扩展是线性的，子密钥差分与具体密钥无关
+func TestSubkeyDiffIsKeyIndependent(t *testing.T) {
+	for delta := 1; delta < KeySpace; delta++ {
+		want := SubkeyDiff(delta)
+		for k := 0; k < KeySpace; k += 37 {
+			a1, a2 := subkeyInts(k)
+			b1, b2 := subkeyInts(k ^ delta)
+			if a1^b1 != want.K1Diff || a2^b2 != want.K2Diff {
+				t.Fatalf("delta=%d key=%d: got (%d, %d), want (%d, %d)", delta, k, a1^b1, a2^b2, want.K1Diff, want.K2Diff)
+			}
+		}
+	}
+}
+
+func TestSlideAttack(t *testing.T) {
+	for _, rounds := range []int{4, 64} {
+		f, err := NewSlideFeistel(rounds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		const secret = 0b10110110
+		result, err := SlideAttack(f, secret, 64, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Recovered || result.RecoveredKey != secret {
+			t.Fatalf("rounds=%d: %+v", rounds, result)
+		}
+	}
+}
+
+func TestKeysForSubkeys(t *testing.T) {
+	for k := 0; k < KeySpace; k += 7 {
+		trace := KeyScheduleTrace(IntTo10BitKey(k))
+		k1, k2 := subkeyInts(k)
+		if BitsToInt(trace.K1) != k1 || BitsToInt(trace.K2) != k2 {
+			t.Fatalf("key=%d: trace subkeys differ from KeyExpansion", k)
+		}
+		found := false
+		for _, c := range KeysForSubkeys(k1, k2) {
+			if c1, c2 := subkeyInts(c); c1 != k1 || c2 != k2 {
+				t.Fatalf("key=%d: candidate %d has wrong subkeys", k, c)
+			}
+			found = found || c == k
+		}
+		if !found {
+			t.Fatalf("key=%d: not among candidates", k)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package saes
+
+import (
+	"SDES/utils"
+	"errors"
+	"fmt"
+)
+
+// S-AES 算法实现（Musa、Schaefer、Wedig 简化 AES）
+// 分组长度：16-bit
+// 密钥长度：16-bit
+// 状态为 2×2 半字节矩阵（按列排列），共 2 轮，运算在 GF(2^4) 上进行，模多项式 x^4 + x + 1
+
+const (
+	// BlockBits 分组长度
+	BlockBits = 16
+	// KeyBits 密钥长度
+	KeyBits = 16
+)
+
+var (
+	// SBox 半字节代替盒
+	SBox = [16]uint16{0x9, 0x4, 0xA, 0xB, 0xD, 0x1, 0x8, 0x5, 0x6, 0x2, 0x0, 0x3, 0xC, 0xE, 0xF, 0x7}
+
+	// InvSBox 逆半字节代替盒
+	InvSBox = [16]uint16{0xA, 0x5, 0x9, 0xB, 0x1, 0x7, 0x8, 0xF, 0x6, 0x0, 0x2, 0x3, 0xC, 0x4, 0xD, 0xE}
+
+	// RCON 轮常数
+	RCON = [2]uint16{0x80, 0x30}
+)
+
+// gfMul GF(2^4) 乘法
+func gfMul(a, b uint16) uint16 {
+	var p uint16
+	for b > 0 {
+		if b&1 != 0 {
+			p ^= a
+		}
+		a <<= 1
+		if a&0x10 != 0 {
+			a ^= 0x13 // x^4 + x + 1
+		}
+		b >>= 1
+	}
+	return p & 0xF
+}
+
+// subNibbles 对每个半字节查表代替
+func subNibbles(s uint16, box [16]uint16) uint16 {
+	return box[s>>12]<<12 | box[s>>8&0xF]<<8 | box[s>>4&0xF]<<4 | box[s&0xF]
+}
+
+// shiftRows 交换第二行的两个半字节（第 2 与第 4 个半字节），其逆运算相同
+func shiftRows(s uint16) uint16 {
+	return s&0xF0F0 | s&0x0F00>>8 | s&0x000F<<8
+}
+
+// mixColumns 每列左乘矩阵 [[a, b], [b, a]]
+// 加密使用 [[1, 4], [4, 1]]，解密使用 [[9, 2], [2, 9]]
+func mixColumns(s uint16, a, b uint16) uint16 {
+	n0, n1, n2, n3 := s>>12, s>>8&0xF, s>>4&0xF, s&0xF
+	return (gfMul(a, n0)^gfMul(b, n1))<<12 |
+		(gfMul(b, n0)^gfMul(a, n1))<<8 |
+		(gfMul(a, n2)^gfMul(b, n3))<<4 |
+		(gfMul(b, n2) ^ gfMul(a, n3))
+}
+
+// g 密钥扩展中的 g 函数：半字节循环移位、S 盒代替、异或轮常数
+func g(w uint16, rcon uint16) uint16 {
+	rotated := w<<4&0xF0 | w>>4
+	return (SBox[rotated>>4]<<4 | SBox[rotated&0xF]) ^ rcon
+}
+
+// KeyExpansionWords 由 16 位密钥生成三个 16 位轮密钥 K0、K1、K2
+func KeyExpansionWords(key uint16) [3]uint16 {
+	w0, w1 := key>>8, key&0xFF
+	w2 := w0 ^ g(w1, RCON[0])
+	w3 := w2 ^ w1
+	w4 := w2 ^ g(w3, RCON[1])
+	w5 := w4 ^ w3
+	return [3]uint16{w0<<8 | w1, w2<<8 | w3, w4<<8 | w5}
+}
+
+// KeyExpansion 由 16 位密钥生成三个 16 位轮密钥（位数组形式）
+func KeyExpansion(key []int) ([]int, []int, []int) {
+	k := KeyExpansionWords(uint16(utils.BitsToInt(key)))
+	return utils.IntToBits(int(k[0]), 16), utils.IntToBits(int(k[1]), 16), utils.IntToBits(int(k[2]), 16)
+}
+
+// EncryptWord 加密一个 16 位分组
+func EncryptWord(plaintext, key uint16) uint16 {
+	s, _ := encryptTrace(plaintext, key, false)
+	return s
+}
+
+// DecryptWord 解密一个 16 位分组
+func DecryptWord(ciphertext, key uint16) uint16 {
+	s, _ := decryptTrace(ciphertext, key, false)
+	return s
+}
+
+func encryptTrace(s, key uint16, trace bool) (uint16, []utils.TraceStep) {
+	k := KeyExpansionWords(key)
+	var steps []utils.TraceStep
+	record := func(name string, v uint16) {
+		if trace {
+			steps = append(steps, utils.TraceStep{Name: name, Value: wordString(v)})
+		}
+	}
+	record("K0", k[0])
+	record("K1", k[1])
+	record("K2", k[2])
+
+	// 轮密钥加 K0
+	s ^= k[0]
+	record("AddRoundKey0", s)
+
+	// 第一轮：半字节代替、行移位、列混淆、轮密钥加
+	s = subNibbles(s, SBox)
+	record("R1.SubNibbles", s)
+	s = shiftRows(s)
+	record("R1.ShiftRows", s)
+	s = mixColumns(s, 1, 4)
+	record("R1.MixColumns", s)
+	s ^= k[1]
+	record("R1.AddRoundKey", s)
+
+	// 第二轮：不做列混淆
+	s = subNibbles(s, SBox)
+	record("R2.SubNibbles", s)
+	s = shiftRows(s)
+	record("R2.ShiftRows", s)
+	s ^= k[2]
+	record("R2.AddRoundKey", s)
+	return s, steps
+}
+
+func decryptTrace(s, key uint16, trace bool) (uint16, []utils.TraceStep) {
+	k := KeyExpansionWords(key)
+	var steps []utils.TraceStep
+	record := func(name string, v uint16) {
+		if trace {
+			steps = append(steps, utils.TraceStep{Name: name, Value: wordString(v)})
+		}
+	}
+	record("K0", k[0])
+	record("K1", k[1])
+	record("K2", k[2])
+
+	s ^= k[2]
+	record("AddRoundKey2", s)
+
+	// 第一轮逆运算：逆行移位、逆半字节代替、轮密钥加、逆列混淆
+	s = shiftRows(s)
+	record("R1.InvShiftRows", s)
+	s = subNibbles(s, InvSBox)
+	record("R1.InvSubNibbles", s)
+	s ^= k[1]
+	record("R1.AddRoundKey", s)
+	s = mixColumns(s, 9, 2)
+	record("R1.InvMixColumns", s)
+
+	// 第二轮逆运算
+	s = shiftRows(s)
+	record("R2.InvShiftRows", s)
+	s = subNibbles(s, InvSBox)
+	record("R2.InvSubNibbles", s)
+	s ^= k[0]
+	record("R2.AddRoundKey", s)
+	return s, steps
+}
+
+// wordString 将 16 位值格式化为二进制字符串
+func wordString(v uint16) string {
+	return fmt.Sprintf("%016b", v)
+}
+
+// Encrypt 加密算法（位数组接口，与 utils.Encrypt 一致）
+func Encrypt(plaintext []int, key []int) []int {
+	return utils.IntToBits(int(EncryptWord(uint16(utils.BitsToInt(plaintext)), uint16(utils.BitsToInt(key)))), BlockBits)
+}
+
+// Decrypt 解密算法
+func Decrypt(ciphertext []int, key []int) []int {
+	return utils.IntToBits(int(DecryptWord(uint16(utils.BitsToInt(ciphertext)), uint16(utils.BitsToInt(key)))), BlockBits)
+}
+
+// EncryptTrace 加密并记录每个中间步骤
+func EncryptTrace(plaintext []int, key []int) ([]int, []utils.TraceStep) {
+	s, steps := encryptTrace(uint16(utils.BitsToInt(plaintext)), uint16(utils.BitsToInt(key)), true)
+	return utils.IntToBits(int(s), BlockBits), steps
+}
+
+// DecryptTrace 解密并记录每个中间步骤
+func DecryptTrace(ciphertext []int, key []int) ([]int, []utils.TraceStep) {
+	s, steps := decryptTrace(uint16(utils.BitsToInt(ciphertext)), uint16(utils.BitsToInt(key)), true)
+	return utils.IntToBits(int(s), BlockBits), steps
+}
+
+// EncryptBytes 以 2 字节为一组加密，奇数长度时末尾补 0x00
+func EncryptBytes(plaintext []byte, key []int) []byte {
+	k := uint16(utils.BitsToInt(key))
+	if len(plaintext)%2 == 1 {
+		plaintext = append(append([]byte(nil), plaintext...), 0)
+	}
+	ciphertext := make([]byte, len(plaintext))
+	for i := 0; i < len(plaintext); i += 2 {
+		c := EncryptWord(uint16(plaintext[i])<<8|uint16(plaintext[i+1]), k)
+		ciphertext[i], ciphertext[i+1] = byte(c>>8), byte(c)
+	}
+	return ciphertext
+}
+
+// DecryptBytes 以 2 字节为一组解密，密文长度必须为偶数
+// 返回结果保留加密时补充的 0x00，由调用方决定是否去除
+func DecryptBytes(ciphertext []byte, key []int) ([]byte, error) {
+	if len(ciphertext)%2 != 0 {
+		return nil, errors.New("S-AES 密文长度必须是 2 字节的整数倍")
+	}
+	k := uint16(utils.BitsToInt(key))
+	plaintext := make([]byte, len(ciphertext))
+	for i := 0; i < len(ciphertext); i += 2 {
+		p := DecryptWord(uint16(ciphertext[i])<<8|uint16(ciphertext[i+1]), k)
+		plaintext[i], plaintext[i+1] = byte(p>>8), byte(p)
+	}
+	return plaintext, nil
+}
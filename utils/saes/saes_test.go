@@ -0,0 +1,43 @@
+package saes
+
+import (
+	"SDES/utils"
+	"testing"
+)
+
+// Stallings《密码编码学与网络安全》附录中的 S-AES 示例
+func TestSAES_TextbookVector(t *testing.T) {
+	key := utils.StringToBits("0100101011110101", KeyBits)
+	plaintext := utils.StringToBits("1101011100101000", BlockBits)
+
+	k0, k1, k2 := KeyExpansion(key)
+	if got := utils.BitsToString(k0); got != "0100101011110101" {
+		t.Errorf("K0: got %s", got)
+	}
+	if got := utils.BitsToString(k1); got != "1101110100101000" {
+		t.Errorf("K1: got %s", got)
+	}
+	if got := utils.BitsToString(k2); got != "1000011110101111" {
+		t.Errorf("K2: got %s", got)
+	}
+
+	ciphertext := Encrypt(plaintext, key)
+	if got := utils.BitsToString(ciphertext); got != "0010010011101100" {
+		t.Errorf("Ciphertext: got %s, want 0010010011101100", got)
+	}
+	if got := utils.BitsToString(Decrypt(ciphertext, key)); got != "1101011100101000" {
+		t.Errorf("Decrypt: got %s", got)
+	}
+}
+
+func TestSAES_Bytes(t *testing.T) {
+	key := utils.StringToBits("0100101011110101", KeyBits)
+	ciphertext := EncryptBytes([]byte("Iloveyou!"), key)
+	plaintext, err := DecryptBytes(ciphertext, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plaintext) != "Iloveyou!\x00" {
+		t.Errorf("DecryptBytes: got %q", plaintext)
+	}
+}
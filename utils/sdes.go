@@ -73,28 +73,10 @@ func XOR(a, b []int) []int {
 	return result
 }
 
-// KeyExpansion 密钥扩展算法
+// KeyExpansion 密钥扩展算法，返回 2 轮 S-DES 的子密钥 k1、k2（见 SDESKeySchedule）
 func KeyExpansion(key []int) ([]int, []int) {
-	// P10置换
-	p10Result := Permute(key, P10[:])
-
-	// 分成两个5位的部分
-	left5 := p10Result[0:5]
-	right5 := p10Result[5:10]
-
-	// 生成k1：左移1位
-	left51 := LeftShift(left5, 1)
-	right51 := LeftShift(right5, 1)
-	combined1 := append(left51, right51...)
-	k1 := Permute(combined1, P8[:])
-
-	// 生成k2：再左移1位（总共左移2位）
-	left52 := LeftShift(left51, 1)
-	right52 := LeftShift(right51, 1)
-	combined2 := append(left52, right52...)
-	k2 := Permute(combined2, P8[:])
-
-	return k1, k2
+	subkeys := SDES.Subkeys(key)
+	return subkeys[0], subkeys[1]
 }
 
 // SBoxSubstitution S盒替换
@@ -125,17 +107,26 @@ func SBoxSubstitution(input []int) []int {
 
 // FFunction f函数
 func FFunction(right4 []int, subkey []int) []int {
+	return SDESRound(right4, subkey, nil)
+}
+
+// SDESRound f 函数，记录 EP、XOR、SBox、P4 四个中间值；作为 Feistel 实例的轮函数
+func SDESRound(right4 []int, subkey []int, trace Tracer) []int {
 	// 扩展置换
 	expanded := Permute(right4, EP[:])
+	trace.emit("EP", expanded)
 
 	// 与子密钥异或
 	xorResult := XOR(expanded, subkey)
+	trace.emit("XOR", xorResult)
 
 	// S盒替换
 	sBoxResult := SBoxSubstitution(xorResult)
+	trace.emit("SBox", sBoxResult)
 
 	// P4置换
 	p4Result := Permute(sBoxResult, SPBox[:])
+	trace.emit("P4", p4Result)
 
 	return p4Result
 }
@@ -148,66 +139,14 @@ func Swap(input []int) []int {
 	return result
 }
 
-// Encrypt 加密算法
+// Encrypt 加密算法，由 2 轮 Feistel 实例 SDES 执行：IP、f_k1、SW、f_k2、IP^-1
 func Encrypt(plaintext []int, key []int) []int {
-	// 密钥扩展
-	k1, k2 := KeyExpansion(key)
-
-	// 初始置换 IP
-	ipResult := Permute(plaintext, IP[:])
-
-	// 第一轮
-	left4 := ipResult[0:4]
-	right4 := ipResult[4:8]
-	fResult1 := FFunction(right4, k1)
-	newLeft := XOR(left4, fResult1)
-	round1Result := append(newLeft, right4...)
-
-	// 交换 SW
-	swapped := Swap(round1Result)
-
-	// 第二轮
-	left4 = swapped[0:4]
-	right4 = swapped[4:8]
-	fResult2 := FFunction(right4, k2)
-	newLeft = XOR(left4, fResult2)
-	round2Result := append(newLeft, right4...)
-
-	// 逆初始置换 IP^-1
-	ciphertext := Permute(round2Result, IPInverse[:])
-
-	return ciphertext
+	return SDES.Encrypt(plaintext, key)
 }
 
-// Decrypt 解密算法
+// Decrypt 解密算法，子密钥顺序相反（先 k2 后 k1）
 func Decrypt(ciphertext []int, key []int) []int {
-	// 密钥扩展（注意解密时密钥顺序相反）
-	k1, k2 := KeyExpansion(key)
-
-	// 初始置换 IP
-	ipResult := Permute(ciphertext, IP[:])
-
-	// 第一轮（使用k2）
-	left4 := ipResult[0:4]
-	right4 := ipResult[4:8]
-	fResult1 := FFunction(right4, k2)
-	newLeft := XOR(left4, fResult1)
-	round1Result := append(newLeft, right4...)
-
-	// 交换 SW
-	swapped := Swap(round1Result)
-
-	// 第二轮（使用k1）
-	left4 = swapped[0:4]
-	right4 = swapped[4:8]
-	fResult2 := FFunction(right4, k1)
-	newLeft = XOR(left4, fResult2)
-	round2Result := append(newLeft, right4...)
-
-	// 逆初始置换 IP^-1
-	plaintext := Permute(round2Result, IPInverse[:])
-
-	return plaintext
+	return SDES.Decrypt(ciphertext, key)
 }
 
 // StringToBits 辅助函数：将字符串转换为位数组
@@ -0,0 +1,262 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+)
+
+// 随机性统计检验
+// 参考 FIPS 140-1 与 NIST SP 800-22，显著性水平 α = 0.01
+
+// StatAlpha 显著性水平
+const StatAlpha = 0.01
+
+// StatResult 单项检验结果
+type StatResult struct {
+	Name      string
+	Statistic float64
+	PValue    float64
+	Passed    bool
+	Detail    string
+}
+
+// StatReport 完整检验报告
+type StatReport struct {
+	Bits    int
+	Period  int // 检测到的最短字节周期，0 表示未发现
+	Results []StatResult
+	Passed  int
+	Total   int
+}
+
+// bytesToBitSlice 将字节序列展开为位序列（高位在前）
+func bytesToBitSlice(data []byte) []int {
+	bits := make([]int, 0, len(data)*8)
+	for _, b := range data {
+		bits = append(bits, ByteToBits(b)...)
+	}
+	return bits
+}
+
+// normalP 标准正态分布双侧 p 值
+func normalP(z float64) float64 {
+	return math.Erfc(math.Abs(z) / math.Sqrt2)
+}
+
+// chiSquareP 卡方分布上侧 p 值
+func chiSquareP(x float64, df int) float64 {
+	return igamc(float64(df)/2, x/2)
+}
+
+// igamc 正则化上不完全伽马函数 Q(a, x)
+func igamc(a, x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	lgam, _ := math.Lgamma(a)
+	if x < a+1 {
+		// 级数展开求 P(a, x)
+		sum, term := 1/a, 1/a
+		for n := 1; n < 500; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*1e-15 {
+				break
+			}
+		}
+		return 1 - sum*math.Exp(-x+a*math.Log(x)-lgam)
+	}
+	// 连分式（Lentz 算法）求 Q(a, x)
+	const tiny = 1e-300
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1; i < 500; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return math.Exp(-x+a*math.Log(x)-lgam) * h
+}
+
+func newStatResult(name string, statistic, p float64, detail string) StatResult {
+	return StatResult{
+		Name:      name,
+		Statistic: statistic,
+		PValue:    p,
+		Passed:    p >= StatAlpha,
+		Detail:    detail,
+	}
+}
+
+// MonobitTest 单比特频数检验
+func MonobitTest(bits []int) StatResult {
+	n := len(bits)
+	ones := 0
+	for _, b := range bits {
+		ones += b
+	}
+	s := float64(2*ones-n) / math.Sqrt(float64(n))
+	return newStatResult("monobit", s, normalP(s), fmt.Sprintf("ones=%d zeros=%d", ones, n-ones))
+}
+
+// RunsTest 游程检验（NIST SP 800-22 2.3）
+func RunsTest(bits []int) StatResult {
+	n := len(bits)
+	ones := 0
+	for _, b := range bits {
+		ones += b
+	}
+	pi := float64(ones) / float64(n)
+	if math.Abs(pi-0.5) >= 2/math.Sqrt(float64(n)) {
+		return newStatResult("runs", 0, 0, "未通过前置频数检验")
+	}
+
+	runs := 1
+	for i := 1; i < n; i++ {
+		if bits[i] != bits[i-1] {
+			runs++
+		}
+	}
+	expected := 2 * float64(n) * pi * (1 - pi)
+	z := (float64(runs) - expected) / (2 * math.Sqrt(2*float64(n)) * pi * (1 - pi))
+	// NIST 定义的 p 值为 erfc(|V - 2nπ(1-π)| / (2√(2n)π(1-π)))
+	p := math.Erfc(math.Abs(z))
+	return newStatResult("runs", float64(runs), p, fmt.Sprintf("runs=%d expected=%.1f", runs, expected))
+}
+
+// PokerTest 扑克检验（FIPS 140-1，m = 4）
+func PokerTest(bits []int) StatResult {
+	const m = 4
+	k := len(bits) / m
+	if k == 0 {
+		return newStatResult("poker", 0, 0, "数据不足")
+	}
+	counts := make([]int, 1<<m)
+	for i := 0; i < k; i++ {
+		counts[BitsToInt(bits[i*m:(i+1)*m])]++
+	}
+	sum := 0.0
+	for _, f := range counts {
+		sum += float64(f * f)
+	}
+	x := float64(int(1)<<m)/float64(k)*sum - float64(k)
+	return newStatResult("poker", x, chiSquareP(x, 1<<m-1), fmt.Sprintf("blocks=%d", k))
+}
+
+// SerialTest 二元序列检验（Menezes 5.4.4）
+func SerialTest(bits []int) StatResult {
+	n := len(bits)
+	if n < 2 {
+		return newStatResult("serial", 0, 0, "数据不足")
+	}
+	var n0, n1 float64
+	for _, b := range bits {
+		if b == 1 {
+			n1++
+		} else {
+			n0++
+		}
+	}
+	var pairs [4]float64
+	for i := 0; i+1 < n; i++ {
+		pairs[bits[i]<<1|bits[i+1]]++
+	}
+	sum := 0.0
+	for _, c := range pairs {
+		sum += c * c
+	}
+	x := 4/float64(n-1)*sum - 2/float64(n)*(n0*n0+n1*n1) + 1
+	return newStatResult("serial", x, chiSquareP(x, 2), "")
+}
+
+// AutocorrelationTest 自相关检验，d 为位移
+func AutocorrelationTest(bits []int, d int) StatResult {
+	name := fmt.Sprintf("autocorrelation(d=%d)", d)
+	n := len(bits)
+	if d <= 0 || n-d <= 0 {
+		return newStatResult(name, 0, 0, "数据不足")
+	}
+	a := 0
+	for i := 0; i+d < n; i++ {
+		a += bits[i] ^ bits[i+d]
+	}
+	z := 2 * (float64(a) - float64(n-d)/2) / math.Sqrt(float64(n-d))
+	return newStatResult(name, z, normalP(z), fmt.Sprintf("A(d)=%d", a))
+}
+
+// DetectPeriod 检测字节序列的最短周期，序列长度不足两个周期时返回 0
+// 最短周期 p 满足 data[i] == data[i+p]，等于 n 减去最长的真前后缀（border）长度，
+// 用 KMP 前缀函数在 O(n) 内求出
+func DetectPeriod(data []byte) int {
+	n := len(data)
+	if n < 2 {
+		return 0
+	}
+	border := make([]int, n)
+	for i := 1; i < n; i++ {
+		k := border[i-1]
+		for k > 0 && data[i] != data[k] {
+			k = border[k-1]
+		}
+		if data[i] == data[k] {
+			k++
+		}
+		border[i] = k
+	}
+	if p := n - border[n-1]; p <= n/2 {
+		return p
+	}
+	return 0
+}
+
+// RunStatTests 对字节序列运行全部检验
+func RunStatTests(data []byte) StatReport {
+	bits := bytesToBitSlice(data)
+	report := StatReport{
+		Bits:   len(bits),
+		Period: DetectPeriod(data),
+	}
+	if len(bits) == 0 {
+		return report
+	}
+
+	report.Results = []StatResult{
+		MonobitTest(bits),
+		RunsTest(bits),
+		PokerTest(bits),
+		SerialTest(bits),
+		AutocorrelationTest(bits, 1),
+		AutocorrelationTest(bits, 8),
+	}
+
+	periodResult := StatResult{Name: "period", Passed: report.Period == 0, PValue: 1}
+	if report.Period > 0 {
+		periodResult.PValue = 0
+		periodResult.Statistic = float64(report.Period)
+		periodResult.Detail = fmt.Sprintf("输出以 %d 字节为周期重复", report.Period)
+	}
+	report.Results = append(report.Results, periodResult)
+
+	for _, r := range report.Results {
+		if r.Passed {
+			report.Passed++
+		}
+	}
+	report.Total = len(report.Results)
+	return report
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math"
+	"strings"
+	"testing"
+)
+
+func bitString(s string) []int {
+	s = strings.ReplaceAll(s, " ", "")
+	return StringToBits(s, len(s))
+}
+
+func near(got, want, tol float64) bool {
+	return math.Abs(got-want) <= tol
+}
+
+// NIST SP 800-22 2.1.8 与 2.3.8 的示例
+func TestNISTExamples(t *testing.T) {
+	if r := MonobitTest(bitString("1011010101")); !near(r.Statistic, 0.632456, 1e-6) || !near(r.PValue, 0.527089, 1e-6) {
+		t.Errorf("monobit: %+v", r)
+	}
+	if r := RunsTest(bitString("1001101011")); r.Statistic != 7 || !near(r.PValue, 0.147232, 1e-6) {
+		t.Errorf("runs: %+v", r)
+	}
+}
+
+// Menezes《应用密码学手册》例 5.31 的序列（40 位重复 4 次）
+func TestMenezesExample(t *testing.T) {
+	bits := bitString(strings.Repeat("11100 01100 01000 10100 11101 11100 10010 01001", 4))
+	// 单比特检验统计量 X1 = (n0 - n1)² / n = 0.4
+	if r := MonobitTest(bits); !near(r.Statistic*r.Statistic, 0.4, 1e-9) || r.Detail != "ones=76 zeros=84" {
+		t.Errorf("monobit: %+v", r)
+	}
+	if r := SerialTest(bits); !near(r.Statistic, 0.6252, 1e-4) || !r.Passed {
+		t.Errorf("serial: %+v", r)
+	}
+	if r := AutocorrelationTest(bits, 8); !near(r.Statistic, 3.8933, 1e-4) || r.Detail != "A(d)=100" || r.Passed {
+		t.Errorf("autocorrelation: %+v", r)
+	}
+}
+
+func TestPokerTest(t *testing.T) {
+	// 16 种 4 位组合各出现一次，X = 0
+	var all []int
+	for v := 0; v < 16; v++ {
+		all = append(all, IntToBits(v, 4)...)
+	}
+	if r := PokerTest(all); r.Statistic != 0 || !near(r.PValue, 1, 1e-9) {
+		t.Errorf("均匀: %+v", r)
+	}
+	// 全 0：X = 16/16·16² − 16 = 240
+	if r := PokerTest(make([]int, 64)); r.Statistic != 240 || r.Passed {
+		t.Errorf("全 0: %+v", r)
+	}
+}
+
+func TestDetectPeriod(t *testing.T) {
+	cases := []struct {
+		data string
+		want int
+	}{
+		{"", 0},
+		{"a", 0},
+		{"aaaa", 1},
+		{"abcabcabc", 3},
+		{"abcabca", 3},
+		{"abcab", 0}, // 不足两个周期
+		{"abcdefgh", 0},
+		{"abaababaab", 5},
+	}
+	for _, tc := range cases {
+		if got := DetectPeriod([]byte(tc.data)); got != tc.want {
+			t.Errorf("DetectPeriod(%q) = %d，应为 %d", tc.data, got, tc.want)
+		}
+	}
+	// 最大长度下线性时间完成
+	long := bytes.Repeat([]byte{1, 2, 3, 4, 5, 6, 7}, 65536/7)
+	if got := DetectPeriod(append(long, 9)); got != 0 {
+		t.Errorf("末尾破坏周期后 DetectPeriod = %d", got)
+	}
+	if got := DetectPeriod(long); got != 7 {
+		t.Errorf("DetectPeriod = %d，应为 7", got)
+	}
+}
+
+func TestCryptoRandPasses(t *testing.T) {
+	data := make([]byte, 8192)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	report := RunStatTests(data)
+	if report.Period != 0 || report.Bits != len(data)*8 {
+		t.Fatalf("报告: %+v", report)
+	}
+	// α = 0.01，真随机数据偶尔会有一项不通过
+	if report.Passed < report.Total-2 {
+		t.Errorf("crypto/rand 只通过 %d/%d 项: %+v", report.Passed, report.Total, report.Results)
+	}
+}
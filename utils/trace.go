@@ -0,0 +1,48 @@
+package utils
+
+// TraceStep 加解密过程中的一个中间步骤
+type TraceStep struct {
+	Name  string
+	Value string
+}
+
+// Tracer 接收 Feistel 引擎、轮函数与密钥扩展产生的中间值，为 nil 时不记录
+type Tracer func(name string, bits []int)
+
+func (t Tracer) emit(name string, bits []int) {
+	if t != nil {
+		t(name, bits)
+	}
+}
+
+// prefixed 为步骤名加上前缀，如 R1.
+func (t Tracer) prefixed(prefix string) Tracer {
+	if t == nil {
+		return nil
+	}
+	return func(name string, bits []int) {
+		t(prefix+name, bits)
+	}
+}
+
+// stepRecorder 返回把中间值追加到 steps 的 Tracer
+func stepRecorder(steps *[]TraceStep) Tracer {
+	return func(name string, bits []int) {
+		*steps = append(*steps, TraceStep{Name: name, Value: BitsToString(bits)})
+	}
+}
+
+// EncryptTrace 加密并记录每个中间步骤，步骤名形如 k1、IP、R1.EP、SW、IP^-1
+// 由执行加密的同一 Feistel 实例 SDES 产生
+func EncryptTrace(plaintext []int, key []int) ([]int, []TraceStep) {
+	var steps []TraceStep
+	out := SDES.EncryptTrace(plaintext, key, stepRecorder(&steps))
+	return out, steps
+}
+
+// DecryptTrace 解密并记录每个中间步骤，子密钥顺序与加密相反
+func DecryptTrace(ciphertext []int, key []int) ([]int, []TraceStep) {
+	var steps []TraceStep
+	out := SDES.DecryptTrace(ciphertext, key, stepRecorder(&steps))
+	return out, steps
+}